@@ -22,6 +22,7 @@ type Cafe struct {
 	TokenSecret string
 	ReferralKey string
 	NodeVersion string
+	Notifier    Notifier
 	server      *http.Server
 }
 
@@ -32,6 +33,11 @@ func (c *Cafe) Start(addr string) {
 	cdao.Dao.Connect()
 	cdao.Dao.Index()
 
+	// default to logging push notifications
+	if c.Notifier == nil {
+		c.Notifier = &LogNotifier{}
+	}
+
 	// setup router
 	router := gin.Default()
 	router.GET("/", func(g *gin.Context) {
@@ -55,6 +61,13 @@ func (c *Cafe) Start(addr string) {
 		v0.GET("/referrals", c.listReferrals)
 
 		v0.POST("/pin", c.pin)
+
+		v0.POST("/push", c.addPushToken)
+		v0.POST("/messages", c.storeMessage)
+
+		v0.PUT("/directory", c.publishDirectoryEntry)
+		v0.DELETE("/directory", c.removeDirectoryEntry)
+		v0.GET("/directory", c.findDirectoryEntry)
 	}
 	c.server = &http.Server{
 		Addr:    addr,
@@ -8,6 +8,7 @@ import (
 	"github.com/textileio/textile-go/cafe/models"
 	"log"
 	"net"
+	"time"
 )
 
 type DAO struct {
@@ -23,10 +24,16 @@ var Dao *DAO
 var db *mgo.Database
 
 const (
-	userCollection     = "users"
-	referralCollection = "referrals"
+	userCollection      = "users"
+	referralCollection  = "referrals"
+	pushTokenCollection = "push_tokens"
+	messageCollection   = "messages"
+	directoryCollection = "directory"
 )
 
+// messageTTL is how long offline messages are kept before mongo expires them
+const messageTTL = time.Hour * 24 * 7
+
 var indexes = map[string][]mgo.Index{
 	userCollection: {
 		{
@@ -55,6 +62,45 @@ var indexes = map[string][]mgo.Index{
 			Background: true,
 		},
 	},
+	pushTokenCollection: {
+		{
+			Key:        []string{"token"},
+			Unique:     true,
+			DropDups:   true,
+			Background: true,
+		},
+		{
+			Key:        []string{"peer_id"},
+			Background: true,
+		},
+	},
+	messageCollection: {
+		{
+			Key:        []string{"peer_id"},
+			Background: true,
+		},
+		{
+			Key:        []string{"user_id", "peer_id", "created"},
+			Background: true,
+		},
+		{
+			Key:         []string{"created"},
+			Background:  true,
+			ExpireAfter: messageTTL,
+		},
+	},
+	directoryCollection: {
+		{
+			Key:        []string{"user_id"},
+			Unique:     true,
+			DropDups:   true,
+			Background: true,
+		},
+		{
+			Key:        []string{"username"},
+			Background: true,
+		},
+	},
 }
 
 func (m *DAO) Index() {
@@ -173,3 +219,84 @@ func (m *DAO) UpdateUser(user models.User) error {
 	err := db.C(userCollection).UpdateId(user.ID, &user)
 	return err
 }
+
+// PUSH TOKENS
+
+// Insert or update a push token, keeping the id and created date of an existing one
+func (m *DAO) UpsertPushToken(token models.PushToken) error {
+	_, err := db.C(pushTokenCollection).Upsert(bson.M{"token": token.Token}, bson.M{
+		"$set": bson.M{
+			"user_id":  token.UserId,
+			"peer_id":  token.PeerId,
+			"pk":       token.PubKey,
+			"sig":      token.Sig,
+			"platform": token.Platform,
+		},
+		"$setOnInsert": bson.M{
+			"_id":     bson.NewObjectId(),
+			"created": time.Now(),
+		},
+	})
+	return err
+}
+
+// Find push tokens by peer id
+func (m *DAO) FindPushTokensByPeerId(pid string) ([]models.PushToken, error) {
+	var tokens []models.PushToken
+	err := db.C(pushTokenCollection).Find(bson.M{"peer_id": pid}).All(&tokens)
+	return tokens, err
+}
+
+// Delete an existing push token
+func (m *DAO) DeletePushToken(token models.PushToken) error {
+	err := db.C(pushTokenCollection).Remove(&token)
+	return err
+}
+
+// MESSAGES
+
+// Insert a new offline message
+func (m *DAO) InsertMessage(msg models.OfflineMessage) error {
+	err := db.C(messageCollection).Insert(&msg)
+	return err
+}
+
+// Count messages a user sent to a peer since a given time
+func (m *DAO) CountMessages(uid string, pid string, since time.Time) (int, error) {
+	return db.C(messageCollection).Find(bson.M{
+		"user_id": uid,
+		"peer_id": pid,
+		"created": bson.M{"$gt": since},
+	}).Count()
+}
+
+// DIRECTORY
+
+// Insert or replace a user's directory entry
+func (m *DAO) UpsertDirectoryEntry(entry models.DirectoryEntry) error {
+	_, err := db.C(directoryCollection).Upsert(bson.M{"user_id": entry.UserId}, &entry)
+	return err
+}
+
+// Find a visible directory entry by username
+func (m *DAO) FindDirectoryEntryByUsername(un string) (models.DirectoryEntry, error) {
+	var entry models.DirectoryEntry
+	err := db.C(directoryCollection).Find(bson.M{
+		"username":   un,
+		"visibility": bson.M{"$ne": models.DirectoryHidden},
+	}).One(&entry)
+	return entry, err
+}
+
+// Find a directory entry by user id, regardless of visibility
+func (m *DAO) FindDirectoryEntryByUserId(uid string) (models.DirectoryEntry, error) {
+	var entry models.DirectoryEntry
+	err := db.C(directoryCollection).Find(bson.M{"user_id": uid}).One(&entry)
+	return entry, err
+}
+
+// Delete a user's directory entry
+func (m *DAO) DeleteDirectoryEntry(uid string) error {
+	err := db.C(directoryCollection).Remove(bson.M{"user_id": uid})
+	return err
+}
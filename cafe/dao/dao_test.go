@@ -38,6 +38,15 @@ var user = models.User{
 	},
 }
 
+var pushToken = models.PushToken{
+	ID:       bson.NewObjectId(),
+	UserId:   user.ID.Hex(),
+	PeerId:   ksuid.New().String(),
+	Token:    ksuid.New().String(),
+	Platform: models.APNS,
+	Created:  now,
+}
+
 func TestDao_Connect(t *testing.T) {
 	d.Hosts = os.Getenv("CAFE_DB_HOSTS")
 	d.Name = os.Getenv("CAFE_DB_NAME")
@@ -208,3 +217,72 @@ func TestDAO_DeleteReferral(t *testing.T) {
 		t.Error("ref deleted, but found")
 	}
 }
+
+func TestDAO_UpsertPushToken(t *testing.T) {
+	if err := d.UpsertPushToken(pushToken); err != nil {
+		t.Errorf("upsert push token failed: %s", err)
+		return
+	}
+	if err := d.UpsertPushToken(pushToken); err != nil {
+		t.Errorf("upsert push token again failed: %s", err)
+	}
+}
+
+func TestDAO_FindPushTokensByPeerId(t *testing.T) {
+	tokens, err := d.FindPushTokensByPeerId(pushToken.PeerId)
+	if err != nil {
+		t.Errorf("find push tokens by peer id failed: %s", err)
+		return
+	}
+	if len(tokens) != 1 {
+		t.Error("incorrect number of push tokens")
+	}
+}
+
+func TestDAO_DeletePushToken(t *testing.T) {
+	err := d.DeletePushToken(pushToken)
+	if err != nil {
+		t.Errorf("delete push token failed: %s", err)
+		return
+	}
+	tokens, err := d.FindPushTokensByPeerId(pushToken.PeerId)
+	if err != nil {
+		t.Errorf("find push tokens by peer id again failed: %s", err)
+		return
+	}
+	if len(tokens) != 0 {
+		t.Error("push token deleted, but found")
+	}
+}
+
+func TestDAO_InsertMessage(t *testing.T) {
+	msg := models.OfflineMessage{
+		ID:        bson.NewObjectId(),
+		UserId:    user.ID.Hex(),
+		PeerId:    pushToken.PeerId,
+		MessageId: ksuid.New().String(),
+		Created:   now,
+	}
+	if err := d.InsertMessage(msg); err != nil {
+		t.Errorf("insert message failed: %s", err)
+	}
+}
+
+func TestDAO_CountMessages(t *testing.T) {
+	count, err := d.CountMessages(user.ID.Hex(), pushToken.PeerId, now.Add(-time.Minute))
+	if err != nil {
+		t.Errorf("count messages failed: %s", err)
+		return
+	}
+	if count != 1 {
+		t.Errorf("incorrect number of messages: %d", count)
+	}
+	count, err = d.CountMessages(user.ID.Hex(), pushToken.PeerId, now)
+	if err != nil {
+		t.Errorf("count messages again failed: %s", err)
+		return
+	}
+	if count != 0 {
+		t.Error("counted messages outside the window")
+	}
+}
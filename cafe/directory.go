@@ -0,0 +1,152 @@
+package cafe
+
+import (
+	"encoding/base64"
+	"github.com/gin-gonic/gin"
+	"github.com/globalsign/mgo"
+	"github.com/globalsign/mgo/bson"
+	"github.com/textileio/textile-go/cafe/dao"
+	"github.com/textileio/textile-go/cafe/middleware"
+	"github.com/textileio/textile-go/cafe/models"
+	"gx/ipfs/QmZoWKhxUmZ2seW4BzX6fJkNR8hh9PsGModr7q171yq2SS/go-libp2p-peer"
+	libp2pc "gx/ipfs/QmaPbCnUMBohSGo3KnxEa2bHqyJVVeEEcwtqJAYxerieBo/go-libp2p-crypto"
+	"net/http"
+	"time"
+)
+
+func (c *Cafe) publishDirectoryEntry(g *gin.Context) {
+	var entry models.DirectoryEntry
+	if err := g.BindJSON(&entry); err != nil {
+		g.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	switch entry.Visibility {
+	case models.DirectoryHidden, models.DirectoryUsername, models.DirectoryIdentities:
+	default:
+		g.JSON(http.StatusBadRequest, gin.H{"error": "invalid visibility"})
+		return
+	}
+
+	// entries are always under the session user's name
+	user, err := dao.Dao.FindUserById(g.GetString(middleware.SubjectKey))
+	if err != nil {
+		g.JSON(http.StatusNotFound, gin.H{"error": "user not found"})
+		return
+	}
+	entry.Username = user.Username
+
+	// the peer must prove it holds the key
+	if !VerifyDirectoryEntry(&entry) {
+		g.JSON(http.StatusForbidden, gin.H{"error": "invalid peer signature"})
+		return
+	}
+
+	// keep the document id stable across updates
+	if existing, err := dao.Dao.FindDirectoryEntryByUserId(user.ID.Hex()); err == nil {
+		entry.ID = existing.ID
+	} else {
+		entry.ID = bson.NewObjectId()
+	}
+	entry.UserId = user.ID.Hex()
+	entry.Updated = time.Now()
+	if err := dao.Dao.UpsertDirectoryEntry(entry); err != nil {
+		g.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	// ship it
+	g.JSON(http.StatusCreated, models.DirectoryResponse{
+		Response: models.Response{Status: http.StatusCreated},
+		Entry:    &entry,
+	})
+}
+
+func (c *Cafe) removeDirectoryEntry(g *gin.Context) {
+	if err := dao.Dao.DeleteDirectoryEntry(g.GetString(middleware.SubjectKey)); err != nil {
+		g.JSON(http.StatusNotFound, gin.H{"error": "entry not found"})
+		return
+	}
+	g.JSON(http.StatusOK, models.Response{
+		Status: http.StatusOK,
+	})
+}
+
+func (c *Cafe) findDirectoryEntry(g *gin.Context) {
+	// lookups are exact-match only, there is no listing or prefix search
+	var entry models.DirectoryEntry
+	var err error
+	if username := g.Query("username"); username != "" {
+		entry, err = dao.Dao.FindDirectoryEntryByUsername(username)
+	} else if value := g.Query("identity_value"); value != "" {
+		entry, err = findDirectoryEntryByIdentity(models.Identity{
+			Type:  models.IdentityType(g.Query("identity_type")),
+			Value: value,
+		})
+	} else {
+		g.JSON(http.StatusBadRequest, gin.H{"error": "missing username or identity"})
+		return
+	}
+	if err != nil {
+		// don't reveal whether a hidden user exists
+		g.JSON(http.StatusNotFound, gin.H{"error": "not found"})
+		return
+	}
+
+	// ship it
+	g.JSON(http.StatusOK, models.DirectoryResponse{
+		Response: models.Response{Status: http.StatusOK},
+		Entry:    &entry,
+	})
+}
+
+// findDirectoryEntryByIdentity only matches verified identities of users who opted in
+func findDirectoryEntryByIdentity(id models.Identity) (models.DirectoryEntry, error) {
+	user, err := dao.Dao.FindUserByIdentity(id)
+	if err != nil {
+		return models.DirectoryEntry{}, err
+	}
+	var verified bool
+	for _, ident := range user.Identities {
+		if ident.Type == id.Type && ident.Value == id.Value {
+			verified = ident.Verified
+		}
+	}
+	if !verified {
+		return models.DirectoryEntry{}, mgo.ErrNotFound
+	}
+	entry, err := dao.Dao.FindDirectoryEntryByUserId(user.ID.Hex())
+	if err != nil {
+		return models.DirectoryEntry{}, err
+	}
+	if entry.Visibility != models.DirectoryIdentities {
+		return models.DirectoryEntry{}, mgo.ErrNotFound
+	}
+	return entry, nil
+}
+
+// VerifyDirectoryEntry checks that the entry's key matches its peer id and signed the entry
+func VerifyDirectoryEntry(entry *models.DirectoryEntry) bool {
+	return verifyPeerSignature(entry.PeerId, entry.PubKey, entry.Sig, entry.SigningBytes())
+}
+
+// verifyPeerSignature checks that an encoded public key matches a peer id and signed data
+func verifyPeerSignature(peerId string, pubKey string, encodedSig string, data []byte) bool {
+	pkb, err := libp2pc.ConfigDecodeKey(pubKey)
+	if err != nil {
+		return false
+	}
+	pk, err := libp2pc.UnmarshalPublicKey(pkb)
+	if err != nil {
+		return false
+	}
+	id, err := peer.IDFromPublicKey(pk)
+	if err != nil || id.Pretty() != peerId {
+		return false
+	}
+	sig, err := base64.StdEncoding.DecodeString(encodedSig)
+	if err != nil {
+		return false
+	}
+	ok, err := pk.Verify(data, sig)
+	return err == nil && ok
+}
@@ -0,0 +1,138 @@
+package cafe
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"github.com/segmentio/ksuid"
+	"github.com/textileio/textile-go/cafe/models"
+	client "github.com/textileio/textile-go/core/cafe"
+	util "github.com/textileio/textile-go/util/testing"
+	"gx/ipfs/QmZoWKhxUmZ2seW4BzX6fJkNR8hh9PsGModr7q171yq2SS/go-libp2p-peer"
+	libp2pc "gx/ipfs/QmaPbCnUMBohSGo3KnxEa2bHqyJVVeEEcwtqJAYxerieBo/go-libp2p-crypto"
+	"net/url"
+	"testing"
+)
+
+var dirRegistration = map[string]interface{}{
+	"username": ksuid.New().String(),
+	"password": ksuid.New().String(),
+	"identity": map[string]string{
+		"type":  "email_address",
+		"value": fmt.Sprintf("%s@textile.io", ksuid.New().String()),
+	},
+	"ref_code": "canihaz?",
+}
+var dirSession *models.Session
+var dirUrl = fmt.Sprintf("%s/api/v0/directory", util.CafeAddr)
+
+func TestDirectory_Setup(t *testing.T) {
+	ref, err := util.CreateReferral(util.CafeReferralKey, 1, 1, "test")
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if len(ref.RefCodes) == 0 {
+		t.Error("got bad ref codes")
+		return
+	}
+	dirRegistration["ref_code"] = ref.RefCodes[0]
+	stat, res, err := util.SignUp(dirRegistration)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if stat != 201 {
+		t.Errorf("got bad status: %d", stat)
+		return
+	}
+	dirSession = res.Session
+}
+
+func TestDirectory_Publish(t *testing.T) {
+	entry, sk, err := newDirectoryEntry(models.DirectoryUsername)
+	if err != nil {
+		t.Fatal(err)
+	}
+	entry.Sig = base64.StdEncoding.EncodeToString([]byte("bogus"))
+	res, err := client.PublishDirectoryEntry(dirSession.AccessToken, entry, dirUrl)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Error == nil {
+		t.Error("publish with bad signature should fail")
+	}
+
+	sig, err := sk.Sign(entry.SigningBytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	entry.Sig = base64.StdEncoding.EncodeToString(sig)
+	res, err = client.PublishDirectoryEntry(dirSession.AccessToken, entry, dirUrl)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Status != 201 {
+		t.Errorf("got bad status: %d", res.Status)
+	}
+}
+
+func TestDirectory_Find(t *testing.T) {
+	query := url.Values{}
+	query.Set("username", dirRegistration["username"].(string))
+	res, err := client.FindDirectoryEntry(dirSession.AccessToken, query, dirUrl)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Entry == nil {
+		t.Fatal("entry not found")
+	}
+	if !VerifyDirectoryEntry(res.Entry) {
+		t.Error("found entry does not verify")
+	}
+}
+
+func TestDirectory_Hidden(t *testing.T) {
+	entry, sk, err := newDirectoryEntry(models.DirectoryHidden)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sig, err := sk.Sign(entry.SigningBytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	entry.Sig = base64.StdEncoding.EncodeToString(sig)
+	if _, err := client.PublishDirectoryEntry(dirSession.AccessToken, entry, dirUrl); err != nil {
+		t.Fatal(err)
+	}
+	query := url.Values{}
+	query.Set("username", entry.Username)
+	res, err := client.FindDirectoryEntry(dirSession.AccessToken, query, dirUrl)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Entry != nil {
+		t.Error("hidden entry should not be found")
+	}
+}
+
+func newDirectoryEntry(visibility models.DirectoryVisibility) (*models.DirectoryEntry, libp2pc.PrivKey, error) {
+	sk, pk, err := libp2pc.GenerateEd25519Key(rand.Reader)
+	if err != nil {
+		return nil, nil, err
+	}
+	pkb, err := pk.Bytes()
+	if err != nil {
+		return nil, nil, err
+	}
+	id, err := peer.IDFromPublicKey(pk)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &models.DirectoryEntry{
+		Username:   dirRegistration["username"].(string),
+		PeerId:     id.Pretty(),
+		PubKey:     libp2pc.ConfigEncodeKey(pkb),
+		Visibility: visibility,
+	}, sk, nil
+}
@@ -6,16 +6,25 @@ import (
 	"github.com/gin-gonic/gin"
 )
 
+// SubjectKey is the context key holding the authenticated user id
+const SubjectKey = "subject"
+
 func Auth(secret string) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		if c.Request.URL.Path == "/api/v0/users" || c.Request.URL.Path == "/api/v0/referrals" {
 			return
 		}
-		_, err := request.ParseFromRequest(c.Request, request.OAuth2Extractor, func(token *jwt.Token) (interface{}, error) {
+		token, err := request.ParseFromRequest(c.Request, request.OAuth2Extractor, func(token *jwt.Token) (interface{}, error) {
 			return []byte(secret), nil
 		})
 		if err != nil {
 			c.AbortWithError(401, err)
+			return
+		}
+		if claims, ok := token.Claims.(jwt.MapClaims); ok {
+			if sub, ok := claims["sub"].(string); ok {
+				c.Set(SubjectKey, sub)
+			}
 		}
 	}
 }
@@ -0,0 +1,45 @@
+package models
+
+import (
+	"github.com/globalsign/mgo/bson"
+	"io"
+	"time"
+)
+
+// DirectoryVisibility controls how a user can be found in the directory
+type DirectoryVisibility string
+
+const (
+	// DirectoryHidden users are never returned from lookups
+	DirectoryHidden DirectoryVisibility = "hidden"
+	// DirectoryUsername users can be found by exact username
+	DirectoryUsername DirectoryVisibility = "username"
+	// DirectoryIdentities users can also be found by their verified identities
+	DirectoryIdentities DirectoryVisibility = "identities"
+)
+
+// DirectoryEntry maps a username to the peer that published it
+type DirectoryEntry struct {
+	ID         bson.ObjectId       `bson:"_id" json:"-"`
+	UserId     string              `bson:"user_id" json:"-"`
+	Username   string              `bson:"username" json:"username"`
+	PeerId     string              `bson:"peer_id" json:"peer_id" binding:"required"`
+	PubKey     string              `bson:"pk" json:"pk" binding:"required"`
+	Sig        string              `bson:"sig" json:"sig,omitempty" binding:"required"`
+	Visibility DirectoryVisibility `bson:"visibility" json:"visibility" binding:"required"`
+	Updated    time.Time           `bson:"updated" json:"updated"`
+}
+
+type DirectoryResponse struct {
+	Response
+	Entry *DirectoryEntry `json:"entry,omitempty"`
+}
+
+func (r *DirectoryResponse) Read(body io.ReadCloser) error {
+	return unmarshalJson(body, r)
+}
+
+// SigningBytes returns the bytes a peer signs to prove it owns an entry
+func (e *DirectoryEntry) SigningBytes() []byte {
+	return []byte(e.Username + ":" + e.PeerId + ":" + string(e.Visibility))
+}
@@ -0,0 +1,38 @@
+package models
+
+import (
+	"github.com/globalsign/mgo/bson"
+	"time"
+)
+
+type PushPlatform string
+
+const (
+	APNS PushPlatform = "apns"
+	FCM  PushPlatform = "fcm"
+)
+
+type PushToken struct {
+	ID       bson.ObjectId `bson:"_id" json:"id"`
+	UserId   string        `bson:"user_id" json:"user_id"`
+	PeerId   string        `bson:"peer_id" json:"peer_id" binding:"required"`
+	PubKey   string        `bson:"pk" json:"pk" binding:"required"`
+	Sig      string        `bson:"sig" json:"sig,omitempty" binding:"required"`
+	Token    string        `bson:"token" json:"token" binding:"required"`
+	Platform PushPlatform  `bson:"platform" json:"platform" binding:"required"`
+	Created  time.Time     `bson:"created" json:"created"`
+}
+
+// SigningBytes returns the bytes a peer signs to prove it owns a push token
+func (t *PushToken) SigningBytes() []byte {
+	return []byte(t.Token + ":" + t.PeerId + ":" + string(t.Platform))
+}
+
+type OfflineMessage struct {
+	ID        bson.ObjectId `bson:"_id" json:"id"`
+	UserId    string        `bson:"user_id" json:"-"`
+	PeerId    string        `bson:"peer_id" json:"peer_id" binding:"required"`
+	MessageId string        `bson:"message_id" json:"message_id" binding:"required"`
+	Payload   string        `bson:"payload" json:"payload"`
+	Created   time.Time     `bson:"created" json:"created"`
+}
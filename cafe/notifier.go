@@ -0,0 +1,19 @@
+package cafe
+
+import (
+	"github.com/textileio/textile-go/cafe/models"
+)
+
+// Notifier delivers push notifications to a user's devices
+type Notifier interface {
+	Notify(token models.PushToken, payload string) error
+}
+
+// LogNotifier only logs notifications, useful for testing
+type LogNotifier struct{}
+
+// Notify logs the token and encrypted payload
+func (n *LogNotifier) Notify(token models.PushToken, payload string) error {
+	log.Infof("push notification to %s (%s): %s", token.PeerId, token.Platform, payload)
+	return nil
+}
@@ -0,0 +1,106 @@
+package cafe
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/globalsign/mgo/bson"
+	"github.com/textileio/textile-go/cafe/dao"
+	"github.com/textileio/textile-go/cafe/middleware"
+	"github.com/textileio/textile-go/cafe/models"
+	"net/http"
+	"time"
+)
+
+// maxMessagesPerWindow is how many messages a user can store for one peer per window
+const maxMessagesPerWindow = 100
+
+// messageRateWindow is the period message rates are counted over
+const messageRateWindow = time.Hour
+
+func (c *Cafe) addPushToken(g *gin.Context) {
+	var token models.PushToken
+	if err := g.BindJSON(&token); err != nil {
+		g.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	switch token.Platform {
+	case models.APNS, models.FCM:
+	default:
+		g.JSON(http.StatusBadRequest, gin.H{"error": "invalid platform"})
+		return
+	}
+
+	// the peer must prove it holds the key, otherwise anyone could
+	// receive another peer's notifications
+	if !VerifyPushToken(&token) {
+		g.JSON(http.StatusForbidden, gin.H{"error": "invalid peer signature"})
+		return
+	}
+
+	// tie the token to the session user
+	token.UserId = g.GetString(middleware.SubjectKey)
+	if err := dao.Dao.UpsertPushToken(token); err != nil {
+		g.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	// ship it
+	g.JSON(http.StatusCreated, models.Response{
+		Status: http.StatusCreated,
+	})
+}
+
+// VerifyPushToken checks that the token's key matches its peer id and signed the token
+func VerifyPushToken(token *models.PushToken) bool {
+	return verifyPeerSignature(token.PeerId, token.PubKey, token.Sig, token.SigningBytes())
+}
+
+func (c *Cafe) storeMessage(g *gin.Context) {
+	var msg models.OfflineMessage
+	if err := g.BindJSON(&msg); err != nil {
+		g.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// any user can wake any peer, so cap how often one user can wake the same peer
+	msg.UserId = g.GetString(middleware.SubjectKey)
+	count, err := dao.Dao.CountMessages(msg.UserId, msg.PeerId, time.Now().Add(-messageRateWindow))
+	if err != nil {
+		g.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if count >= maxMessagesPerWindow {
+		g.JSON(http.StatusTooManyRequests, gin.H{"error": "too many messages for peer"})
+		return
+	}
+	msg.ID = bson.NewObjectId()
+	msg.Created = time.Now()
+	if err := dao.Dao.InsertMessage(msg); err != nil {
+		g.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	// wake up the recipient's devices
+	if msg.Payload != "" {
+		go c.notify(msg)
+	}
+
+	// ship it
+	id := msg.ID.Hex()
+	g.JSON(http.StatusCreated, models.Response{
+		Status: http.StatusCreated,
+		Id:     &id,
+	})
+}
+
+func (c *Cafe) notify(msg models.OfflineMessage) {
+	tokens, err := dao.Dao.FindPushTokensByPeerId(msg.PeerId)
+	if err != nil {
+		log.Errorf("error finding push tokens for %s: %s", msg.PeerId, err)
+		return
+	}
+	for _, token := range tokens {
+		if err := c.Notifier.Notify(token, msg.Payload); err != nil {
+			log.Errorf("error notifying %s: %s", token.PeerId, err)
+		}
+	}
+}
@@ -0,0 +1,151 @@
+package cafe
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"github.com/segmentio/ksuid"
+	"github.com/textileio/textile-go/cafe/models"
+	util "github.com/textileio/textile-go/util/testing"
+	"gx/ipfs/QmZoWKhxUmZ2seW4BzX6fJkNR8hh9PsGModr7q171yq2SS/go-libp2p-peer"
+	libp2pc "gx/ipfs/QmaPbCnUMBohSGo3KnxEa2bHqyJVVeEEcwtqJAYxerieBo/go-libp2p-crypto"
+	"testing"
+)
+
+var pushRegistration = map[string]interface{}{
+	"username": ksuid.New().String(),
+	"password": ksuid.New().String(),
+	"identity": map[string]string{
+		"type":  "email_address",
+		"value": fmt.Sprintf("%s@textile.io", ksuid.New().String()),
+	},
+	"ref_code": "canihaz?",
+}
+var pushSession *models.Session
+var pushPeerId = ksuid.New().String()
+
+func TestPush_Setup(t *testing.T) {
+	ref, err := util.CreateReferral(util.CafeReferralKey, 1, 1, "test")
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if len(ref.RefCodes) == 0 {
+		t.Error("got bad ref codes")
+		return
+	}
+	pushRegistration["ref_code"] = ref.RefCodes[0]
+	stat, res, err := util.SignUp(pushRegistration)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if stat != 201 {
+		t.Errorf("got bad status: %d", stat)
+		return
+	}
+	pushSession = res.Session
+}
+
+func TestPush_AddPushToken(t *testing.T) {
+	sk, pk, err := libp2pc.GenerateEd25519Key(rand.Reader)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	pkb, err := pk.Bytes()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	id, err := peer.IDFromPublicKey(pk)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	pushPeerId = id.Pretty()
+	token := &models.PushToken{
+		PeerId:   pushPeerId,
+		PubKey:   libp2pc.ConfigEncodeKey(pkb),
+		Token:    ksuid.New().String(),
+		Platform: "bogus",
+	}
+	stat, err := util.AddPushToken(signPushToken(token, sk), pushSession.AccessToken)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if stat != 400 {
+		t.Errorf("bad status from add push token with bad platform: %d", stat)
+		return
+	}
+	token.Platform = models.APNS
+	stat, err = util.AddPushToken(signPushToken(token, sk), pushSession.AccessToken)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if stat != 201 {
+		t.Errorf("got bad status: %d", stat)
+		return
+	}
+
+	// re-registering the same token should update it
+	stat, err = util.AddPushToken(signPushToken(token, sk), pushSession.AccessToken)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if stat != 201 {
+		t.Errorf("got bad status re-registering token: %d", stat)
+	}
+}
+
+func TestPush_AddPushTokenBadSig(t *testing.T) {
+	sk, _, err := libp2pc.GenerateEd25519Key(rand.Reader)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	signed := signPushToken(&models.PushToken{
+		PeerId:   pushPeerId,
+		PubKey:   "bogus",
+		Token:    ksuid.New().String(),
+		Platform: models.APNS,
+	}, sk)
+	stat, err := util.AddPushToken(signed, pushSession.AccessToken)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if stat != 403 {
+		t.Errorf("bad status from add push token for another peer: %d", stat)
+	}
+}
+
+func TestPush_StoreMessage(t *testing.T) {
+	msg := map[string]string{
+		"peer_id":    pushPeerId,
+		"message_id": ksuid.New().String(),
+		"payload":    "ciphertext",
+	}
+	stat, err := util.StoreMessage(msg, pushSession.AccessToken)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if stat != 201 {
+		t.Errorf("got bad status: %d", stat)
+	}
+}
+
+func signPushToken(token *models.PushToken, sk libp2pc.PrivKey) map[string]string {
+	sig, _ := sk.Sign(token.SigningBytes())
+	return map[string]string{
+		"peer_id":  token.PeerId,
+		"pk":       token.PubKey,
+		"sig":      base64.StdEncoding.EncodeToString(sig),
+		"token":    token.Token,
+		"platform": string(token.Platform),
+	}
+}
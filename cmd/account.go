@@ -0,0 +1,27 @@
+package cmd
+
+import (
+	"fmt"
+	"github.com/fatih/color"
+	"github.com/textileio/textile-go/core"
+	"gopkg.in/abiosoft/ishell.v2"
+)
+
+func RecoverAccount(c *ishell.Context) {
+	if !core.Node.Wallet.IsOnline() {
+		c.Println("not online yet")
+		return
+	}
+	result, err := core.Node.Wallet.Recover()
+	if err != nil {
+		c.Err(err)
+		return
+	}
+
+	green := color.New(color.FgHiGreen).SprintFunc()
+	c.Println(green(fmt.Sprintf("recovered %d threads and %d devices from backup (%s)",
+		result.Threads, result.Devices, result.Created.Format("2006-01-02 15:04"))))
+	if result.Username != "" {
+		c.Println(fmt.Sprintf("sign in to your cafe as '%s' to restore your username", result.Username))
+	}
+}
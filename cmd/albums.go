@@ -0,0 +1,168 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"github.com/fatih/color"
+	"github.com/textileio/textile-go/core"
+	"github.com/textileio/textile-go/wallet/thread"
+	"gopkg.in/abiosoft/ishell.v2"
+	"strings"
+)
+
+func ListAlbums(c *ishell.Context) {
+	if len(c.Args) == 0 {
+		c.Err(errors.New("missing thread id"))
+		return
+	}
+	threadId := c.Args[0]
+
+	_, thrd := core.Node.Wallet.GetThread(threadId)
+	if thrd == nil {
+		c.Err(errors.New(fmt.Sprintf("could not find thread: %s", threadId)))
+		return
+	}
+
+	albums := thrd.Albums("", -1)
+	if len(albums) == 0 {
+		c.Println(fmt.Sprintf("no albums found in: %s", threadId))
+	} else {
+		c.Println(fmt.Sprintf("found %v albums in: %s", len(albums), threadId))
+	}
+
+	blue := color.New(color.FgHiBlue).SprintFunc()
+	for _, album := range albums {
+		name, err := thrd.Decrypt(album.DataCaptionCipher)
+		if err != nil {
+			c.Err(err)
+			return
+		}
+		c.Println(blue(fmt.Sprintf("name: %s, id: %s", string(name), album.Id)))
+	}
+}
+
+func AddAlbum(c *ishell.Context) {
+	if len(c.Args) == 0 {
+		c.Err(errors.New("missing thread id"))
+		return
+	}
+	if len(c.Args) == 1 {
+		c.Err(errors.New("missing album name"))
+		return
+	}
+	threadId := c.Args[0]
+	name := strings.Join(c.Args[1:], " ")
+
+	_, thrd := core.Node.Wallet.GetThread(threadId)
+	if thrd == nil {
+		c.Err(errors.New(fmt.Sprintf("could not find thread: %s", threadId)))
+		return
+	}
+
+	addr, err := thrd.AddAlbum(name)
+	if err != nil {
+		c.Err(err)
+		return
+	}
+
+	cyan := color.New(color.FgCyan).SprintFunc()
+	c.Println(cyan(fmt.Sprintf("added album %s with name %s", addr.B58String(), name)))
+}
+
+func ListAlbumPhotos(c *ishell.Context) {
+	if len(c.Args) == 0 {
+		c.Err(errors.New("missing album id"))
+		return
+	}
+	albumId := c.Args[0]
+
+	thrd, err := getThreadForAlbum(albumId)
+	if err != nil {
+		c.Err(err)
+		return
+	}
+
+	blocks, err := thrd.AlbumPhotos(albumId, "", -1)
+	if err != nil {
+		c.Err(err)
+		return
+	}
+	if len(blocks) == 0 {
+		c.Println(fmt.Sprintf("no photos found in: %s", albumId))
+	} else {
+		c.Println(fmt.Sprintf("found %v photos in: %s", len(blocks), albumId))
+	}
+
+	magenta := color.New(color.FgHiMagenta).SprintFunc()
+	for _, block := range blocks {
+		c.Println(magenta(fmt.Sprintf("id: %s, block: %s", block.DataId, block.Id)))
+	}
+}
+
+func AddAlbumPhoto(c *ishell.Context) {
+	if len(c.Args) == 0 {
+		c.Err(errors.New("missing album id"))
+		return
+	}
+	if len(c.Args) == 1 {
+		c.Err(errors.New("missing photo id"))
+		return
+	}
+	albumId := c.Args[0]
+	photoId := c.Args[1]
+
+	thrd, err := getThreadForAlbum(albumId)
+	if err != nil {
+		c.Err(err)
+		return
+	}
+
+	addr, err := thrd.AddToAlbum(albumId, photoId)
+	if err != nil {
+		c.Err(err)
+		return
+	}
+
+	green := color.New(color.FgHiGreen).SprintFunc()
+	c.Println(green(fmt.Sprintf("ok, added %s to album. added block %s.", photoId, addr.B58String())))
+}
+
+func RemoveAlbumPhoto(c *ishell.Context) {
+	if len(c.Args) == 0 {
+		c.Err(errors.New("missing album id"))
+		return
+	}
+	if len(c.Args) == 1 {
+		c.Err(errors.New("missing photo id"))
+		return
+	}
+	albumId := c.Args[0]
+	photoId := c.Args[1]
+
+	thrd, err := getThreadForAlbum(albumId)
+	if err != nil {
+		c.Err(err)
+		return
+	}
+
+	addr, err := thrd.RemoveFromAlbum(albumId, photoId)
+	if err != nil {
+		c.Err(err)
+		return
+	}
+
+	red := color.New(color.FgHiRed).SprintFunc()
+	c.Println(red(fmt.Sprintf("removed %s from album. added block %s.", photoId, addr.B58String())))
+}
+
+func getThreadForAlbum(albumId string) (*thread.Thread, error) {
+	block, err := core.Node.Wallet.GetBlock(albumId)
+	if err != nil {
+		return nil, err
+	}
+	_, thrd := core.Node.Wallet.GetThread(block.ThreadId)
+	if thrd == nil {
+		return nil, errors.New(fmt.Sprintf("could not find thread %s", block.ThreadId))
+	}
+	return thrd, nil
+}
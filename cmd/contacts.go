@@ -0,0 +1,105 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"github.com/fatih/color"
+	"github.com/textileio/textile-go/core"
+	"gopkg.in/abiosoft/ishell.v2"
+	libp2pc "gx/ipfs/QmaPbCnUMBohSGo3KnxEa2bHqyJVVeEEcwtqJAYxerieBo/go-libp2p-crypto"
+)
+
+func ListContacts(c *ishell.Context) {
+	contacts := core.Node.Wallet.Contacts()
+	if len(contacts) == 0 {
+		c.Println("no contacts found")
+	} else {
+		c.Println(fmt.Sprintf("found %v contacts", len(contacts)))
+	}
+
+	blue := color.New(color.FgHiBlue).SprintFunc()
+	for _, contact := range contacts {
+		username := contact.Username
+		if username == "" {
+			username = "unknown"
+		}
+		c.Println(blue(fmt.Sprintf("username: %s, id: %s, last seen: %s",
+			username, contact.Id, contact.LastSeen.Format("2006-01-02 15:04"))))
+	}
+}
+
+func AddContact(c *ishell.Context) {
+	if len(c.Args) == 0 {
+		c.Err(errors.New("missing contact pub key"))
+		return
+	}
+	pk, err := decodePubKey(c.Args[0])
+	if err != nil {
+		c.Err(err)
+		return
+	}
+
+	contact, err := core.Node.Wallet.AddContact(pk)
+	if err != nil {
+		c.Err(err)
+		return
+	}
+
+	cyan := color.New(color.FgCyan).SprintFunc()
+	c.Println(cyan(fmt.Sprintf("added contact %s", contact.Id)))
+}
+
+func FindContact(c *ishell.Context) {
+	if len(c.Args) == 0 {
+		c.Err(errors.New("missing username"))
+		return
+	}
+	contact, err := core.Node.Wallet.FindContact(c.Args[0])
+	if err != nil {
+		c.Err(err)
+		return
+	}
+
+	cyan := color.New(color.FgCyan).SprintFunc()
+	c.Println(cyan(fmt.Sprintf("found %s, id: %s", contact.Username, contact.Id)))
+}
+
+func RefreshContact(c *ishell.Context) {
+	if len(c.Args) == 0 {
+		c.Err(errors.New("missing contact id"))
+		return
+	}
+	contact, err := core.Node.Wallet.RefreshContact(c.Args[0])
+	if err != nil {
+		c.Err(err)
+		return
+	}
+
+	cyan := color.New(color.FgCyan).SprintFunc()
+	c.Println(cyan(fmt.Sprintf("username: %s, avatar: %s", contact.Username, contact.AvatarId)))
+}
+
+func RemoveContact(c *ishell.Context) {
+	if len(c.Args) == 0 {
+		c.Err(errors.New("missing contact id"))
+		return
+	}
+	id := c.Args[0]
+
+	if err := core.Node.Wallet.RemoveContact(id); err != nil {
+		c.Err(err)
+		return
+	}
+
+	red := color.New(color.FgHiRed).SprintFunc()
+	c.Println(red(fmt.Sprintf("removed contact %s", id)))
+}
+
+// decodePubKey decodes a base64 encoded public key
+func decodePubKey(pks string) (libp2pc.PubKey, error) {
+	pkb, err := libp2pc.ConfigDecodeKey(pks)
+	if err != nil {
+		return nil, err
+	}
+	return libp2pc.UnmarshalPublicKey(pkb)
+}
@@ -56,6 +56,41 @@ func AddDevice(c *ishell.Context) {
 	c.Println(cyan(fmt.Sprintf("added device '%s'", name)))
 }
 
+func NewPairingRequest(c *ishell.Context) {
+	req, err := core.Node.Wallet.NewPairingRequest()
+	if err != nil {
+		c.Err(err)
+		return
+	}
+
+	cyan := color.New(color.FgCyan).SprintFunc()
+	c.Println(cyan(fmt.Sprintf("pairing code: %s", req.Code)))
+	c.Println(cyan(fmt.Sprintf("link: %s", req.Link)))
+	c.Println(cyan(fmt.Sprintf("expires: %s", req.Expires.Format("15:04:05"))))
+}
+
+func PairDevice(c *ishell.Context) {
+	if len(c.Args) == 0 {
+		c.Err(errors.New("missing pairing link"))
+		return
+	}
+	link := c.Args[0]
+	if len(c.Args) == 1 {
+		c.Err(errors.New("missing device name"))
+		return
+	}
+	name := c.Args[1]
+
+	dev, err := core.Node.Wallet.PairDevice(link, name)
+	if err != nil {
+		c.Err(err)
+		return
+	}
+
+	cyan := color.New(color.FgCyan).SprintFunc()
+	c.Println(cyan(fmt.Sprintf("paired device '%s' (%s)", dev.Name, dev.Id)))
+}
+
 func RemoveDevice(c *ishell.Context) {
 	if len(c.Args) == 0 {
 		c.Err(errors.New("missing device id"))
@@ -8,11 +8,13 @@ import (
 	"github.com/mitchellh/go-homedir"
 	"github.com/textileio/textile-go/core"
 	"github.com/textileio/textile-go/repo"
+	"github.com/textileio/textile-go/wallet"
 	"github.com/textileio/textile-go/wallet/thread"
 	"gopkg.in/abiosoft/ishell.v2"
 	"io/ioutil"
 	"os"
 	"path/filepath"
+	"strings"
 )
 
 func AddPhoto(c *ishell.Context) {
@@ -50,6 +52,10 @@ func AddPhoto(c *ishell.Context) {
 		c.Err(err)
 		return
 	}
+	if added.Duplicate {
+		yellow := color.New(color.FgHiYellow).SprintFunc()
+		c.Println(yellow(fmt.Sprintf("already added as %s, reusing it.", added.Id)))
+	}
 
 	// add to thread
 	_, thrd := core.Node.Wallet.GetThread(threadId)
@@ -68,6 +74,51 @@ func AddPhoto(c *ishell.Context) {
 	c.Println(cyan(fmt.Sprintf("added photo %s to %s. added block %s.", added.Id, thrd.Id, addr.B58String())))
 }
 
+func ImportPhotos(c *ishell.Context) {
+	var dir, threadId string
+	for i := 0; i < len(c.Args); i++ {
+		if c.Args[i] == "--thread" && i+1 < len(c.Args) {
+			threadId = c.Args[i+1]
+			i++
+			continue
+		}
+		dir = c.Args[i]
+	}
+	if dir == "" {
+		c.Err(errors.New("missing photo directory"))
+		return
+	}
+	if threadId == "" {
+		c.Err(errors.New("missing thread id (--thread)"))
+		return
+	}
+
+	// try to get path with home dir tilda
+	path, err := homedir.Expand(dir)
+	if err != nil {
+		path = dir
+	}
+
+	red := color.New(color.FgHiRed).SprintFunc()
+	result, err := core.Node.Wallet.ImportPhotos(path, threadId, func(prog *wallet.ImportProgress) {
+		switch {
+		case prog.Error != "":
+			c.Println(red(fmt.Sprintf("[%d/%d] error adding %s: %s", prog.Done, prog.Total, prog.Path, prog.Error)))
+		case prog.Skipped:
+			c.Println(fmt.Sprintf("[%d/%d] skipped %s", prog.Done, prog.Total, prog.Path))
+		default:
+			c.Println(fmt.Sprintf("[%d/%d] added %s", prog.Done, prog.Total, prog.Path))
+		}
+	})
+	if err != nil {
+		c.Err(err)
+		return
+	}
+
+	cyan := color.New(color.FgCyan).SprintFunc()
+	c.Println(cyan(fmt.Sprintf("imported %d photos, skipped %d, %d errors.", result.Added, result.Skipped, len(result.Errors))))
+}
+
 func SharePhoto(c *ishell.Context) {
 	if len(c.Args) == 0 {
 		c.Err(errors.New("missing photo id"))
@@ -261,6 +312,63 @@ func IgnorePhoto(c *ishell.Context) {
 	c.Println(red(fmt.Sprintf("ok, sent ignore for %s via %s", block.Id, addr.B58String())))
 }
 
+func UnignorePhoto(c *ishell.Context) {
+	if len(c.Args) == 0 {
+		c.Err(errors.New("missing block id"))
+		return
+	}
+	id := c.Args[0]
+
+	block, err := core.Node.Wallet.GetBlock(id)
+	if err != nil {
+		c.Err(err)
+		return
+	}
+	_, thrd := core.Node.Wallet.GetThread(block.ThreadId)
+	if thrd == nil {
+		c.Err(errors.New(fmt.Sprintf("could not find thread %s", block.ThreadId)))
+		return
+	}
+
+	addr, err := thrd.Restore(block.Id)
+	if err != nil {
+		c.Err(err)
+		return
+	}
+
+	green := color.New(color.FgHiGreen).SprintFunc()
+	c.Println(green(fmt.Sprintf("ok, sent restore for %s via %s", block.Id, addr.B58String())))
+}
+
+func EditPhotoCaption(c *ishell.Context) {
+	if len(c.Args) == 0 {
+		c.Err(errors.New("missing block id"))
+		return
+	}
+	id := c.Args[0]
+	caption := strings.Join(c.Args[1:], " ")
+
+	block, err := core.Node.Wallet.GetBlock(id)
+	if err != nil {
+		c.Err(err)
+		return
+	}
+	_, thrd := core.Node.Wallet.GetThread(block.ThreadId)
+	if thrd == nil {
+		c.Err(errors.New(fmt.Sprintf("could not find thread %s", block.ThreadId)))
+		return
+	}
+
+	addr, err := thrd.EditCaption(block.Id, caption)
+	if err != nil {
+		c.Err(err)
+		return
+	}
+
+	green := color.New(color.FgHiGreen).SprintFunc()
+	c.Println(green(fmt.Sprintf("ok, sent caption for %s via %s", block.Id, addr.B58String())))
+}
+
 func getBlockAndThreadForDataId(dataId string) (*repo.Block, *thread.Thread, error) {
 	block, err := core.Node.Wallet.GetBlockByDataId(dataId)
 	if err != nil {
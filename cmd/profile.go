@@ -4,8 +4,12 @@ import (
 	"errors"
 	"fmt"
 	"github.com/fatih/color"
+	"github.com/mitchellh/go-homedir"
+	"github.com/textileio/textile-go/cafe/models"
 	"github.com/textileio/textile-go/core"
 	"gopkg.in/abiosoft/ishell.v2"
+	"sort"
+	"strings"
 )
 
 func PublishProfile(c *ishell.Context) {
@@ -71,6 +75,31 @@ func GetProfile(c *ishell.Context) {
 	if prof.AvatarId != "" {
 		c.Println(green(fmt.Sprintf("avatar_id: %s", prof.AvatarId)))
 	}
+	var keys []string
+	for key := range prof.Fields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		c.Println(green(fmt.Sprintf("%s: %s", key, prof.Fields[key])))
+	}
+}
+
+func SetProfileField(c *ishell.Context) {
+	if len(c.Args) == 0 {
+		c.Err(errors.New("missing field key"))
+		return
+	}
+	key := c.Args[0]
+	value := strings.Join(c.Args[1:], " ")
+
+	if err := core.Node.Wallet.SetProfileField(key, value); err != nil {
+		c.Err(err)
+		return
+	}
+
+	green := color.New(color.FgHiGreen).SprintFunc()
+	c.Println(green("ok, updated"))
 }
 
 func SetAvatarId(c *ishell.Context) {
@@ -88,3 +117,49 @@ func SetAvatarId(c *ishell.Context) {
 	green := color.New(color.FgHiGreen).SprintFunc()
 	c.Println(green("ok, updated"))
 }
+
+func SetAvatar(c *ishell.Context) {
+	if len(c.Args) == 0 {
+		c.Err(errors.New("missing image path"))
+		return
+	}
+	pth, err := homedir.Expand(c.Args[0])
+	if err != nil {
+		c.Err(err)
+		return
+	}
+
+	id, err := core.Node.Wallet.SetAvatar(pth)
+	if err != nil {
+		c.Err(err)
+		return
+	}
+
+	green := color.New(color.FgHiGreen).SprintFunc()
+	c.Println(green(fmt.Sprintf("ok, updated avatar: %s", id)))
+}
+
+func SetDirectoryVisibility(c *ishell.Context) {
+	if len(c.Args) == 0 {
+		c.Err(errors.New("missing visibility: hidden, username, identities, or leave"))
+		return
+	}
+	visibility := c.Args[0]
+
+	var err error
+	switch visibility {
+	case "leave":
+		err = core.Node.Wallet.LeaveDirectory()
+	case string(models.DirectoryHidden), string(models.DirectoryUsername), string(models.DirectoryIdentities):
+		err = core.Node.Wallet.SetDirectoryVisibility(models.DirectoryVisibility(visibility))
+	default:
+		err = errors.New(fmt.Sprintf("invalid visibility: %s", visibility))
+	}
+	if err != nil {
+		c.Err(err)
+		return
+	}
+
+	green := color.New(color.FgHiGreen).SprintFunc()
+	c.Println(green("ok, updated"))
+}
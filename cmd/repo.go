@@ -0,0 +1,37 @@
+package cmd
+
+import (
+	"errors"
+	"github.com/fatih/color"
+	"github.com/textileio/textile-go/core"
+	"gopkg.in/abiosoft/ishell.v2"
+)
+
+func ChangeRepoPassword(c *ishell.Context) {
+	if core.Node.Wallet.Started() {
+		c.Err(errors.New("node must be stopped first (run `stop`)"))
+		return
+	}
+	c.Print("current password: ")
+	current := c.ReadPassword()
+	c.Print("new password (empty to remove encryption): ")
+	next := c.ReadPassword()
+	c.Print("confirm new password: ")
+	confirm := c.ReadPassword()
+	if next != confirm {
+		c.Err(errors.New("passwords do not match"))
+		return
+	}
+
+	if err := core.Node.Wallet.ChangePassword(current, next); err != nil {
+		c.Err(err)
+		return
+	}
+
+	green := color.New(color.FgHiGreen).SprintFunc()
+	if next == "" {
+		c.Println(green("repo encryption removed"))
+		return
+	}
+	c.Println(green("repo password changed"))
+}
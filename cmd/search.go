@@ -0,0 +1,77 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"github.com/fatih/color"
+	"github.com/textileio/textile-go/core"
+	"github.com/textileio/textile-go/repo"
+	"gopkg.in/abiosoft/ishell.v2"
+	"strconv"
+	"strings"
+	"time"
+)
+
+func Search(c *ishell.Context) {
+	query, filters, err := parseSearchArgs(c.Args)
+	if err != nil {
+		c.Err(err)
+		return
+	}
+
+	results, err := core.Node.Wallet.Search(query, filters)
+	if err != nil {
+		c.Err(err)
+		return
+	}
+	if len(results) == 0 {
+		c.Println("no results found")
+		return
+	}
+	c.Println(fmt.Sprintf("found %v results", len(results)))
+
+	magenta := color.New(color.FgHiMagenta).SprintFunc()
+	for _, res := range results {
+		line := fmt.Sprintf("id: %s, block: %s, thread: %s", res.DataId, res.BlockId, res.ThreadId)
+		if res.Name != "" {
+			line += fmt.Sprintf(", name: %s", res.Name)
+		}
+		if res.Caption != "" {
+			line += fmt.Sprintf(", caption: %s", res.Caption)
+		}
+		c.Println(magenta(line))
+	}
+}
+
+// parseSearchArgs splits key:value filters (thread, author, after, before, limit)
+// from the free text query, dates are formatted as 2006-01-02
+func parseSearchArgs(args []string) (string, *repo.SearchFilters, error) {
+	filters := new(repo.SearchFilters)
+	var words []string
+	for _, arg := range args {
+		parts := strings.SplitN(arg, ":", 2)
+		if len(parts) != 2 {
+			words = append(words, arg)
+			continue
+		}
+		var err error
+		switch parts[0] {
+		case "thread":
+			filters.ThreadId = parts[1]
+		case "author":
+			filters.AuthorId = parts[1]
+		case "after":
+			filters.After, err = time.Parse("2006-01-02", parts[1])
+		case "before":
+			filters.Before, err = time.Parse("2006-01-02", parts[1])
+		case "limit":
+			filters.Limit, err = strconv.Atoi(parts[1])
+		default:
+			words = append(words, arg)
+		}
+		if err != nil {
+			return "", nil, errors.New(fmt.Sprintf("invalid %s filter: %s", parts[0], parts[1]))
+		}
+	}
+	return strings.Join(words, " "), filters, nil
+}
@@ -1,15 +1,19 @@
 package cmd
 
 import (
-	"crypto/rand"
 	"errors"
 	"fmt"
 	"github.com/fatih/color"
+	"github.com/mitchellh/go-homedir"
 	"github.com/textileio/textile-go/core"
 	"github.com/textileio/textile-go/util"
+	"github.com/textileio/textile-go/wallet"
 	"github.com/textileio/textile-go/wallet/thread"
 	"gopkg.in/abiosoft/ishell.v2"
-	libp2pc "gx/ipfs/QmaPbCnUMBohSGo3KnxEa2bHqyJVVeEEcwtqJAYxerieBo/go-libp2p-crypto"
+	mh "gx/ipfs/QmZyZDi491cCNTLfAhwcaDii2Kg4pwKRkhqQzURGDvY6ua/go-multihash"
+	"strconv"
+	"strings"
+	"time"
 )
 
 func ListThreads(c *ishell.Context) {
@@ -33,13 +37,7 @@ func AddThread(c *ishell.Context) {
 	}
 	name := c.Args[0]
 
-	sk, _, err := libp2pc.GenerateEd25519Key(rand.Reader)
-	if err != nil {
-		c.Err(err)
-		return
-	}
-
-	thrd, err := core.Node.Wallet.AddThread(name, sk)
+	thrd, err := core.Node.Wallet.AddDerivedThread(name)
 	if err != nil {
 		c.Err(err)
 		return
@@ -77,10 +75,10 @@ func ListThreadPeers(c *ishell.Context) {
 
 func AddThreadInvite(c *ishell.Context) {
 	if len(c.Args) == 0 {
-		c.Err(errors.New("missing peer pub key"))
+		c.Err(errors.New("missing peer pub key, contact id, or username"))
 		return
 	}
-	pks := c.Args[0]
+	invitee := c.Args[0]
 	if len(c.Args) == 1 {
 		c.Err(errors.New("missing thread id"))
 		return
@@ -93,35 +91,92 @@ func AddThreadInvite(c *ishell.Context) {
 		return
 	}
 
-	pkb, err := libp2pc.ConfigDecodeKey(pks)
+	// fallback to contacts if not a pub key
+	var addr mh.Multihash
+	var err error
+	pk, perr := decodePubKey(invitee)
+	if perr == nil {
+		addr, err = thrd.AddInvite(pk)
+	} else {
+		addr, err = core.Node.Wallet.InviteContact(id, invitee)
+	}
 	if err != nil {
 		c.Err(err)
 		return
 	}
-	pk, err := libp2pc.UnmarshalPublicKey(pkb)
+
+	green := color.New(color.FgHiGreen).SprintFunc()
+	c.Println(green(fmt.Sprintf("invite sent! added block %s.", addr.B58String())))
+}
+
+func AcceptThreadInvite(c *ishell.Context) {
+	if len(c.Args) == 0 {
+		c.Err(errors.New("missing invite address"))
+		return
+	}
+	blockId := c.Args[0]
+
+	addr, err := core.Node.Wallet.AcceptThreadInvite(blockId)
 	if err != nil {
 		c.Err(err)
 		return
 	}
 
-	addr, err := thrd.AddInvite(pk)
+	green := color.New(color.FgHiGreen).SprintFunc()
+	c.Println(green(fmt.Sprintf("ok, accepted. added block %s.", addr.B58String())))
+}
+
+func AddExternalThreadInvite(c *ishell.Context) {
+	if len(c.Args) == 0 {
+		c.Err(errors.New("missing thread id"))
+		return
+	}
+	id := c.Args[0]
+
+	// optional expiry (e.g., 24h) and max uses
+	var ttl time.Duration
+	var maxUses int
+	if len(c.Args) > 1 {
+		var err error
+		ttl, err = time.ParseDuration(c.Args[1])
+		if err != nil {
+			c.Err(errors.New(fmt.Sprintf("invalid expiry: %s", c.Args[1])))
+			return
+		}
+	}
+	if len(c.Args) > 2 {
+		var err error
+		maxUses, err = strconv.Atoi(c.Args[2])
+		if err != nil {
+			c.Err(errors.New(fmt.Sprintf("invalid max uses: %s", c.Args[2])))
+			return
+		}
+	}
+
+	invite, err := core.Node.Wallet.CreateExternalInvite(id, ttl, maxUses)
 	if err != nil {
 		c.Err(err)
 		return
 	}
 
 	green := color.New(color.FgHiGreen).SprintFunc()
-	c.Println(green(fmt.Sprintf("invite sent! added block %s.", addr.B58String())))
+	c.Println(green(fmt.Sprintf("added! link: %s", invite.Link)))
 }
 
-func AcceptThreadInvite(c *ishell.Context) {
+func AcceptExternalThreadInvite(c *ishell.Context) {
 	if len(c.Args) == 0 {
-		c.Err(errors.New("missing invite address"))
+		c.Err(errors.New("missing invite link or id"))
 		return
 	}
-	blockId := c.Args[0]
 
-	addr, err := core.Node.Wallet.AcceptThreadInvite(blockId)
+	// accept a link, or an id and key
+	var addr mh.Multihash
+	var err error
+	if len(c.Args) == 1 {
+		addr, err = core.Node.Wallet.AcceptExternalThreadInviteLink(c.Args[0])
+	} else {
+		addr, err = core.Node.Wallet.AcceptExternalThreadInvite(c.Args[0], []byte(c.Args[1]))
+	}
 	if err != nil {
 		c.Err(err)
 		return
@@ -131,49 +186,138 @@ func AcceptThreadInvite(c *ishell.Context) {
 	c.Println(green(fmt.Sprintf("ok, accepted. added block %s.", addr.B58String())))
 }
 
-func AddExternalThreadInvite(c *ishell.Context) {
+func RevokeExternalThreadInvite(c *ishell.Context) {
+	if len(c.Args) == 0 {
+		c.Err(errors.New("missing invite id"))
+		return
+	}
+	id := c.Args[0]
+
+	addr, err := core.Node.Wallet.RevokeExternalInvite(id)
+	if err != nil {
+		c.Err(err)
+		return
+	}
+
+	green := color.New(color.FgHiGreen).SprintFunc()
+	c.Println(green(fmt.Sprintf("ok, revoked. added block %s.", addr.B58String())))
+}
+
+func SyncThread(c *ishell.Context) {
 	if len(c.Args) == 0 {
 		c.Err(errors.New("missing thread id"))
 		return
 	}
 	id := c.Args[0]
 
-	_, thrd := core.Node.Wallet.GetThread(id)
-	if thrd == nil {
-		c.Err(errors.New(fmt.Sprintf("could not find thread: %s", id)))
+	count, err := core.Node.Wallet.SyncThread(id, func(prog *wallet.ThreadSyncProgress) {
+		if !prog.Done {
+			c.Println(fmt.Sprintf("fetched %d blocks from %s...", prog.Fetched, prog.PeerId))
+		}
+	})
+	if err != nil {
+		c.Err(err)
+		return
+	}
+
+	green := color.New(color.FgHiGreen).SprintFunc()
+	c.Println(green(fmt.Sprintf("ok, synced. fetched %d new blocks.", count)))
+}
+
+func RenameThread(c *ishell.Context) {
+	if len(c.Args) == 0 {
+		c.Err(errors.New("missing thread id"))
+		return
+	}
+	if len(c.Args) == 1 {
+		c.Err(errors.New("missing thread name"))
 		return
 	}
+	id := c.Args[0]
+	name := strings.Join(c.Args[1:], " ")
 
-	addr, key, err := thrd.AddExternalInvite()
+	addr, err := core.Node.Wallet.RenameThread(id, name)
 	if err != nil {
 		c.Err(err)
 		return
 	}
 
 	green := color.New(color.FgHiGreen).SprintFunc()
-	c.Println(green(fmt.Sprintf("added! creds: %s %s", addr.B58String(), string(key))))
+	c.Println(green(fmt.Sprintf("ok, renamed. added block %s.", addr.B58String())))
 }
 
-func AcceptExternalThreadInvite(c *ishell.Context) {
+func DescribeThread(c *ishell.Context) {
 	if len(c.Args) == 0 {
-		c.Err(errors.New("missing invite id"))
+		c.Err(errors.New("missing thread id"))
 		return
 	}
 	id := c.Args[0]
+	description := strings.Join(c.Args[1:], " ")
+
+	addr, err := core.Node.Wallet.DescribeThread(id, description)
+	if err != nil {
+		c.Err(err)
+		return
+	}
+
+	green := color.New(color.FgHiGreen).SprintFunc()
+	c.Println(green(fmt.Sprintf("ok, described. added block %s.", addr.B58String())))
+}
+
+func SetThreadCover(c *ishell.Context) {
+	if len(c.Args) == 0 {
+		c.Err(errors.New("missing thread id"))
+		return
+	}
 	if len(c.Args) == 1 {
-		c.Err(errors.New("missing invite key"))
+		c.Err(errors.New("missing photo block id"))
 		return
 	}
-	key := c.Args[1]
+	id := c.Args[0]
+	blockId := c.Args[1]
 
-	addr, err := core.Node.Wallet.AcceptExternalThreadInvite(id, []byte(key))
+	addr, err := core.Node.Wallet.SetThreadCover(id, blockId)
 	if err != nil {
 		c.Err(err)
 		return
 	}
 
 	green := color.New(color.FgHiGreen).SprintFunc()
-	c.Println(green(fmt.Sprintf("ok, accepted. added block %s.", addr.B58String())))
+	c.Println(green(fmt.Sprintf("ok, cover set. added block %s.", addr.B58String())))
+}
+
+func ExportThreadPhotos(c *ishell.Context) {
+	if len(c.Args) == 0 {
+		c.Err(errors.New("missing thread id"))
+		return
+	}
+	if len(c.Args) == 1 {
+		c.Err(errors.New("missing out directory"))
+		return
+	}
+	id := c.Args[0]
+
+	// try to get path with home dir tilda
+	dest, err := homedir.Expand(c.Args[1])
+	if err != nil {
+		dest = c.Args[1]
+	}
+
+	red := color.New(color.FgHiRed).SprintFunc()
+	result, err := core.Node.Wallet.ExportPhotos(id, dest, func(prog *wallet.ExportProgress) {
+		if prog.Error != "" {
+			c.Println(red(fmt.Sprintf("[%d/%d] error exporting %s: %s", prog.Done, prog.Total, prog.DataId, prog.Error)))
+			return
+		}
+		c.Println(fmt.Sprintf("[%d/%d] saved %s", prog.Done, prog.Total, prog.Path))
+	})
+	if err != nil {
+		c.Err(err)
+		return
+	}
+
+	blue := color.New(color.FgHiBlue).SprintFunc()
+	c.Println(blue(fmt.Sprintf("exported %d photos to %s, %d errors.", result.Exported, dest, len(result.Errors))))
 }
 
 func RemoveThread(c *ishell.Context) {
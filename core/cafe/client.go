@@ -7,6 +7,7 @@ import (
 	"github.com/textileio/textile-go/cafe/models"
 	"io"
 	"net/http"
+	neturl "net/url"
 )
 
 func CreateReferral(rreq *models.ReferralRequest, url string) (*models.ReferralResponse, error) {
@@ -113,3 +114,83 @@ func Pin(accessTok string, reader io.Reader, url string, cType string) (*models.
 	}
 	return resp, nil
 }
+
+func AddPushToken(accessTok string, token *models.PushToken, url string) (*models.Response, error) {
+	return postJSON(accessTok, token, url)
+}
+
+func StoreMessage(accessTok string, msg *models.OfflineMessage, url string) (*models.Response, error) {
+	return postJSON(accessTok, msg, url)
+}
+
+func PublishDirectoryEntry(accessTok string, entry *models.DirectoryEntry, url string) (*models.DirectoryResponse, error) {
+	payload, err := json.Marshal(entry)
+	if err != nil {
+		return nil, err
+	}
+	resp := &models.DirectoryResponse{}
+	if err := doDirectory(accessTok, "PUT", url, bytes.NewBuffer(payload), resp); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+func RemoveDirectoryEntry(accessTok string, url string) (*models.Response, error) {
+	resp := &models.DirectoryResponse{}
+	if err := doDirectory(accessTok, "DELETE", url, nil, resp); err != nil {
+		return nil, err
+	}
+	return &resp.Response, nil
+}
+
+func FindDirectoryEntry(accessTok string, query neturl.Values, url string) (*models.DirectoryResponse, error) {
+	resp := &models.DirectoryResponse{}
+	if err := doDirectory(accessTok, "GET", fmt.Sprintf("%s?%s", url, query.Encode()), nil, resp); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+func doDirectory(accessTok string, method string, url string, body io.Reader, resp *models.DirectoryResponse) error {
+	// build the request
+	req, err := http.NewRequest(method, url, body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", accessTok))
+	client := &http.Client{}
+	res, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	// read response
+	return resp.Read(res.Body)
+}
+
+func postJSON(accessTok string, body interface{}, url string) (*models.Response, error) {
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+
+	// build the request
+	req, err := http.NewRequest("POST", url, bytes.NewBuffer(payload))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", accessTok))
+	client := &http.Client{}
+	res, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	// read response
+	resp := &models.Response{}
+	if err := resp.Read(res.Body); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
@@ -24,9 +24,10 @@ var Node *TextileNode
 
 // TextileNode is the main node interface for textile functionality
 type TextileNode struct {
-	Wallet  *w.Wallet
-	gateway *http.Server
-	mux     sync.Mutex
+	Wallet     *w.Wallet
+	gateway    *http.Server
+	photoToken string
+	mux        sync.Mutex
 }
 
 // NodeConfig is used to configure the node
@@ -45,7 +45,15 @@ func TestTextileNode_StartAgain(t *testing.T) {
 }
 
 func TestTextileNode_StartServer(t *testing.T) {
-	node.StartGateway(fmt.Sprintf("127.0.0.1:%d", config.GetRandomPort()))
+	if err := node.StartGateway(fmt.Sprintf("127.0.0.1:%d", config.GetRandomPort())); err != nil {
+		t.Errorf("start gateway failed: %s", err)
+	}
+}
+
+func TestTextileNode_StartServerAddrInUse(t *testing.T) {
+	if err := node.StartGateway(node.GetGatewayAddr()); err == nil {
+		t.Error("start gateway on a used address should fail")
+	}
 }
 
 func TestTextileNode_GetGatewayAddr(t *testing.T) {
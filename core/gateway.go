@@ -1,15 +1,33 @@
 package core
 
 import (
+	"bytes"
 	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"fmt"
 	"github.com/gin-gonic/gin"
 	"github.com/gin-gonic/gin/render"
 	"github.com/textileio/textile-go/crypto"
+	"github.com/textileio/textile-go/wallet/model"
+	mh "gx/ipfs/QmZyZDi491cCNTLfAhwcaDii2Kg4pwKRkhqQzURGDvY6ua/go-multihash"
+	"io"
+	"io/ioutil"
+	"net"
 	"net/http"
+	"path"
+	"strconv"
+	"strings"
+	"time"
 )
 
-// StartGateway starts the gateway
-func (t *TextileNode) StartGateway(addr string) {
+// maxRangeLength is the most bytes served for a single range request of a stream
+const maxRangeLength = 1 << 20
+
+// StartGateway starts the gateway, returning an error if it can't listen on addr
+func (t *TextileNode) StartGateway(addr string) error {
 	// setup router
 	router := gin.Default()
 	router.GET("/health", func(g *gin.Context) {
@@ -17,17 +35,31 @@ func (t *TextileNode) StartGateway(addr string) {
 	})
 	router.GET("/ipfs/:root", gatewayHandler)
 	router.GET("/ipfs/:root/*path", gatewayHandler)
+	router.GET("/photos/:id/:variant", photoHandler)
 	router.GET("/ipns/:root", profileHandler)
 	router.GET("/ipns/:root/*path", profileHandler)
+	// decrypted photos are only served with a secret for this session
+	token := make([]byte, 32)
+	if _, err := rand.Read(token); err != nil {
+		return err
+	}
+	t.photoToken = hex.EncodeToString(token)
+
+	// bind before serving so callers know the gateway is up
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		log.Errorf("error starting gateway: %s", err)
+		return err
+	}
 	t.gateway = &http.Server{
-		Addr:    addr,
+		Addr:    listener.Addr().String(),
 		Handler: router,
 	}
 
-	// start listening
+	// start serving
 	errc := make(chan error)
 	go func() {
-		errc <- t.gateway.ListenAndServe()
+		errc <- t.gateway.Serve(listener)
 		close(errc)
 	}()
 	go func() {
@@ -45,10 +77,14 @@ func (t *TextileNode) StartGateway(addr string) {
 		}
 	}()
 	log.Infof("gateway listening at %s\n", t.gateway.Addr)
+	return nil
 }
 
 // StopGateway stops the gateway
 func (t *TextileNode) StopGateway() error {
+	if t.gateway == nil {
+		return nil
+	}
 	ctx, cancel := context.WithCancel(context.Background())
 	if err := t.gateway.Shutdown(ctx); err != nil {
 		log.Errorf("error shutting down gateway: %s", err)
@@ -58,6 +94,11 @@ func (t *TextileNode) StopGateway() error {
 	return nil
 }
 
+// GetPhotoToken returns the secret required by the gateway's photo route
+func (t *TextileNode) GetPhotoToken() string {
+	return t.photoToken
+}
+
 // GetGatewayAddr returns the gateway's address
 func (t *TextileNode) GetGatewayAddr() string {
 	return t.gateway.Addr
@@ -88,7 +129,7 @@ func gatewayHandler(c *gin.Context) {
 			c.Status(404)
 			return
 		}
-		c.Render(200, render.Data{Data: data})
+		serveData(c, data)
 		return
 	}
 
@@ -103,13 +144,13 @@ func gatewayHandler(c *gin.Context) {
 	// if key is provided, try to decrypt the data with it
 	key, exists := c.GetQuery("key")
 	if exists {
-		plain, err := crypto.DecryptAES(data, []byte(key))
+		plain, err := crypto.DecryptEnvelope(data, []byte(key), path.Base(contentPath))
 		if err != nil {
 			log.Errorf("error decrypting %s: %s", contentPath, err)
 			c.Status(404)
 			return
 		}
-		c.Render(200, render.Data{Data: plain})
+		serveData(c, plain)
 		return
 	}
 
@@ -117,6 +158,112 @@ func gatewayHandler(c *gin.Context) {
 	c.Render(200, render.Data{Data: data})
 }
 
+// photoHandler serves decrypted photo variants (photo, thumb, or a rendition name) by data id.
+// Requests must come from loopback and carry the session's photo token.
+func photoHandler(c *gin.Context) {
+	if !isLoopback(c.Request.RemoteAddr) {
+		c.Status(403)
+		return
+	}
+	token := Node.GetPhotoToken()
+	if token == "" || subtle.ConstantTimeCompare([]byte(c.Query("token")), []byte(token)) != 1 {
+		c.Status(403)
+		return
+	}
+	id := c.Param("id")
+	if _, err := mh.FromB58String(id); err != nil {
+		c.Status(400)
+		return
+	}
+	reader, err := Node.Wallet.GetPhotoReader(id, c.Param("variant"))
+	if err != nil {
+		log.Errorf("error getting photo %s: %s", id, err)
+		c.Status(404)
+		return
+	}
+	defer reader.Close()
+	if err := serveStream(c, reader); err != nil {
+		log.Errorf("error streaming photo %s: %s", id, err)
+	}
+}
+
+// isLoopback returns whether or not a request's remote address is a loopback address
+func isLoopback(remoteAddr string) bool {
+	host, _, err := net.SplitHostPort(remoteAddr)
+	if err != nil {
+		return false
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+// serveData writes data with support for range requests
+func serveData(c *gin.Context, data []byte) {
+	http.ServeContent(c.Writer, c.Request, "", time.Time{}, bytes.NewReader(data))
+}
+
+// serveStream writes a reader of unknown length, supporting a single "bytes=start-end" range.
+// Other ranges need the full length, so the whole body is sent instead.
+func serveStream(c *gin.Context, reader io.Reader) error {
+	start, end, ok := parseRange(c.GetHeader("Range"))
+	if !ok {
+		c.Status(200)
+		_, err := io.Copy(c.Writer, reader)
+		return err
+	}
+
+	// skip to the start of the range
+	if _, err := io.CopyN(ioutil.Discard, reader, start); err != nil {
+		if err == io.EOF {
+			c.Status(http.StatusRequestedRangeNotSatisfiable)
+			return nil
+		}
+		return err
+	}
+
+	// read the range first so its end can be clamped to the real length
+	length := end - start + 1
+	if length > maxRangeLength {
+		length = maxRangeLength
+	}
+	buf := make([]byte, length)
+	n, err := io.ReadFull(reader, buf)
+	switch err {
+	case nil:
+		c.Header("Content-Range", fmt.Sprintf("bytes %d-%d/*", start, start+int64(n)-1))
+	case io.ErrUnexpectedEOF:
+		c.Header("Content-Range", fmt.Sprintf("bytes %d-%d/%d", start, start+int64(n)-1, start+int64(n)))
+	case io.EOF:
+		c.Status(http.StatusRequestedRangeNotSatisfiable)
+		return nil
+	default:
+		return err
+	}
+	c.Status(http.StatusPartialContent)
+	_, err = c.Writer.Write(buf[:n])
+	return err
+}
+
+// parseRange parses a single "bytes=start-end" range
+func parseRange(header string) (int64, int64, bool) {
+	if !strings.HasPrefix(header, "bytes=") {
+		return 0, 0, false
+	}
+	parts := strings.Split(strings.TrimPrefix(header, "bytes="), "-")
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return 0, 0, false
+	}
+	start, err := strconv.ParseInt(parts[0], 10, 64)
+	if err != nil || start < 0 {
+		return 0, 0, false
+	}
+	end, err := strconv.ParseInt(parts[1], 10, 64)
+	if err != nil || end < start {
+		return 0, 0, false
+	}
+	return start, end, true
+}
+
 // profileHandler handles profile request hosted on ipns
 func profileHandler(c *gin.Context) {
 	pth, err := Node.Wallet.ResolveProfile(c.Param("root"))
@@ -126,8 +273,23 @@ func profileHandler(c *gin.Context) {
 		return
 	}
 
-	// get data
+	// get data, avatars are served by size
 	contentPath := pth.String() + c.Param("path")
+	if c.Param("path") == "/avatar" {
+		size, err := avatarSize(c.Query("size"))
+		if err != nil {
+			c.String(400, err.Error())
+			return
+		}
+		data, err := Node.Wallet.GetDataAtPath(fmt.Sprintf("%s/avatar/%d", pth.String(), size))
+		if err != nil {
+			log.Errorf("error getting avatar for %s: %s", c.Param("root"), err)
+			c.Status(404)
+			return
+		}
+		serveData(c, data)
+		return
+	}
 	data, err := Node.Wallet.GetDataAtPath(contentPath)
 	if err != nil {
 		log.Errorf("error getting data at profile path %s: %s", contentPath, err)
@@ -138,3 +300,20 @@ func profileHandler(c *gin.Context) {
 	// lastly, just return the raw bytes (standard gateway)
 	c.Render(200, render.Data{Data: data})
 }
+
+// avatarSize returns the requested avatar size, or the default if not specified
+func avatarSize(query string) (int, error) {
+	if query == "" {
+		return model.DefaultAvatarSize, nil
+	}
+	size, err := strconv.Atoi(query)
+	if err != nil {
+		return 0, errors.New(fmt.Sprintf("invalid avatar size: %s", query))
+	}
+	for _, s := range model.AvatarSizes {
+		if s == size {
+			return size, nil
+		}
+	}
+	return 0, errors.New(fmt.Sprintf("unsupported avatar size: %d", size))
+}
@@ -0,0 +1,311 @@
+package crypto
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"golang.org/x/crypto/hkdf"
+	"io"
+	"io/ioutil"
+)
+
+// EnvelopeVersion is the current ciphertext envelope version
+const EnvelopeVersion byte = 1
+
+// StreamChunkSize is the plaintext size of each chunk in a streamed envelope
+const StreamChunkSize = 64 * 1024
+
+// envelope header: magic, version, mode
+const envelopeMagic = "TXE"
+const headerSize = len(envelopeMagic) + 2
+
+// envelope modes
+const (
+	modeSealed byte = iota
+	modeStream
+)
+
+// sealed envelopes carry a full gcm nonce, streams carry a prefix
+// which is extended with a chunk counter and a final chunk flag
+const nonceSize = 12
+const streamPrefixSize = 7
+
+var errInvalidKey = errors.New("invalid key")
+var errInvalidEnvelope = errors.New("invalid envelope")
+var errTruncatedStream = errors.New("truncated stream")
+
+// IsEnvelope returns whether or not data starts with an envelope header
+func IsEnvelope(data []byte) bool {
+	return len(data) >= headerSize && string(data[:len(envelopeMagic)]) == envelopeMagic
+}
+
+// EncryptEnvelope encrypts bytes with a random nonce under a subkey of key derived for name.
+// An empty name uses the key without per-file separation.
+func EncryptEnvelope(plaintext []byte, key []byte, name string) ([]byte, error) {
+	header := newHeader(modeSealed)
+	aead, err := newAEAD(key, name)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, nonceSize)
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	out := append(header, nonce...)
+	return aead.Seal(out, nonce, plaintext, header), nil
+}
+
+// DecryptEnvelope decrypts an envelope created by EncryptEnvelope or NewEncryptWriter,
+// falling back to DecryptAES for data encrypted before envelopes existed.
+func DecryptEnvelope(data []byte, key []byte, name string) ([]byte, error) {
+	if !IsEnvelope(data) {
+		return DecryptAES(data, key)
+	}
+	plain, err := openEnvelope(data, key, name)
+	if err != nil {
+		// legacy ciphertext may collide with the magic bytes
+		if legacy, lerr := DecryptAES(data, key); lerr == nil {
+			return legacy, nil
+		}
+		return nil, err
+	}
+	return plain, nil
+}
+
+// NewEncryptWriter returns a writer which encrypts to w in fixed size chunks.
+// Close must be called to write the final chunk.
+func NewEncryptWriter(w io.Writer, key []byte, name string) (io.WriteCloser, error) {
+	aead, err := newAEAD(key, name)
+	if err != nil {
+		return nil, err
+	}
+	prefix := make([]byte, streamPrefixSize)
+	if _, err := rand.Read(prefix); err != nil {
+		return nil, err
+	}
+	header := append(newHeader(modeStream), prefix...)
+	if _, err := w.Write(header); err != nil {
+		return nil, err
+	}
+	return &streamWriter{
+		w:      w,
+		aead:   aead,
+		prefix: prefix,
+		ad:     header,
+		buf:    make([]byte, 0, StreamChunkSize),
+	}, nil
+}
+
+// NewDecryptReader returns a reader which decrypts r. Streamed envelopes are
+// decrypted chunk by chunk, everything else is read fully and handed to DecryptEnvelope.
+func NewDecryptReader(r io.Reader, key []byte, name string) (io.Reader, error) {
+	br := bufio.NewReaderSize(r, StreamChunkSize+headerSize+streamPrefixSize)
+	peek, err := br.Peek(headerSize)
+	if err != nil && err != io.EOF {
+		return nil, err
+	}
+	if IsEnvelope(peek) && peek[len(envelopeMagic)+1] == modeStream {
+		return newStreamReader(br, key, name)
+	}
+	data, err := ioutil.ReadAll(br)
+	if err != nil {
+		return nil, err
+	}
+	plain, err := DecryptEnvelope(data, key, name)
+	if err != nil {
+		return nil, err
+	}
+	return bytes.NewReader(plain), nil
+}
+
+// openEnvelope decrypts data which has a valid envelope header
+func openEnvelope(data []byte, key []byte, name string) ([]byte, error) {
+	version := data[len(envelopeMagic)]
+	if version != EnvelopeVersion {
+		return nil, errors.New(fmt.Sprintf("unsupported envelope version: %d", version))
+	}
+	switch data[len(envelopeMagic)+1] {
+	case modeSealed:
+		if len(data) < headerSize+nonceSize {
+			return nil, errInvalidEnvelope
+		}
+		aead, err := newAEAD(key, name)
+		if err != nil {
+			return nil, err
+		}
+		header := data[:headerSize]
+		nonce := data[headerSize : headerSize+nonceSize]
+		return aead.Open(nil, nonce, data[headerSize+nonceSize:], header)
+	case modeStream:
+		reader, err := newStreamReader(bufio.NewReader(bytes.NewReader(data)), key, name)
+		if err != nil {
+			return nil, err
+		}
+		return ioutil.ReadAll(reader)
+	default:
+		return nil, errInvalidEnvelope
+	}
+}
+
+// newHeader returns a header for the current version and mode
+func newHeader(mode byte) []byte {
+	return append([]byte(envelopeMagic), EnvelopeVersion, mode)
+}
+
+// newAEAD returns aes-256 gcm under an hkdf-sha256 subkey of key for name
+func newAEAD(key []byte, name string) (cipher.AEAD, error) {
+	if len(key) != 44 {
+		return nil, errInvalidKey
+	}
+	subkey := make([]byte, 32)
+	kdf := hkdf.New(sha256.New, key, nil, []byte(fmt.Sprintf("textile/envelope/v%d/%s", EnvelopeVersion, name)))
+	if _, err := io.ReadFull(kdf, subkey); err != nil {
+		return nil, err
+	}
+	block, err := aes.NewCipher(subkey)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// chunkNonce returns prefix || counter || final flag
+func chunkNonce(prefix []byte, counter uint32, final bool) []byte {
+	nonce := make([]byte, nonceSize)
+	copy(nonce, prefix)
+	binary.BigEndian.PutUint32(nonce[streamPrefixSize:], counter)
+	if final {
+		nonce[nonceSize-1] = 1
+	}
+	return nonce
+}
+
+// streamWriter holds back a full chunk until it knows whether more data follows
+type streamWriter struct {
+	w       io.Writer
+	aead    cipher.AEAD
+	prefix  []byte
+	ad      []byte
+	buf     []byte
+	counter uint32
+	closed  bool
+}
+
+func (s *streamWriter) Write(p []byte) (int, error) {
+	if s.closed {
+		return 0, errors.New("write to closed stream")
+	}
+	n := len(p)
+	for len(p) > 0 {
+		if len(s.buf) == StreamChunkSize {
+			if err := s.flush(false); err != nil {
+				return n - len(p), err
+			}
+		}
+		take := StreamChunkSize - len(s.buf)
+		if take > len(p) {
+			take = len(p)
+		}
+		s.buf = append(s.buf, p[:take]...)
+		p = p[take:]
+	}
+	return n, nil
+}
+
+func (s *streamWriter) Close() error {
+	if s.closed {
+		return nil
+	}
+	s.closed = true
+	return s.flush(true)
+}
+
+func (s *streamWriter) flush(final bool) error {
+	if s.counter == ^uint32(0) {
+		return errors.New("stream too large")
+	}
+	ciph := s.aead.Seal(nil, chunkNonce(s.prefix, s.counter, final), s.buf, s.ad)
+	if _, err := s.w.Write(ciph); err != nil {
+		return err
+	}
+	s.counter++
+	s.buf = s.buf[:0]
+	return nil
+}
+
+// streamReader decrypts one chunk at a time, peeking ahead to detect the final chunk
+type streamReader struct {
+	r       *bufio.Reader
+	aead    cipher.AEAD
+	prefix  []byte
+	ad      []byte
+	chunk   []byte
+	plain   []byte
+	counter uint32
+	done    bool
+}
+
+func newStreamReader(r *bufio.Reader, key []byte, name string) (*streamReader, error) {
+	header := make([]byte, headerSize+streamPrefixSize)
+	if _, err := io.ReadFull(r, header); err != nil {
+		return nil, errInvalidEnvelope
+	}
+	if header[len(envelopeMagic)] != EnvelopeVersion {
+		return nil, errors.New(fmt.Sprintf("unsupported envelope version: %d", header[len(envelopeMagic)]))
+	}
+	aead, err := newAEAD(key, name)
+	if err != nil {
+		return nil, err
+	}
+	return &streamReader{
+		r:      r,
+		aead:   aead,
+		prefix: header[headerSize:],
+		ad:     header,
+		chunk:  make([]byte, StreamChunkSize+aead.Overhead()),
+	}, nil
+}
+
+func (s *streamReader) Read(p []byte) (int, error) {
+	for len(s.plain) == 0 {
+		if s.done {
+			return 0, io.EOF
+		}
+		if err := s.next(); err != nil {
+			return 0, err
+		}
+	}
+	n := copy(p, s.plain)
+	s.plain = s.plain[n:]
+	return n, nil
+}
+
+func (s *streamReader) next() error {
+	n, err := io.ReadFull(s.r, s.chunk)
+	if err != nil && err != io.ErrUnexpectedEOF && err != io.EOF {
+		return err
+	}
+	final := n < len(s.chunk)
+	if !final {
+		if _, perr := s.r.Peek(1); perr == io.EOF {
+			final = true
+		}
+	}
+	plain, err := s.aead.Open(s.chunk[:0], chunkNonce(s.prefix, s.counter, final), s.chunk[:n], s.ad)
+	if err != nil {
+		if final {
+			return errTruncatedStream
+		}
+		return err
+	}
+	s.counter++
+	s.plain = plain
+	s.done = final
+	return nil
+}
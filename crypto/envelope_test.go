@@ -0,0 +1,130 @@
+package crypto
+
+import (
+	"bytes"
+	"crypto/rand"
+	"io/ioutil"
+	"testing"
+)
+
+var envelopeTestData = struct {
+	plaintext  []byte
+	key        []byte
+	ciphertext []byte
+}{
+	plaintext: []byte("yoyoyoyo!"),
+}
+
+func TestEncryptEnvelope(t *testing.T) {
+	key, err := GenerateAESKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	envelopeTestData.key = key
+	ciphertext, err := EncryptEnvelope(envelopeTestData.plaintext, key, "photo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !IsEnvelope(ciphertext) {
+		t.Error("encrypt envelope missing header")
+	}
+	again, err := EncryptEnvelope(envelopeTestData.plaintext, key, "photo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Equal(ciphertext, again) {
+		t.Error("encrypt envelope reused nonce")
+	}
+	envelopeTestData.ciphertext = ciphertext
+}
+
+func TestDecryptEnvelope(t *testing.T) {
+	plaintext, err := DecryptEnvelope(envelopeTestData.ciphertext, envelopeTestData.key, "photo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(envelopeTestData.plaintext, plaintext) {
+		t.Error("decrypt envelope failed")
+	}
+	if _, err := DecryptEnvelope(envelopeTestData.ciphertext, envelopeTestData.key, "thumb"); err == nil {
+		t.Error("decrypt envelope with wrong subkey succeeded")
+	}
+	key, err := GenerateAESKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := DecryptEnvelope(envelopeTestData.ciphertext, key, "photo"); err == nil {
+		t.Error("decrypt envelope with bad key succeeded")
+	}
+}
+
+func TestDecryptEnvelope_Legacy(t *testing.T) {
+	legacy, err := EncryptAES(envelopeTestData.plaintext, envelopeTestData.key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	plaintext, err := DecryptEnvelope(legacy, envelopeTestData.key, "photo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(envelopeTestData.plaintext, plaintext) {
+		t.Error("decrypt legacy ciphertext failed")
+	}
+}
+
+func TestEncryptWriter(t *testing.T) {
+	for _, size := range []int{0, 1, StreamChunkSize, StreamChunkSize*2 + 7} {
+		plaintext := make([]byte, size)
+		if _, err := rand.Read(plaintext); err != nil {
+			t.Fatal(err)
+		}
+		var buf bytes.Buffer
+		writer, err := NewEncryptWriter(&buf, envelopeTestData.key, "photo")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := writer.Write(plaintext); err != nil {
+			t.Fatal(err)
+		}
+		if err := writer.Close(); err != nil {
+			t.Fatal(err)
+		}
+		ciphertext := buf.Bytes()
+
+		reader, err := NewDecryptReader(bytes.NewReader(ciphertext), envelopeTestData.key, "photo")
+		if err != nil {
+			t.Fatal(err)
+		}
+		streamed, err := ioutil.ReadAll(reader)
+		if err != nil {
+			t.Fatalf("decrypt stream of %d bytes failed: %s", size, err)
+		}
+		if !bytes.Equal(plaintext, streamed) {
+			t.Errorf("decrypt stream of %d bytes mismatch", size)
+		}
+		whole, err := DecryptEnvelope(ciphertext, envelopeTestData.key, "photo")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(plaintext, whole) {
+			t.Errorf("decrypt envelope stream of %d bytes mismatch", size)
+		}
+	}
+}
+
+func TestDecryptReader_Truncated(t *testing.T) {
+	plaintext := make([]byte, StreamChunkSize*2)
+	var buf bytes.Buffer
+	writer, err := NewEncryptWriter(&buf, envelopeTestData.key, "photo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	writer.Write(plaintext)
+	writer.Close()
+
+	// drop the final chunk
+	truncated := buf.Bytes()[:buf.Len()-(StreamChunkSize+16)]
+	if _, err := DecryptEnvelope(truncated, envelopeTestData.key, "photo"); err == nil {
+		t.Error("decrypt truncated stream succeeded")
+	}
+}
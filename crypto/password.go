@@ -0,0 +1,51 @@
+package crypto
+
+import (
+	"crypto/rand"
+	"errors"
+	"golang.org/x/crypto/scrypt"
+)
+
+// scrypt cost parameters, tuned to stay well under a second on mobile
+const (
+	scryptN   = 1 << 15
+	scryptR   = 8
+	scryptP   = 1
+	saltSize  = 16
+	keyLength = 44
+)
+
+// EncryptWithPassword encrypts bytes under a key stretched from password with scrypt.
+// The random salt is prepended to the ciphertext envelope.
+func EncryptWithPassword(plaintext []byte, password string) ([]byte, error) {
+	salt := make([]byte, saltSize)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, err
+	}
+	key, err := passwordKey(password, salt)
+	if err != nil {
+		return nil, err
+	}
+	ciph, err := EncryptEnvelope(plaintext, key, "password")
+	if err != nil {
+		return nil, err
+	}
+	return append(salt, ciph...), nil
+}
+
+// DecryptWithPassword decrypts bytes created by EncryptWithPassword
+func DecryptWithPassword(ciphertext []byte, password string) ([]byte, error) {
+	if len(ciphertext) < saltSize || !IsEnvelope(ciphertext[saltSize:]) {
+		return nil, errors.New("invalid password ciphertext")
+	}
+	key, err := passwordKey(password, ciphertext[:saltSize])
+	if err != nil {
+		return nil, err
+	}
+	return openEnvelope(ciphertext[saltSize:], key, "password")
+}
+
+// passwordKey stretches password into an envelope key
+func passwordKey(password string, salt []byte) ([]byte, error) {
+	return scrypt.Key([]byte(password), salt, scryptN, scryptR, scryptP, keyLength)
+}
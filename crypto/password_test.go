@@ -0,0 +1,25 @@
+package crypto
+
+import (
+	"bytes"
+	"testing"
+)
+
+var passwordTestPlaintext = []byte("yoyoyoyo!")
+
+func TestEncryptWithPassword(t *testing.T) {
+	ciphertext, err := EncryptWithPassword(passwordTestPlaintext, "hunter2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	plaintext, err := DecryptWithPassword(ciphertext, "hunter2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(passwordTestPlaintext, plaintext) {
+		t.Error("decrypt with password failed")
+	}
+	if _, err := DecryptWithPassword(ciphertext, "hunter3"); err == nil {
+		t.Error("decrypt with wrong password succeeded")
+	}
+}
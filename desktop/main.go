@@ -19,6 +19,7 @@ import (
 	"github.com/textileio/textile-go/wallet/thread"
 	"os"
 	"path/filepath"
+	"strings"
 	"time"
 )
 
@@ -26,6 +27,7 @@ var (
 	appName  = "Textile"
 	builtAt  string
 	debug    = flag.Bool("d", false, "enables the debug mode")
+	password = flag.String("p", "", "unlocks (or encrypts on first run) the repo with a password")
 	window   *astilectron.Window
 	gateway  string
 	expanded bool
@@ -39,6 +41,7 @@ const (
 	SleepOnLoad     = time.Second * 1
 	SleepOnPreReady = time.Millisecond * 200
 	SleepOnExpand   = time.Millisecond * 200
+	InviteTTL       = time.Hour * 24 * 7
 )
 
 func main() {
@@ -69,6 +72,7 @@ func start(_ *astilectron.Astilectron, w *astilectron.Window, _ *astilectron.Men
 		LogFiles: true,
 		WalletConfig: wallet.Config{
 			RepoPath: filepath.Join(appDir, "repo"),
+			Password: *password,
 		},
 	}
 	core.Node, _, err = core.NewNode(config)
@@ -114,6 +118,8 @@ func start(_ *astilectron.Astilectron, w *astilectron.Window, _ *astilectron.Men
 					break
 				case wallet.DeviceRemoved:
 					break
+				case wallet.ProfileUpdated:
+					break
 				}
 			}
 		}
@@ -127,7 +133,9 @@ func start(_ *astilectron.Astilectron, w *astilectron.Window, _ *astilectron.Men
 	}
 
 	// start the gateway
-	core.Node.StartGateway(fmt.Sprintf("127.0.0.1:%d", rconfig.GetRandomPort()))
+	if err := core.Node.StartGateway(fmt.Sprintf("127.0.0.1:%d", rconfig.GetRandomPort())); err != nil {
+		return err
+	}
 
 	// save off the server address
 	gateway = fmt.Sprintf("http://%s", core.Node.GetGatewayAddr())
@@ -166,14 +174,14 @@ func start(_ *astilectron.Astilectron, w *astilectron.Window, _ *astilectron.Men
 
 	} else {
 		// get qr code for setup
-		qr, pk, err := getQRCode()
+		qr, code, err := getQRCode()
 		if err != nil {
 			astilog.Error(err)
 			return err
 		}
 		sendData("setup", map[string]interface{}{
-			"qr": qr,
-			"pk": pk,
+			"qr":   qr,
+			"code": code,
 		})
 	}
 
@@ -208,6 +216,36 @@ func handleMessage(_ *astilectron.Window, m bootstrap.MessageIn) (interface{}, e
 		return map[string]interface{}{
 			"html": html,
 		}, nil
+	case "invite.create":
+		var threadId string
+		if err := json.Unmarshal(m.Payload, &threadId); err != nil {
+			return nil, err
+		}
+		invite, err := core.Node.Wallet.CreateExternalInvite(threadId, InviteTTL, 0)
+		if err != nil {
+			return nil, err
+		}
+		png, err := qrcode.Encode(invite.Link, qrcode.Medium, QRCodeSize)
+		if err != nil {
+			return nil, err
+		}
+		return map[string]interface{}{
+			"link":    invite.Link,
+			"qr":      base64.StdEncoding.EncodeToString(png),
+			"expires": invite.Expires,
+		}, nil
+	case "invite.accept":
+		var link string
+		if err := json.Unmarshal(m.Payload, &link); err != nil {
+			return nil, err
+		}
+		addr, err := core.Node.Wallet.AcceptExternalThreadInviteLink(strings.TrimSpace(link))
+		if err != nil {
+			return nil, err
+		}
+		return map[string]interface{}{
+			"id": addr.B58String(),
+		}, nil
 	default:
 		return map[string]interface{}{}, nil
 	}
@@ -228,20 +266,19 @@ func subscribe(thrd *thread.Thread) {
 }
 
 func getQRCode() (string, string, error) {
-	// get our own public key
-	pk, err := core.Node.Wallet.GetPubKeyString()
+	// get a one-time pairing link
+	req, err := core.Node.Wallet.NewPairingRequest()
 	if err != nil {
 		return "", "", err
 	}
 
 	// create a qr code
-	url := fmt.Sprintf("https://www.textile.photos/invites/device#key=%s", pk)
-	png, err := qrcode.Encode(url, qrcode.Medium, QRCodeSize)
+	png, err := qrcode.Encode(req.Link, qrcode.Medium, QRCodeSize)
 	if err != nil {
 		return "", "", err
 	}
 
-	return base64.StdEncoding.EncodeToString(png), pk, nil
+	return base64.StdEncoding.EncodeToString(png), req.Code, nil
 }
 
 func getThreadPhotos(id string) (string, error) {
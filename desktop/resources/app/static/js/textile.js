@@ -21,7 +21,7 @@ let textile = {
           break
 
         case 'setup':
-          setAddress(msg.qr, msg.pk)
+          setAddress(msg.qr, msg.code)
           break
 
         case 'preready':
@@ -61,12 +61,12 @@ let textile = {
   },
 }
 
-function setAddress(qr, pk) {
+function setAddress(qr, code) {
   $('.logo').addClass('hidden')
   let qrCode = $('.qr-code')
   qrCode.attr('src', 'data:image/png;base64,' + qr)
   qrCode.removeClass('hidden')
-  $('.address').text('Address: ' + pk)
+  $('.address').text('Pairing code: ' + code)
 }
 
 function hideSetup() {
@@ -95,6 +95,44 @@ function refresh() {
   }, 500)
 }
 
+function createInvite() {
+  let id = $('.thread.active').attr('id')
+  if (!id) {
+    return
+  }
+  astilectron.sendMessage({name: 'invite.create', payload: id}, function (message) {
+    if (message.name === 'error') {
+      asticode.notifier.error(message.payload)
+      return
+    }
+    let content = $('<div></div>')
+    $('<img class="qr-code" src="data:image/png;base64,' + message.payload.qr + '" />').appendTo(content)
+    $('<p class="invite-link"></p>').text(message.payload.link).appendTo(content)
+    $('<p></p>').text('Expires ' + new Date(message.payload.expires).toLocaleString()).appendTo(content)
+    asticode.modaler.setContent(content[0])
+    asticode.modaler.show()
+  })
+}
+
+function showAcceptInvite() {
+  let content = $('<div></div>')
+  let input = $('<input type="text" placeholder="textile://invite/..." />').appendTo(content)
+  $('<button>Join</button>').click(function () {
+    acceptInvite(input.val())
+  }).appendTo(content)
+  asticode.modaler.setContent(content[0])
+  asticode.modaler.show()
+}
+
+function acceptInvite(link) {
+  astilectron.sendMessage({name: 'invite.accept', payload: link}, function (message) {
+    asticode.modaler.hide()
+    if (message.name === 'error') {
+      asticode.notifier.error(message.payload)
+    }
+  })
+}
+
 function renderThreads(threads) {
   threads.forEach(function (thread) {
     addThread(thread)
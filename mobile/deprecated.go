@@ -0,0 +1,139 @@
+package mobile
+
+import (
+	tcore "github.com/textileio/textile-go/core"
+)
+
+// The methods below return json encoded strings and plain errors. They are kept
+// for existing bridge code and wrap the typed api.
+
+// GetTokens calls core GetTokens
+// Deprecated: use Tokens
+func (m *Mobile) GetTokens() (string, error) {
+	tokens, err := tcore.Node.Wallet.GetTokens()
+	if err != nil {
+		return "", err
+	}
+	return toJSON(tokens)
+}
+
+// GetProfile returns this peer's profile
+// Deprecated: use Profile
+func (m *Mobile) GetProfile() (string, error) {
+	id, err := tcore.Node.Wallet.GetId()
+	if err != nil {
+		return "", err
+	}
+	return m.GetPeerProfile(id)
+}
+
+// GetPeerProfile uses a peer id to look up a profile
+// Deprecated: use PeerProfile
+func (m *Mobile) GetPeerProfile(peerId string) (string, error) {
+	prof, err := tcore.Node.Wallet.GetProfile(peerId)
+	if err != nil {
+		log.Errorf("error getting profile %s: %s", peerId, err)
+		return "", err
+	}
+	return toJSON(prof)
+}
+
+// Threads lists all threads
+// Deprecated: use ThreadList
+func (m *Mobile) Threads() (string, error) {
+	threads, err := m.ThreadList()
+	if err != nil {
+		return "", legacyError(err)
+	}
+	return toJSON(threads)
+}
+
+// PhotoThreads call core PhotoThreads
+// Deprecated: use PhotoThreadList
+func (m *Mobile) PhotoThreads(id string) (string, error) {
+	threads, err := m.PhotoThreadList(id)
+	if err != nil {
+		return "", legacyError(err)
+	}
+	return toJSON(threads)
+}
+
+// AddThread adds a new thread with the given name
+// Deprecated: use CreateThread
+func (m *Mobile) AddThread(name string, mnemonic string) (string, error) {
+	thrd, err := m.CreateThread(name, mnemonic)
+	if err != nil {
+		return "", legacyError(err)
+	}
+	return toJSON(thrd)
+}
+
+// AddExternalThreadInvite generates a new external invite link to a thread
+// Deprecated: use CreateExternalThreadInvite
+func (m *Mobile) AddExternalThreadInvite(threadId string) (string, error) {
+	invite, err := m.CreateExternalThreadInvite(threadId, 0, 0)
+	if err != nil {
+		return "", legacyError(err)
+	}
+	return toJSON(invite)
+}
+
+// Devices lists all devices
+// Deprecated: use DeviceList
+func (m *Mobile) Devices() (string, error) {
+	devices, err := m.DeviceList()
+	if err != nil {
+		return "", legacyError(err)
+	}
+	return toJSON(devices)
+}
+
+// AddPhoto adds a photo by path
+// Deprecated: use CreatePhoto
+func (m *Mobile) AddPhoto(path string) (string, error) {
+	added, err := tcore.Node.Wallet.AddPhoto(path)
+	if err != nil {
+		return "", err
+	}
+	return toJSON(added)
+}
+
+// GetPhotos returns thread photo blocks with json encoding
+// Deprecated: use PhotoList
+func (m *Mobile) GetPhotos(offsetId string, limit int, threadId string) (string, error) {
+	photos, err := m.PhotoList(offsetId, limit, threadId)
+	if err != nil {
+		return "", legacyError(err)
+	}
+	return toJSON(photos)
+}
+
+// GetPhotoData returns a data url for a photo
+// Deprecated: use PhotoImage
+func (m *Mobile) GetPhotoData(id string) (string, error) {
+	data, err := m.PhotoImage(id)
+	if err != nil {
+		return "", legacyError(err)
+	}
+	return toJSON(data)
+}
+
+// GetThumbData returns a data url for a photo thumbnail
+// Deprecated: use ThumbImage
+func (m *Mobile) GetThumbData(id string) (string, error) {
+	data, err := m.ThumbImage(id)
+	if err != nil {
+		return "", legacyError(err)
+	}
+	return toJSON(data)
+}
+
+// GetPhotoMetadata returns a meta data object for a photo
+// Deprecated: use PhotoMetadata
+func (m *Mobile) GetPhotoMetadata(id string) (string, error) {
+	meta, err := m.getPhotoMetadata(id)
+	if err != nil {
+		return "", legacyError(err)
+	}
+	return toJSON(meta)
+}
@@ -0,0 +1,114 @@
+package mobile
+
+import (
+	"errors"
+	"fmt"
+	"github.com/textileio/textile-go/repo"
+	"github.com/textileio/textile-go/repo/db"
+	"github.com/textileio/textile-go/wallet"
+	"github.com/textileio/textile-go/wallet/thread"
+	"strings"
+)
+
+// Error codes let the bridge layer switch on an error. Messages are unchanged,
+// so the code is recovered from a message with ErrorCode.
+const (
+	ErrCodeUnknown = iota
+	ErrCodeOffline
+	ErrCodeNotSignedIn
+	ErrCodeNotFound
+	ErrCodeInvalidKey
+	ErrCodeInvalidPassword
+	ErrCodeInvalidInvite
+	ErrCodeLegacyIdentity
+)
+
+// knownErrors maps wallet errors to their codes
+var knownErrors = map[error]int{
+	wallet.ErrOffline:            ErrCodeOffline,
+	wallet.ErrStopped:            ErrCodeOffline,
+	wallet.ErrInviterUnreachable: ErrCodeOffline,
+	wallet.ErrNotSignedIn:        ErrCodeNotSignedIn,
+	wallet.ErrNoCafeHost:         ErrCodeNotSignedIn,
+	wallet.ErrBlockNotFound:      ErrCodeNotFound,
+	wallet.ErrNoBackup:           ErrCodeNotFound,
+	wallet.ErrContactNotFound:    ErrCodeNotFound,
+	repo.ErrInvalidPassword:      ErrCodeInvalidPassword,
+	repo.ErrPasswordRequired:     ErrCodeInvalidPassword,
+	db.ErrInvalidPassword:        ErrCodeInvalidPassword,
+	thread.ErrInviteNotFound:     ErrCodeInvalidInvite,
+	thread.ErrInviteRevoked:      ErrCodeInvalidInvite,
+	thread.ErrInviteExpired:      ErrCodeInvalidInvite,
+	thread.ErrInviteUsedUp:       ErrCodeInvalidInvite,
+	wallet.ErrLegacyIdentity:     ErrCodeLegacyIdentity,
+}
+
+// knownPrefixes maps the start of formatted error messages to their codes
+var knownPrefixes = map[string]int{
+	"could not find thread: ": ErrCodeNotFound,
+	"illegal base64 data":     ErrCodeInvalidKey,
+}
+
+// Error is a structured error with a code the bridge layer can switch on
+type Error struct {
+	Code  int
+	cause error
+}
+
+// Error returns the underlying error message
+func (e *Error) Error() string {
+	return e.cause.Error()
+}
+
+// Message returns the error message
+func (e *Error) Message() string {
+	return e.cause.Error()
+}
+
+// ErrorCode returns the code for an error message, defaulting to ErrCodeUnknown
+func ErrorCode(message string) int {
+	for err, code := range knownErrors {
+		if err.Error() == message {
+			return code
+		}
+	}
+	for prefix, code := range knownPrefixes {
+		if strings.HasPrefix(message, prefix) {
+			return code
+		}
+	}
+	return ErrCodeUnknown
+}
+
+// newError creates a structured error with a formatted message
+func newError(code int, format string, args ...interface{}) *Error {
+	return &Error{Code: code, cause: errors.New(fmt.Sprintf(format, args...))}
+}
+
+// wrapError classifies known wallet errors, passing through already structured errors
+func wrapError(err error) error {
+	if err == nil {
+		return nil
+	}
+	if _, ok := err.(*Error); ok {
+		return err
+	}
+	code, ok := knownErrors[err]
+	if !ok {
+		code = ErrCodeUnknown
+	}
+	return &Error{Code: code, cause: err}
+}
+
+// invalidKey wraps key decoding errors
+func invalidKey(err error) error {
+	return &Error{Code: ErrCodeInvalidKey, cause: err}
+}
+
+// legacyError strips the code from structured errors for the deprecated json api
+func legacyError(err error) error {
+	if serr, ok := err.(*Error); ok {
+		return serr.cause
+	}
+	return err
+}
@@ -0,0 +1,23 @@
+package mobile_test
+
+import (
+	"fmt"
+	. "github.com/textileio/textile-go/mobile"
+	"github.com/textileio/textile-go/wallet"
+	"testing"
+)
+
+func TestErrorCode(t *testing.T) {
+	if ErrorCode(wallet.ErrOffline.Error()) != ErrCodeOffline {
+		t.Error("bad error code from wallet error message")
+	}
+	if ErrorCode(fmt.Sprintf("could not find thread: %s", "foo")) != ErrCodeNotFound {
+		t.Error("bad error code from formatted message")
+	}
+	if ErrorCode("plain error") != ErrCodeUnknown {
+		t.Error("plain error should have unknown code")
+	}
+	if ErrorCode(fmt.Sprintf("%d: boom", ErrCodeNotFound)) != ErrCodeUnknown {
+		t.Error("messages do not carry codes")
+	}
+}
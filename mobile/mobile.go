@@ -2,17 +2,20 @@ package mobile
 
 import (
 	"encoding/json"
-	"errors"
 	"fmt"
 	"github.com/op/go-logging"
 	"github.com/textileio/textile-go/cafe/models"
 	tcore "github.com/textileio/textile-go/core"
 	"github.com/textileio/textile-go/repo"
+	rconfig "github.com/textileio/textile-go/repo/config"
 	"github.com/textileio/textile-go/util"
 	"github.com/textileio/textile-go/wallet"
 	"github.com/textileio/textile-go/wallet/model"
 	"github.com/textileio/textile-go/wallet/thread"
 	libp2pc "gx/ipfs/QmaPbCnUMBohSGo3KnxEa2bHqyJVVeEEcwtqJAYxerieBo/go-libp2p-crypto"
+	"io"
+	"os"
+	"sync"
 	"time"
 )
 
@@ -31,11 +34,18 @@ type Messenger interface {
 
 // NodeConfig is used to configure the mobile node
 // NOTE: logLevel is one of: CRITICAL ERROR WARNING NOTICE INFO DEBUG
+// NOTE: password is optional, it encrypts the repo on first run and unlocks it after
+// NOTE: mnemonic and passphrase are only used on first run, to restore an account
+// NOTE: renditions are name:width[:square], comma separated, defaults are used if empty
 type NodeConfig struct {
-	RepoPath string
-	CafeAddr string
-	LogLevel string
-	LogFiles bool
+	RepoPath   string
+	Mnemonic   string
+	Passphrase string
+	Password   string
+	CafeAddr   string
+	Renditions string
+	LogLevel   string
+	LogFiles   bool
 }
 
 // Mobile is the name of the framework (must match package name)
@@ -43,55 +53,8 @@ type Mobile struct {
 	RepoPath  string
 	Mnemonic  string
 	messenger Messenger
-}
-
-// Thread is a simple meta data wrapper around a Thread
-type Thread struct {
-	Id    string `json:"id"`
-	Name  string `json:"name"`
-	Peers int    `json:"peers"`
-}
-
-// Threads is a wrapper around a list of Threads
-type Threads struct {
-	Items []Thread `json:"items"`
-}
-
-// Device is a simple meta data wrapper around a Device
-type Device struct {
-	Id   string `json:"id"`
-	Name string `json:"name"`
-}
-
-// Devices is a wrapper around a list of Devices
-type Devices struct {
-	Items []Device `json:"items"`
-}
-
-// Photo is a simple meta data wrapper around a photo block
-type Photo struct {
-	Id       string    `json:"id"`
-	Date     time.Time `json:"date"`
-	AuthorId string    `json:"author_id"`
-	Caption  string    `json:"caption"`
-}
-
-// Photos is a wrapper around a list of photos
-type Photos struct {
-	Items []Photo `json:"items"`
-}
-
-// ImageData is a wrapper around an image data url and meta data
-type ImageData struct {
-	Url      string               `json:"url"`
-	Metadata *model.PhotoMetadata `json:"metadata"`
-}
-
-// ExternalInvite is a wrapper around an invite id and key
-type ExternalInvite struct {
-	Id      string `json:"id"`
-	Key     string `json:"key"`
-	Inviter string `json:"inviter"`
+	gateway   string
+	mux       sync.Mutex
 }
 
 // Create a gomobile compatible wrapper around TextileNode
@@ -100,18 +63,33 @@ func NewNode(config *NodeConfig, messenger Messenger) (*Mobile, error) {
 	if err != nil {
 		ll = logging.INFO
 	}
+	var restore *string
+	if config.Mnemonic != "" {
+		restore = &config.Mnemonic
+	}
+	var renditions []model.Rendition
+	if config.Renditions != "" {
+		renditions, err = wallet.ParseRenditions(config.Renditions)
+		if err != nil {
+			return nil, err
+		}
+	}
 	cconfig := tcore.NodeConfig{
 		LogLevel: ll,
 		LogFiles: config.LogFiles,
 		WalletConfig: wallet.Config{
-			RepoPath: config.RepoPath,
-			IsMobile: true,
-			CafeAddr: config.CafeAddr,
+			RepoPath:   config.RepoPath,
+			Mnemonic:   restore,
+			Passphrase: config.Passphrase,
+			Password:   config.Password,
+			IsMobile:   true,
+			CafeAddr:   config.CafeAddr,
+			Renditions: renditions,
 		},
 	}
 	node, mnemonic, err := tcore.NewNode(cconfig)
 	if err != nil {
-		return nil, err
+		return nil, wrapError(err)
 	}
 	tcore.Node = node
 
@@ -165,6 +143,9 @@ func (m *Mobile) Start() error {
 
 					case wallet.DeviceRemoved:
 						name = "onDeviceRemoved"
+
+					case wallet.ProfileUpdated:
+						name = "onProfileUpdated"
 					}
 					m.messenger.Notify(&Event{Name: name, Payload: payload})
 				}
@@ -184,8 +165,22 @@ func (m *Mobile) Start() error {
 	return nil
 }
 
+// ChangePassword re-encrypts the repo with a new password, empty to remove encryption.
+// The node must be stopped.
+func (m *Mobile) ChangePassword(current string, next string) error {
+	return wrapError(tcore.Node.Wallet.ChangePassword(current, next))
+}
+
 // Stop the mobile node
 func (m *Mobile) Stop() error {
+	m.mux.Lock()
+	if m.gateway != "" {
+		if err := tcore.Node.StopGateway(); err != nil {
+			log.Errorf("error stopping gateway: %s", err)
+		}
+		m.gateway = ""
+	}
+	m.mux.Unlock()
 	if err := tcore.Node.StopWallet(); err != nil && err != wallet.ErrStopped {
 		return err
 	}
@@ -243,13 +238,30 @@ func (m *Mobile) GetUsername() (string, error) {
 	return tcore.Node.Wallet.GetUsername()
 }
 
-// GetTokens calls core GetTokens
-func (m *Mobile) GetTokens() (string, error) {
+// Tokens returns the current cafe session tokens
+func (m *Mobile) Tokens() (*Tokens, error) {
+	if !m.IsSignedIn() {
+		return nil, wrapError(wallet.ErrNotSignedIn)
+	}
 	tokens, err := tcore.Node.Wallet.GetTokens()
 	if err != nil {
-		return "", err
+		return nil, wrapError(err)
 	}
-	return toJSON(tokens)
+	return newTokens(tokens), nil
+}
+
+// SetPushToken registers a device push token (apns or fcm) with the cafe
+func (m *Mobile) SetPushToken(token string, platform string) error {
+	return wrapError(tcore.Node.Wallet.SetPushToken(token, platform))
+}
+
+// DecryptNotification decrypts a push notification payload, returning thread name and author
+func (m *Mobile) DecryptNotification(payload string) (*Notification, error) {
+	note, err := tcore.Node.Wallet.DecryptNotification(payload)
+	if err != nil {
+		return nil, invalidKey(err)
+	}
+	return newNotification(note), nil
 }
 
 // SetAvatarId calls core SetAvatarId
@@ -257,29 +269,41 @@ func (m *Mobile) SetAvatarId(id string) error {
 	return tcore.Node.Wallet.SetAvatarId(id)
 }
 
-// GetProfile returns this peer's profile
-func (m *Mobile) GetProfile() (string, error) {
-	id, err := tcore.Node.Wallet.GetId()
+// SetAvatar sets a public avatar from an image path, returning the avatar directory id
+func (m *Mobile) SetAvatar(path string) (string, error) {
+	id, err := tcore.Node.Wallet.SetAvatar(path)
 	if err != nil {
-		log.Errorf("error getting id %s: %s", id, err)
-		return "", err
+		return "", wrapError(err)
+	}
+	return id, nil
+}
+
+// SetProfileField sets a public profile field, an empty value clears it
+func (m *Mobile) SetProfileField(key string, value string) error {
+	if err := tcore.Node.Wallet.SetProfileField(key, value); err != nil {
+		return wrapError(err)
 	}
-	prof, err := tcore.Node.Wallet.GetProfile(id)
+	return nil
+}
+
+// Profile returns this peer's profile
+func (m *Mobile) Profile() (*Profile, error) {
+	id, err := tcore.Node.Wallet.GetId()
 	if err != nil {
-		log.Errorf("error getting profile %s: %s", id, err)
-		return "", err
+		log.Errorf("error getting id %s: %s", id, err)
+		return nil, wrapError(err)
 	}
-	return toJSON(prof)
+	return m.PeerProfile(id)
 }
 
-// GetPeerProfile uses a peer id to look up a profile
-func (m *Mobile) GetPeerProfile(peerId string) (string, error) {
+// PeerProfile uses a peer id to look up a profile
+func (m *Mobile) PeerProfile(peerId string) (*Profile, error) {
 	prof, err := tcore.Node.Wallet.GetProfile(peerId)
 	if err != nil {
 		log.Errorf("error getting profile %s: %s", peerId, err)
-		return "", err
+		return nil, wrapError(err)
 	}
-	return toJSON(prof)
+	return newProfile(prof), nil
 }
 
 // RefreshMessages run the message retriever and repointer jobs
@@ -287,97 +311,146 @@ func (m *Mobile) RefreshMessages() error {
 	return tcore.Node.Wallet.RefreshMessages()
 }
 
-// Threads lists all threads
-func (m *Mobile) Threads() (string, error) {
-	threads := Threads{Items: make([]Thread, 0)}
-	for _, thrd := range tcore.Node.Wallet.Threads() {
-		peers := thrd.Peers()
-		item := Thread{Id: thrd.Id, Name: thrd.Name, Peers: len(peers)}
-		threads.Items = append(threads.Items, item)
+// Sync runs one bounded pass of background work, suitable for an os background task
+func (m *Mobile) Sync(timeoutSeconds int) (*SyncSummary, error) {
+	timeout := time.Duration(timeoutSeconds) * time.Second
+	result, err := tcore.Node.Wallet.Sync(timeout, m.notifySyncProgress)
+	if err != nil {
+		return nil, wrapError(err)
 	}
-	return toJSON(threads)
+	return newSyncSummary(result), nil
 }
 
-// PhotoThreads call core PhotoThreads
-func (m *Mobile) PhotoThreads(id string) (string, error) {
-	threads := Threads{Items: make([]Thread, 0)}
-	for _, thrd := range tcore.Node.Wallet.PhotoThreads(id) {
-		peers := thrd.Peers()
-		item := Thread{Id: thrd.Id, Name: thrd.Name, Peers: len(peers)}
-		threads.Items = append(threads.Items, item)
+// notifySyncProgress passes sync progress to messenger
+func (m *Mobile) notifySyncProgress(prog *wallet.SyncProgress) {
+	payload, err := toJSON(prog)
+	if err != nil {
+		return
 	}
-	return toJSON(threads)
+	m.messenger.Notify(&Event{Name: "onSyncProgress", Payload: payload})
+}
+
+// SyncThread pulls missed blocks in a thread from its peers, returning the number of new blocks
+func (m *Mobile) SyncThread(threadId string) (int, error) {
+	count, err := tcore.Node.Wallet.SyncThread(threadId, m.notifyThreadSyncProgress)
+	if err != nil {
+		return 0, wrapError(err)
+	}
+	return count, nil
+}
+
+// notifyThreadSyncProgress passes thread sync progress to messenger
+func (m *Mobile) notifyThreadSyncProgress(prog *wallet.ThreadSyncProgress) {
+	payload, err := toJSON(prog)
+	if err != nil {
+		return
+	}
+	m.messenger.Notify(&Event{Name: "onThreadSyncProgress", Payload: payload})
+}
+
+// IsIdentityDerived returns whether or not the account can be restored from its mnemonic.
+// Accounts created before identities were derived from the mnemonic can't be recovered.
+func (m *Mobile) IsIdentityDerived() bool {
+	return tcore.Node.Wallet.IsIdentityDerived()
 }
 
-// AddThread adds a new thread with the given name
-func (m *Mobile) AddThread(name string, mnemonic string) (string, error) {
+// Recover restores threads and devices from the account backup published with the profile
+func (m *Mobile) Recover() (*RecoverSummary, error) {
+	result, err := tcore.Node.Wallet.Recover()
+	if err != nil {
+		return nil, wrapError(err)
+	}
+	return newRecoverSummary(result), nil
+}
+
+// ThreadList lists all threads
+func (m *Mobile) ThreadList() (*Threads, error) {
+	return newThreads(tcore.Node.Wallet.Threads()), nil
+}
+
+// PhotoThreadList lists the threads containing a photo
+func (m *Mobile) PhotoThreadList(id string) (*Threads, error) {
+	return newThreads(tcore.Node.Wallet.PhotoThreads(id)), nil
+}
+
+// CreateThread adds a new thread with the given name
+func (m *Mobile) CreateThread(name string, mnemonic string) (*Thread, error) {
 	var mnem *string
 	if mnemonic != "" {
 		mnem = &mnemonic
 	}
 	thrd, _, err := tcore.Node.Wallet.AddThreadWithMnemonic(name, mnem)
 	if err != nil {
-		return "", err
+		return nil, wrapError(err)
 	}
-
-	// build json
 	peers := thrd.Peers()
-	item := Thread{
-		Id:    thrd.Id,
-		Name:  thrd.Name,
-		Peers: len(peers),
-	}
-	return toJSON(item)
+	name, description, coverId := thrd.Metadata()
+	return &Thread{
+		Id:          thrd.Id,
+		Name:        name,
+		Description: description,
+		CoverId:     coverId,
+		Peers:       len(peers),
+	}, nil
 }
 
 // AddThreadInvite adds a new invite to a thread
 func (m *Mobile) AddThreadInvite(threadId string, inviteePk string) (string, error) {
-	_, thrd := tcore.Node.Wallet.GetThread(threadId)
-	if thrd == nil {
-		return "", errors.New(fmt.Sprintf("could not find thread: %s", threadId))
+	thrd, err := m.getThread(threadId)
+	if err != nil {
+		return "", err
 	}
 
 	// decode pubkey
 	ikb, err := libp2pc.ConfigDecodeKey(inviteePk)
 	if err != nil {
-		return "", err
+		return "", invalidKey(err)
 	}
 	ipk, err := libp2pc.UnmarshalPublicKey(ikb)
 	if err != nil {
-		return "", err
+		return "", invalidKey(err)
 	}
 
 	// add it
 	addr, err := thrd.AddInvite(ipk)
 	if err != nil {
-		return "", err
+		return "", wrapError(err)
 	}
 
 	return addr.B58String(), nil
 }
 
-// AddExternalThreadInvite generates a new external invite link to a thread
-func (m *Mobile) AddExternalThreadInvite(threadId string) (string, error) {
-	_, thrd := tcore.Node.Wallet.GetThread(threadId)
-	if thrd == nil {
-		return "", errors.New(fmt.Sprintf("could not find thread: %s", threadId))
+// AddThreadInviteByContact invites a contact, by peer id or username, to a thread
+func (m *Mobile) AddThreadInviteByContact(threadId string, contact string) (string, error) {
+	addr, err := tcore.Node.Wallet.InviteContact(threadId, contact)
+	if err != nil {
+		return "", wrapError(err)
 	}
+	return addr.B58String(), nil
+}
 
+// CreateExternalThreadInvite generates a new external invite link to a thread, which expires
+// after ttlSeconds and can be used maxUses times (zero for no limit)
+func (m *Mobile) CreateExternalThreadInvite(threadId string, ttlSeconds int, maxUses int) (*ExternalInvite, error) {
 	// add it
-	addr, key, err := thrd.AddExternalInvite()
+	invite, err := tcore.Node.Wallet.CreateExternalInvite(threadId, time.Duration(ttlSeconds)*time.Second, maxUses)
 	if err != nil {
-		return "", err
+		return nil, wrapError(err)
 	}
 
 	// create a structured invite
 	username, _ := m.GetUsername()
-	invite := ExternalInvite{
-		Id:      addr.B58String(),
-		Key:     string(key),
+	ext := &ExternalInvite{
+		Id:      invite.Id,
+		Key:     invite.Key,
+		Link:    invite.Link,
+		MaxUses: invite.MaxUses,
 		Inviter: username,
 	}
-
-	return toJSON(invite)
+	if !invite.Expires.IsZero() {
+		ext.Expires = invite.Expires.Unix()
+	}
+	return ext, nil
 }
 
 // AcceptExternalThreadInvite notifies the thread of a join
@@ -385,7 +458,53 @@ func (m *Mobile) AcceptExternalThreadInvite(id string, key string) (string, erro
 	m.waitForOnline()
 	addr, err := tcore.Node.Wallet.AcceptExternalThreadInvite(id, []byte(key))
 	if err != nil {
-		return "", err
+		return "", wrapError(err)
+	}
+	return addr.B58String(), nil
+}
+
+// AcceptExternalThreadInviteLink accepts a textile://invite link
+func (m *Mobile) AcceptExternalThreadInviteLink(link string) (string, error) {
+	m.waitForOnline()
+	addr, err := tcore.Node.Wallet.AcceptExternalThreadInviteLink(link)
+	if err != nil {
+		return "", wrapError(err)
+	}
+	return addr.B58String(), nil
+}
+
+// RevokeExternalThreadInvite revokes an external invite by id
+func (m *Mobile) RevokeExternalThreadInvite(id string) (string, error) {
+	addr, err := tcore.Node.Wallet.RevokeExternalInvite(id)
+	if err != nil {
+		return "", wrapError(err)
+	}
+	return addr.B58String(), nil
+}
+
+// RenameThread adds a metadata block with a new thread name
+func (m *Mobile) RenameThread(threadId string, name string) (string, error) {
+	addr, err := tcore.Node.Wallet.RenameThread(threadId, name)
+	if err != nil {
+		return "", wrapError(err)
+	}
+	return addr.B58String(), nil
+}
+
+// DescribeThread adds a metadata block with a new thread description
+func (m *Mobile) DescribeThread(threadId string, description string) (string, error) {
+	addr, err := tcore.Node.Wallet.DescribeThread(threadId, description)
+	if err != nil {
+		return "", wrapError(err)
+	}
+	return addr.B58String(), nil
+}
+
+// SetThreadCover adds a metadata block that uses a thread photo as the cover
+func (m *Mobile) SetThreadCover(threadId string, blockId string) (string, error) {
+	addr, err := tcore.Node.Wallet.SetThreadCover(threadId, blockId)
+	if err != nil {
+		return "", wrapError(err)
 	}
 	return addr.B58String(), nil
 }
@@ -394,19 +513,96 @@ func (m *Mobile) AcceptExternalThreadInvite(id string, key string) (string, erro
 func (m *Mobile) RemoveThread(id string) (string, error) {
 	addr, err := tcore.Node.Wallet.RemoveThread(id)
 	if err != nil {
-		return "", err
+		return "", wrapError(err)
 	}
-	return addr.B58String(), err
+	return addr.B58String(), nil
 }
 
-// Devices lists all devices
-func (m *Mobile) Devices() (string, error) {
-	devices := Devices{Items: make([]Device, 0)}
+// Search finds photos by caption or name, optionally within a thread and a date
+// range (unix seconds, zero to ignore)
+func (m *Mobile) Search(query string, threadId string, after int64, before int64, limit int) (*SearchResults, error) {
+	filters := &repo.SearchFilters{ThreadId: threadId, Limit: limit}
+	if after > 0 {
+		filters.After = time.Unix(after, 0)
+	}
+	if before > 0 {
+		filters.Before = time.Unix(before, 0)
+	}
+	entries, err := tcore.Node.Wallet.Search(query, filters)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+	results := &SearchResults{Items: make([]SearchResult, 0)}
+	for _, entry := range entries {
+		results.Items = append(results.Items, *newSearchResult(&entry))
+	}
+	return results, nil
+}
+
+// ContactList lists known contacts, most recently seen first
+func (m *Mobile) ContactList() (*Contacts, error) {
+	contacts := &Contacts{Items: make([]Contact, 0)}
+	for _, contact := range tcore.Node.Wallet.Contacts() {
+		contacts.Items = append(contacts.Items, *newContact(&contact))
+	}
+	return contacts, nil
+}
+
+// AddContact adds a contact by public key
+func (m *Mobile) AddContact(pubKey string) (*Contact, error) {
+	pkb, err := libp2pc.ConfigDecodeKey(pubKey)
+	if err != nil {
+		return nil, invalidKey(err)
+	}
+	pk, err := libp2pc.UnmarshalPublicKey(pkb)
+	if err != nil {
+		return nil, invalidKey(err)
+	}
+	contact, err := tcore.Node.Wallet.AddContact(pk)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+	return newContact(contact), nil
+}
+
+// FindContact looks up a username in the cafe directory and adds it as a contact
+func (m *Mobile) FindContact(username string) (*Contact, error) {
+	contact, err := tcore.Node.Wallet.FindContact(username)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+	return newContact(contact), nil
+}
+
+// SetDirectoryVisibility sets how others can find us in the cafe directory:
+// "hidden", "username", or "identities"
+func (m *Mobile) SetDirectoryVisibility(visibility string) error {
+	return wrapError(tcore.Node.Wallet.SetDirectoryVisibility(models.DirectoryVisibility(visibility)))
+}
+
+// RefreshContact resolves a contact's profile
+func (m *Mobile) RefreshContact(id string) (*Contact, error) {
+	m.waitForOnline()
+	contact, err := tcore.Node.Wallet.RefreshContact(id)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+	return newContact(contact), nil
+}
+
+// RemoveContact removes a contact
+func (m *Mobile) RemoveContact(id string) error {
+	return wrapError(tcore.Node.Wallet.RemoveContact(id))
+}
+
+// DeviceList lists all devices
+func (m *Mobile) DeviceList() (*Devices, error) {
+	devices := &Devices{Items: make([]Device, 0)}
 	for _, dev := range tcore.Node.Wallet.Devices() {
 		item := Device{Id: dev.Id, Name: dev.Name}
 		devices.Items = append(devices.Items, item)
 	}
-	return toJSON(devices)
+	return devices, nil
 }
 
 // AddDevice calls core AddDevice
@@ -414,177 +610,376 @@ func (m *Mobile) AddDevice(name string, pubKey string) error {
 	m.waitForOnline()
 	pkb, err := libp2pc.ConfigDecodeKey(pubKey)
 	if err != nil {
-		return err
+		return invalidKey(err)
 	}
 	pk, err := libp2pc.UnmarshalPublicKey(pkb)
 	if err != nil {
-		return err
+		return invalidKey(err)
 	}
-	return tcore.Node.Wallet.AddDevice(name, pk)
+	return wrapError(tcore.Node.Wallet.AddDevice(name, pk))
+}
+
+// NewPairingRequest creates a one-time code for pairing this device, the link is meant for a qr code
+func (m *Mobile) NewPairingRequest() (*PairingRequest, error) {
+	req, err := tcore.Node.Wallet.NewPairingRequest()
+	if err != nil {
+		return nil, wrapError(err)
+	}
+	return newPairingRequest(req), nil
+}
+
+// PairDevice pairs the device that shows link and invites it to all threads
+func (m *Mobile) PairDevice(link string, name string) (*Device, error) {
+	m.waitForOnline()
+	dev, err := tcore.Node.Wallet.PairDevice(link, name)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+	return &Device{Id: dev.Id, Name: dev.Name}, nil
 }
 
 // RemoveDevice call core RemoveDevice
 func (m *Mobile) RemoveDevice(id string) error {
-	return tcore.Node.Wallet.RemoveDevice(id)
+	return wrapError(tcore.Node.Wallet.RemoveDevice(id))
 }
 
-// AddPhoto adds a photo by path
-func (m *Mobile) AddPhoto(path string) (string, error) {
+// CreatePhoto adds a photo by path
+func (m *Mobile) CreatePhoto(path string) (*AddedPhoto, error) {
 	added, err := tcore.Node.Wallet.AddPhoto(path)
 	if err != nil {
-		return "", err
+		return nil, wrapError(err)
+	}
+	photo := &AddedPhoto{Id: added.Id, Key: added.Key, Duplicate: added.Duplicate}
+	if added.Archive != nil {
+		photo.ArchivePath = added.Archive.Path
 	}
-	return toJSON(added)
+	return photo, nil
 }
 
-// SharePhoto adds an existing photo to a new thread
+// AddPhotoToThread adds an existing photo to a new thread
 func (m *Mobile) AddPhotoToThread(dataId string, key string, threadId string, caption string) (string, error) {
-	_, thrd := tcore.Node.Wallet.GetThread(threadId)
-	if thrd == nil {
-		return "", errors.New(fmt.Sprintf("could not find thread %s", threadId))
+	thrd, err := m.getThread(threadId)
+	if err != nil {
+		return "", err
 	}
 
 	addr, err := thrd.AddPhoto(dataId, caption, []byte(key))
 	if err != nil {
-		return "", err
+		return "", wrapError(err)
 	}
 
 	return addr.B58String(), nil
 }
 
-// SharePhoto adds an existing photo to a new thread
+// SharePhotoToThread adds an existing photo to a new thread
 func (m *Mobile) SharePhotoToThread(dataId string, threadId string, caption string) (string, error) {
 	block, err := tcore.Node.Wallet.GetBlockByDataId(dataId)
 	if err != nil {
-		return "", err
+		return "", wrapError(err)
 	}
-	_, fromThread := tcore.Node.Wallet.GetThread(block.ThreadId)
-	if fromThread == nil {
-		return "", errors.New(fmt.Sprintf("could not find thread %s", block.ThreadId))
+	fromThread, err := m.getThread(block.ThreadId)
+	if err != nil {
+		return "", err
 	}
-	_, toThread := tcore.Node.Wallet.GetThread(threadId)
-	if toThread == nil {
-		return "", errors.New(fmt.Sprintf("could not find thread %s", threadId))
+	toThread, err := m.getThread(threadId)
+	if err != nil {
+		return "", err
 	}
 	key, err := fromThread.Decrypt(block.DataKeyCipher)
 	if err != nil {
-		return "", err
+		return "", invalidKey(err)
 	}
 
 	// TODO: owner challenge
 	addr, err := toThread.AddPhoto(dataId, caption, key)
 	if err != nil {
-		return "", err
+		return "", wrapError(err)
 	}
 
 	return addr.B58String(), nil
 }
 
-// GetPhotos returns thread photo blocks with json encoding
-func (m *Mobile) GetPhotos(offsetId string, limit int, threadId string) (string, error) {
-	_, thrd := tcore.Node.Wallet.GetThread(threadId)
-	if thrd == nil {
-		return "", errors.New(fmt.Sprintf("thread not found: %s", threadId))
+// PhotoList returns thread photo blocks
+func (m *Mobile) PhotoList(offsetId string, limit int, threadId string) (*Photos, error) {
+	thrd, err := m.getThread(threadId)
+	if err != nil {
+		return nil, err
 	}
 
-	// build json
-	photos := &Photos{Items: make([]Photo, 0)}
-	for _, b := range thrd.Blocks(offsetId, limit, repo.PhotoBlock) {
-		var caption string
-		if b.DataCaptionCipher != nil {
-			captionb, err := thrd.Decrypt(b.DataCaptionCipher)
-			if err != nil {
-				return "", err
-			}
-			caption = string(captionb)
+	photos, err := newPhotos(thrd, thrd.Blocks(offsetId, limit, repo.PhotoBlock))
+	if err != nil {
+		return nil, err
+	}
+
+	// check for offline messages
+	m.RefreshMessages()
+
+	return photos, nil
+}
+
+// CreateAlbum adds an album to a thread
+func (m *Mobile) CreateAlbum(threadId string, name string) (string, error) {
+	thrd, err := m.getThread(threadId)
+	if err != nil {
+		return "", err
+	}
+	addr, err := thrd.AddAlbum(name)
+	if err != nil {
+		return "", wrapError(err)
+	}
+	return addr.B58String(), nil
+}
+
+// AlbumList returns thread album blocks
+func (m *Mobile) AlbumList(offsetId string, limit int, threadId string) (*Albums, error) {
+	thrd, err := m.getThread(threadId)
+	if err != nil {
+		return nil, err
+	}
+
+	albums := &Albums{Items: make([]Album, 0)}
+	for _, b := range thrd.Albums(offsetId, limit) {
+		name, err := thrd.Decrypt(b.DataCaptionCipher)
+		if err != nil {
+			log.Warningf("skipping album %s with unreadable name: %s", b.Id, err)
+			continue
 		}
 		authorId, err := util.IdFromEncodedPublicKey(b.AuthorPk)
 		if err != nil {
-			return "", err
+			return nil, invalidKey(err)
 		}
-		photos.Items = append(photos.Items, Photo{
-			Id:       b.DataId,
+		albums.Items = append(albums.Items, Album{
+			Id:       b.Id,
+			ThreadId: b.ThreadId,
+			Name:     string(name),
 			Date:     b.Date,
-			Caption:  string(caption),
 			AuthorId: authorId.Pretty(),
 		})
 	}
+	return albums, nil
+}
 
-	// check for offline messages
-	m.RefreshMessages()
+// AlbumPhotoList returns the photo blocks in an album
+func (m *Mobile) AlbumPhotoList(offsetId string, limit int, albumId string) (*Photos, error) {
+	thrd, err := m.getAlbumThread(albumId)
+	if err != nil {
+		return nil, err
+	}
+	blocks, err := thrd.AlbumPhotos(albumId, offsetId, limit)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+	return newPhotos(thrd, blocks)
+}
+
+// AddPhotoToAlbum adds a thread photo to an album
+func (m *Mobile) AddPhotoToAlbum(albumId string, dataId string) (string, error) {
+	thrd, err := m.getAlbumThread(albumId)
+	if err != nil {
+		return "", err
+	}
+	addr, err := thrd.AddToAlbum(albumId, dataId)
+	if err != nil {
+		return "", wrapError(err)
+	}
+	return addr.B58String(), nil
+}
+
+// RemovePhotoFromAlbum takes a photo out of an album
+func (m *Mobile) RemovePhotoFromAlbum(albumId string, dataId string) (string, error) {
+	thrd, err := m.getAlbumThread(albumId)
+	if err != nil {
+		return "", err
+	}
+	addr, err := thrd.RemoveFromAlbum(albumId, dataId)
+	if err != nil {
+		return "", wrapError(err)
+	}
+	return addr.B58String(), nil
+}
+
+// IgnorePhoto adds an ignore block for a photo block, hiding it from the thread
+func (m *Mobile) IgnorePhoto(blockId string) (string, error) {
+	block, err := tcore.Node.Wallet.GetBlock(blockId)
+	if err != nil {
+		return "", wrapError(err)
+	}
+	thrd, err := m.getThread(block.ThreadId)
+	if err != nil {
+		return "", err
+	}
+	addr, err := thrd.Ignore(block.Id)
+	if err != nil {
+		return "", wrapError(err)
+	}
+	return addr.B58String(), nil
+}
+
+// UnignorePhoto adds a restore block for an ignored photo block
+func (m *Mobile) UnignorePhoto(blockId string) (string, error) {
+	block, err := tcore.Node.Wallet.GetBlock(blockId)
+	if err != nil {
+		return "", wrapError(err)
+	}
+	thrd, err := m.getThread(block.ThreadId)
+	if err != nil {
+		return "", err
+	}
+	addr, err := thrd.Restore(block.Id)
+	if err != nil {
+		return "", wrapError(err)
+	}
+	return addr.B58String(), nil
+}
 
-	return toJSON(photos)
+// EditPhotoCaption adds a caption block replacing the caption of one of our photo blocks
+func (m *Mobile) EditPhotoCaption(blockId string, caption string) (string, error) {
+	block, err := tcore.Node.Wallet.GetBlock(blockId)
+	if err != nil {
+		return "", wrapError(err)
+	}
+	thrd, err := m.getThread(block.ThreadId)
+	if err != nil {
+		return "", err
+	}
+	addr, err := thrd.EditCaption(block.Id, caption)
+	if err != nil {
+		return "", wrapError(err)
+	}
+	return addr.B58String(), nil
 }
 
-// GetPhotoData returns a data url for a photo
-func (m *Mobile) GetPhotoData(id string) (string, error) {
+// PhotoImage returns a data url for a photo
+func (m *Mobile) PhotoImage(id string) (*ImageData, error) {
 	return m.getImageData(id, "photo", false)
 }
 
-// GetPhotoData returns a data url for a photo
-func (m *Mobile) GetThumbData(id string) (string, error) {
+// ThumbImage returns a data url for a photo thumbnail
+func (m *Mobile) ThumbImage(id string) (*ImageData, error) {
 	return m.getImageData(id, "thumb", true)
 }
 
-// GetPhotoMetadata returns a meta data object for a photo
-func (m *Mobile) GetPhotoMetadata(id string) (string, error) {
+// WritePhoto decrypts a photo variant (photo, thumb, or a rendition name like medium) to a file at path
+func (m *Mobile) WritePhoto(id string, variant string, path string) error {
+	reader, err := tcore.Node.Wallet.GetPhotoReader(id, variant)
+	if err != nil {
+		return wrapError(err)
+	}
+	defer reader.Close()
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(file, reader); err != nil {
+		file.Close()
+		return wrapError(err)
+	}
+	return file.Close()
+}
+
+// PhotoUrl returns a loopback url serving a photo variant (photo, thumb, or a rendition name like medium)
+// The photo is decrypted as it is streamed, the url only works from this device and is valid until the node is stopped
+func (m *Mobile) PhotoUrl(id string, variant string) (string, error) {
+	if _, err := tcore.Node.Wallet.GetBlockByDataId(id); err != nil {
+		return "", wrapError(err)
+	}
+	m.mux.Lock()
+	defer m.mux.Unlock()
+	if m.gateway == "" {
+		if err := tcore.Node.StartGateway(fmt.Sprintf("127.0.0.1:%d", rconfig.GetRandomPort())); err != nil {
+			return "", err
+		}
+		m.gateway = fmt.Sprintf("http://%s", tcore.Node.GetGatewayAddr())
+	}
+	return fmt.Sprintf("%s/photos/%s/%s?token=%s", m.gateway, id, variant, tcore.Node.GetPhotoToken()), nil
+}
+
+// PhotoMetadata returns a meta data object for a photo
+func (m *Mobile) PhotoMetadata(id string) (*PhotoMeta, error) {
+	meta, err := m.getPhotoMetadata(id)
+	if err != nil {
+		return nil, err
+	}
+	return newPhotoMeta(meta), nil
+}
+
+// GetPhotoKey calls core GetPhotoKey
+func (m *Mobile) GetPhotoKey(id string) (string, error) {
+	key, err := tcore.Node.Wallet.GetPhotoKey(id)
+	if err != nil {
+		return "", wrapError(err)
+	}
+	return key, nil
+}
+
+// getThread returns a loaded thread or a not found error
+func (m *Mobile) getThread(id string) (*thread.Thread, error) {
+	_, thrd := tcore.Node.Wallet.GetThread(id)
+	if thrd == nil {
+		return nil, newError(ErrCodeNotFound, "could not find thread: %s", id)
+	}
+	return thrd, nil
+}
+
+// getAlbumThread returns the thread an album block belongs to
+func (m *Mobile) getAlbumThread(albumId string) (*thread.Thread, error) {
+	block, err := tcore.Node.Wallet.GetBlock(albumId)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+	return m.getThread(block.ThreadId)
+}
+
+// getPhotoMetadata loads photo meta data via its block
+func (m *Mobile) getPhotoMetadata(id string) (*model.PhotoMetadata, error) {
 	block, err := tcore.Node.Wallet.GetBlockByDataId(id)
 	if err != nil {
 		log.Errorf("could not find block for data id %s: %s", id, err)
-		return "", err
+		return nil, wrapError(err)
 	}
-	_, thrd := tcore.Node.Wallet.GetThread(block.ThreadId)
-	if thrd == nil {
-		err := errors.New(fmt.Sprintf("could not find thread: %s", block.ThreadId))
+	thrd, err := m.getThread(block.ThreadId)
+	if err != nil {
 		log.Error(err.Error())
-		return "", err
+		return nil, err
 	}
 	meta, err := thrd.GetPhotoMetaData(id, block)
 	if err != nil {
 		log.Errorf("get photo meta data failed %s: %s", id, err)
-		return "", err
+		return nil, wrapError(err)
 	}
-	return toJSON(meta)
-}
-
-// GetPhotoKey calls core GetPhotoKey
-func (m *Mobile) GetPhotoKey(id string) (string, error) {
-	return tcore.Node.Wallet.GetPhotoKey(id)
+	return meta, nil
 }
 
 // getImageData returns a data url for an image under a path
-func (m *Mobile) getImageData(id string, path string, isThumb bool) (string, error) {
+func (m *Mobile) getImageData(id string, path string, isThumb bool) (*ImageData, error) {
 	block, err := tcore.Node.Wallet.GetBlockByDataId(id)
 	if err != nil {
 		log.Errorf("could not find block for data id %s: %s", id, err)
-		return "", err
+		return nil, wrapError(err)
 	}
-	_, thrd := tcore.Node.Wallet.GetThread(block.ThreadId)
-	if thrd == nil {
-		err := errors.New(fmt.Sprintf("could not find thread: %s", block.ThreadId))
+	thrd, err := m.getThread(block.ThreadId)
+	if err != nil {
 		log.Error(err.Error())
-		return "", err
+		return nil, err
 	}
 	url, err := thrd.GetBlockDataBase64(fmt.Sprintf("%s/%s", id, path), block)
 	if err != nil {
 		log.Errorf("get block data base64 failed %s: %s", id, err)
-		return "", err
+		return nil, wrapError(err)
 	}
 
 	// get meta data for url type
 	meta, err := thrd.GetPhotoMetaData(id, block)
 	if err != nil {
 		log.Errorf("get photo meta data failed %s: %s", id, err)
-		return "", err
+		return nil, wrapError(err)
 	}
 	if isThumb {
 		url = getThumbDataURLPrefix(meta) + url
 	} else {
 		url = getPhotoDataURLPrefix(meta) + url
 	}
-	data := &ImageData{Url: url, Metadata: meta}
-
-	return toJSON(data)
+	return &ImageData{Url: url, Metadata: meta}, nil
 }
 
 // subscribe to thread and pass updates to messenger
@@ -642,6 +1037,24 @@ func getThumbDataURLPrefix(meta *model.PhotoMetadata) string {
 	}
 }
 
+// newThreads wraps a list of threads
+func newThreads(list []*thread.Thread) *Threads {
+	threads := &Threads{Items: make([]Thread, 0)}
+	for _, thrd := range list {
+		peers := thrd.Peers()
+		name, description, coverId := thrd.Metadata()
+		item := Thread{
+			Id:          thrd.Id,
+			Name:        name,
+			Description: description,
+			CoverId:     coverId,
+			Peers:       len(peers),
+		}
+		threads.Items = append(threads.Items, item)
+	}
+	return threads
+}
+
 // toJSON returns a json string and logs errors
 func toJSON(any interface{}) (string, error) {
 	jsonb, err := json.Marshal(any)
@@ -651,3 +1064,30 @@ func toJSON(any interface{}) (string, error) {
 	}
 	return string(jsonb), nil
 }
+
+// newPhotos decrypts photo blocks into bindable photos
+func newPhotos(thrd *thread.Thread, blocks []repo.Block) (*Photos, error) {
+	photos := &Photos{Items: make([]Photo, 0)}
+	for _, b := range blocks {
+		var caption string
+		if b.DataCaptionCipher != nil {
+			captionb, err := thrd.Decrypt(b.DataCaptionCipher)
+			if err != nil {
+				return nil, invalidKey(err)
+			}
+			caption = string(captionb)
+		}
+		authorId, err := util.IdFromEncodedPublicKey(b.AuthorPk)
+		if err != nil {
+			return nil, invalidKey(err)
+		}
+		photos.Items = append(photos.Items, Photo{
+			Id:       b.DataId,
+			BlockId:  b.Id,
+			Date:     b.Date,
+			Caption:  string(caption),
+			AuthorId: authorId.Pretty(),
+		})
+	}
+	return photos, nil
+}
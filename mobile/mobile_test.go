@@ -1,16 +1,26 @@
 package mobile_test
 
 import (
+	"archive/tar"
+	"compress/gzip"
 	"crypto/rand"
+	"encoding/base64"
 	"encoding/json"
+	"fmt"
 	"github.com/segmentio/ksuid"
 	"github.com/textileio/textile-go/core"
+	"github.com/textileio/textile-go/crypto"
 	. "github.com/textileio/textile-go/mobile"
+	tutil "github.com/textileio/textile-go/util"
 	util "github.com/textileio/textile-go/util/testing"
 	"github.com/textileio/textile-go/wallet"
 	"github.com/textileio/textile-go/wallet/model"
 	libp2pc "gx/ipfs/QmaPbCnUMBohSGo3KnxEa2bHqyJVVeEEcwtqJAYxerieBo/go-libp2p-crypto"
+	"io"
+	"io/ioutil"
+	"net/http"
 	"os"
+	"strings"
 	"testing"
 )
 
@@ -171,6 +181,24 @@ func TestMobile_Threads(t *testing.T) {
 	}
 }
 
+func TestMobile_ThreadList(t *testing.T) {
+	threads, err := mobile.ThreadList()
+	if err != nil {
+		t.Errorf("list threads failed: %s", err)
+		return
+	}
+	if threads.Count() != 2 {
+		t.Error("list threads bad result")
+		return
+	}
+	if threads.Get(1).Id != threadId {
+		t.Error("list threads bad item")
+	}
+	if threads.Get(2) != nil {
+		t.Error("list threads out of range item should be nil")
+	}
+}
+
 func TestMobile_RemoveThread(t *testing.T) {
 	<-core.Node.Wallet.Online()
 	blockId, err := mobile.RemoveThread(defaultThreadId)
@@ -259,6 +287,16 @@ func TestMobile_AddPhoto(t *testing.T) {
 		t.Error("add photo result should have an archive")
 		return
 	}
+	names, err := archiveNames(res.Archive.Path)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	for _, name := range []string{"photo", "thumb", "meta", "pk"} {
+		if !names[name] {
+			t.Errorf("archive is missing %s", name)
+		}
+	}
 	addedPhotoKey = res.Key
 	addedPhotoId = res.Id
 }
@@ -309,6 +347,17 @@ func TestMobile_GetPhotosBadThread(t *testing.T) {
 	}
 }
 
+func TestMobile_PhotoListBadThread(t *testing.T) {
+	_, err := mobile.PhotoList("", -1, "empty")
+	if err == nil {
+		t.Error("list photos from bad thread should fail")
+		return
+	}
+	if ErrorCode(err.Error()) != ErrCodeNotFound {
+		t.Errorf("list photos from bad thread got bad error code: %s", err)
+	}
+}
+
 func TestMobile_PhotoThreads(t *testing.T) {
 	res, err := mobile.PhotoThreads(addedPhotoId)
 	if err != nil {
@@ -336,6 +385,27 @@ func TestMobile_GetPhotoData(t *testing.T) {
 	}
 }
 
+func TestMobile_ErrorCodes(t *testing.T) {
+	_, err := mobile.PhotoImage("QmNope")
+	if err == nil {
+		t.Error("get missing photo should fail")
+		return
+	}
+	serr, ok := err.(*Error)
+	if !ok || serr.Code != ErrCodeNotFound || ErrorCode(err.Error()) != ErrCodeNotFound {
+		t.Errorf("missing photo should have not found code: %s", err)
+	}
+	if serr != nil && serr.Message() != wallet.ErrBlockNotFound.Error() {
+		t.Errorf("bad error message: %s", serr.Message())
+	}
+	if err.Error() != wallet.ErrBlockNotFound.Error() {
+		t.Errorf("error message should be unchanged: %s", err)
+	}
+	if _, err := mobile.GetPhotoData("QmNope"); err != wallet.ErrBlockNotFound {
+		t.Errorf("legacy error should unwrap to cause: %s", err)
+	}
+}
+
 func TestMobile_GetThumbData(t *testing.T) {
 	res, err := mobile.GetThumbData(addedPhotoId)
 	if err != nil {
@@ -347,6 +417,86 @@ func TestMobile_GetThumbData(t *testing.T) {
 	}
 }
 
+func TestMobile_WritePhoto(t *testing.T) {
+	path := "testdata/medium.jpg"
+	defer os.Remove(path)
+	if err := mobile.WritePhoto(addedPhotoId, "medium", path); err != nil {
+		t.Errorf("write photo failed: %s", err)
+		return
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if info.Size() == 0 {
+		t.Error("write photo bad result")
+	}
+	if err := mobile.WritePhoto(addedPhotoId, "huge", path); err == nil {
+		t.Error("write photo with bad variant should fail")
+	}
+}
+
+func TestMobile_PhotoUrl(t *testing.T) {
+	url, err := mobile.PhotoUrl(addedPhotoId, "thumb")
+	if err != nil {
+		t.Errorf("get photo url failed: %s", err)
+		return
+	}
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	req.Header.Set("Range", "bytes=0-9")
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Errorf("get photo url data failed: %s", err)
+		return
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusPartialContent {
+		t.Errorf("got bad status: %d", res.StatusCode)
+		return
+	}
+	body, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if len(body) != 10 {
+		t.Error("get photo url range bad result")
+	}
+
+	// a range past the end is clamped to the real length
+	req.Header.Set("Range", "bytes=0-99999999")
+	res, err = http.DefaultClient.Do(req)
+	if err != nil {
+		t.Errorf("get photo url data failed: %s", err)
+		return
+	}
+	defer res.Body.Close()
+	body, err = ioutil.ReadAll(res.Body)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if res.Header.Get("Content-Range") != fmt.Sprintf("bytes 0-%d/%d", len(body)-1, len(body)) {
+		t.Errorf("range not clamped: %s", res.Header.Get("Content-Range"))
+	}
+
+	// the token is required
+	res, err = http.Get(strings.Split(url, "?")[0])
+	if err != nil {
+		t.Errorf("get photo url data failed: %s", err)
+		return
+	}
+	res.Body.Close()
+	if res.StatusCode != http.StatusForbidden {
+		t.Errorf("photo without token got status: %d", res.StatusCode)
+	}
+}
+
 func TestMobile_GetPhotoMetadata(t *testing.T) {
 	res, err := mobile.GetPhotoMetadata(addedPhotoId)
 	if err != nil {
@@ -395,6 +545,48 @@ func TestMobile_GetProfile(t *testing.T) {
 	}
 }
 
+func TestMobile_Sync(t *testing.T) {
+	res, err := mobile.Sync(30)
+	if err != nil {
+		t.Errorf("sync failed: %s", err)
+		return
+	}
+	if !res.TimedOut && res.Finished != len(wallet.SyncSteps) {
+		t.Error("sync did not finish all steps")
+	}
+}
+
+func TestMobile_DecryptNotification(t *testing.T) {
+	pks, err := mobile.GetPubKey()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	pk, err := tutil.UnmarshalPublicKeyFromString(pks)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	plaintext, err := json.Marshal(&model.Notification{ThreadName: "wedding", AuthorId: "QmAuthor"})
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	ciphertext, err := crypto.Encrypt(pk, plaintext)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	note, err := mobile.DecryptNotification(base64.StdEncoding.EncodeToString(ciphertext))
+	if err != nil {
+		t.Errorf("decrypt notification failed: %s", err)
+		return
+	}
+	if note.ThreadName != "wedding" || note.AuthorId != "QmAuthor" {
+		t.Error("decrypted notification mismatch")
+	}
+}
+
 func TestMobile_SignOut(t *testing.T) {
 	if err := mobile.SignOut(); err != nil {
 		t.Errorf("signout failed: %s", err)
@@ -429,3 +621,28 @@ func TestMobile_SignInAgain(t *testing.T) {
 func Test_Teardown(t *testing.T) {
 	os.RemoveAll(mobile.RepoPath)
 }
+
+// archiveNames returns the file names in a gzipped tar archive
+func archiveNames(path string) (map[string]bool, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	gr, err := gzip.NewReader(file)
+	if err != nil {
+		return nil, err
+	}
+	tr := tar.NewReader(gr)
+	names := make(map[string]bool)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			return names, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		names[header.Name] = true
+	}
+}
@@ -0,0 +1,422 @@
+package mobile
+
+import (
+	"github.com/textileio/textile-go/repo"
+	"github.com/textileio/textile-go/wallet"
+	"github.com/textileio/textile-go/wallet/model"
+	"time"
+)
+
+// Thread is a simple meta data wrapper around a Thread
+type Thread struct {
+	Id          string `json:"id"`
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	CoverId     string `json:"cover_id,omitempty"`
+	Peers       int    `json:"peers"`
+}
+
+// Threads is a wrapper around a list of Threads
+type Threads struct {
+	Items []Thread `json:"items"`
+}
+
+// Count returns the number of threads
+func (t *Threads) Count() int {
+	return len(t.Items)
+}
+
+// Get returns the thread at index i
+func (t *Threads) Get(i int) *Thread {
+	if i < 0 || i >= len(t.Items) {
+		return nil
+	}
+	return &t.Items[i]
+}
+
+// Device is a simple meta data wrapper around a Device
+type Device struct {
+	Id   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// Devices is a wrapper around a list of Devices
+type Devices struct {
+	Items []Device `json:"items"`
+}
+
+// Count returns the number of devices
+func (d *Devices) Count() int {
+	return len(d.Items)
+}
+
+// Get returns the device at index i
+func (d *Devices) Get(i int) *Device {
+	if i < 0 || i >= len(d.Items) {
+		return nil
+	}
+	return &d.Items[i]
+}
+
+// Contact is a simple meta data wrapper around a Contact
+type Contact struct {
+	Id       string `json:"id"`
+	Username string `json:"username"`
+	AvatarId string `json:"avatar_id"`
+	LastSeen int64  `json:"last_seen"`
+}
+
+// Contacts is a wrapper around a list of Contacts
+type Contacts struct {
+	Items []Contact `json:"items"`
+}
+
+// Count returns the number of contacts
+func (c *Contacts) Count() int {
+	return len(c.Items)
+}
+
+// Get returns the contact at index i
+func (c *Contacts) Get(i int) *Contact {
+	if i < 0 || i >= len(c.Items) {
+		return nil
+	}
+	return &c.Items[i]
+}
+
+// SearchResult is a photo matching a search
+type SearchResult struct {
+	Id        string  `json:"id"`
+	BlockId   string  `json:"block_id"`
+	ThreadId  string  `json:"thread_id"`
+	AuthorId  string  `json:"author_id"`
+	Caption   string  `json:"caption,omitempty"`
+	Name      string  `json:"name,omitempty"`
+	Date      int64   `json:"date"`
+	Taken     int64   `json:"taken,omitempty"`
+	Latitude  float64 `json:"latitude,omitempty"`
+	Longitude float64 `json:"longitude,omitempty"`
+}
+
+// SearchResults is a wrapper around a list of SearchResults
+type SearchResults struct {
+	Items []SearchResult `json:"items"`
+}
+
+// Count returns the number of search results
+func (s *SearchResults) Count() int {
+	return len(s.Items)
+}
+
+// Get returns the search result at index i
+func (s *SearchResults) Get(i int) *SearchResult {
+	if i < 0 || i >= len(s.Items) {
+		return nil
+	}
+	return &s.Items[i]
+}
+
+// Photo is a simple meta data wrapper around a photo block
+type Photo struct {
+	Id       string    `json:"id"`
+	BlockId  string    `json:"block_id"`
+	Date     time.Time `json:"date"`
+	AuthorId string    `json:"author_id"`
+	Caption  string    `json:"caption"`
+}
+
+// Timestamp returns the photo block date in unix seconds
+func (p *Photo) Timestamp() int64 {
+	return p.Date.Unix()
+}
+
+// Photos is a wrapper around a list of photos
+type Photos struct {
+	Items []Photo `json:"items"`
+}
+
+// Count returns the number of photos
+func (p *Photos) Count() int {
+	return len(p.Items)
+}
+
+// Get returns the photo at index i
+func (p *Photos) Get(i int) *Photo {
+	if i < 0 || i >= len(p.Items) {
+		return nil
+	}
+	return &p.Items[i]
+}
+
+// Album is a named collection of photos in a thread
+type Album struct {
+	Id       string    `json:"id"`
+	ThreadId string    `json:"thread_id"`
+	Name     string    `json:"name"`
+	Date     time.Time `json:"date"`
+	AuthorId string    `json:"author_id"`
+}
+
+// Timestamp returns the album block date in unix seconds
+func (a *Album) Timestamp() int64 {
+	return a.Date.Unix()
+}
+
+// Albums is a wrapper around a list of albums
+type Albums struct {
+	Items []Album `json:"items"`
+}
+
+// Count returns the number of albums
+func (a *Albums) Count() int {
+	return len(a.Items)
+}
+
+// Get returns the album at index i
+func (a *Albums) Get(i int) *Album {
+	if i < 0 || i >= len(a.Items) {
+		return nil
+	}
+	return &a.Items[i]
+}
+
+// ImageData is a wrapper around an image data url and meta data
+type ImageData struct {
+	Url      string               `json:"url"`
+	Metadata *model.PhotoMetadata `json:"metadata"`
+}
+
+// Meta returns the image meta data in a bindable form
+func (d *ImageData) Meta() *PhotoMeta {
+	return newPhotoMeta(d.Metadata)
+}
+
+// PhotoMeta is a flat, bindable version of photo meta data
+type PhotoMeta struct {
+	Name            string
+	Ext             string
+	Format          string
+	ThumbnailFormat string
+	Width           int
+	Height          int
+	Latitude        float64
+	Longitude       float64
+	PeerId          string
+	Username        string
+	Created         int64
+	Added           int64
+	renditions      []Rendition
+}
+
+// RenditionCount returns the number of resized versions stored with the photo
+func (p *PhotoMeta) RenditionCount() int {
+	return len(p.renditions)
+}
+
+// Rendition returns the rendition at index i
+func (p *PhotoMeta) Rendition(i int) *Rendition {
+	if i < 0 || i >= len(p.renditions) {
+		return nil
+	}
+	return &p.renditions[i]
+}
+
+// Rendition is a resized version of a photo, selectable by name
+type Rendition struct {
+	Name   string
+	Width  int
+	Height int
+	Square bool
+}
+
+// ExternalInvite is a wrapper around an invite id and key
+type ExternalInvite struct {
+	Id      string `json:"id"`
+	Key     string `json:"key"`
+	Link    string `json:"link"`
+	Expires int64  `json:"expires,omitempty"`
+	MaxUses int    `json:"max_uses,omitempty"`
+	Inviter string `json:"inviter"`
+}
+
+// AddedPhoto is the result of adding a photo
+type AddedPhoto struct {
+	Id          string
+	Key         string
+	ArchivePath string
+	Duplicate   bool
+}
+
+// Profile is a wrapper around a peer profile
+type Profile struct {
+	Id          string
+	Username    string
+	AvatarId    string
+	Avatar      string
+	DisplayName string
+	Bio         string
+	CafeAddr    string
+	Threads     string
+	Updated     int64
+	fields      map[string]string
+}
+
+// GetField returns a public profile field by key, or an empty string
+func (p *Profile) GetField(key string) string {
+	return p.fields[key]
+}
+
+// Tokens is a wrapper around cafe session tokens
+type Tokens struct {
+	Access  string
+	Refresh string
+}
+
+// Notification is a decrypted push notification payload
+type Notification struct {
+	Type           string
+	MessageId      string
+	ThreadId       string
+	ThreadName     string
+	AuthorId       string
+	AuthorUsername string
+}
+
+// SyncSummary summarizes a sync pass
+type SyncSummary struct {
+	Messages int
+	Pins     int
+	Pointers int
+	Threads  int
+	Backups  int
+	Finished int
+	TimedOut bool
+	Millis   int64
+}
+
+// RecoverSummary summarizes an account recovery
+type RecoverSummary struct {
+	Threads  int
+	Devices  int
+	Username string
+	Created  int64
+}
+
+// PairingRequest is a one-time code (and link) a new device displays to be paired
+type PairingRequest struct {
+	Code    string
+	Link    string
+	Expires int64
+}
+
+func newContact(contact *repo.Contact) *Contact {
+	return &Contact{
+		Id:       contact.Id,
+		Username: contact.Username,
+		AvatarId: contact.AvatarId,
+		LastSeen: contact.LastSeen.Unix(),
+	}
+}
+
+func newSearchResult(entry *repo.SearchEntry) *SearchResult {
+	res := &SearchResult{
+		Id:        entry.DataId,
+		BlockId:   entry.BlockId,
+		ThreadId:  entry.ThreadId,
+		AuthorId:  entry.AuthorId,
+		Caption:   entry.Caption,
+		Name:      entry.Name,
+		Date:      entry.Date.Unix(),
+		Latitude:  entry.Latitude,
+		Longitude: entry.Longitude,
+	}
+	if !entry.Taken.IsZero() {
+		res.Taken = entry.Taken.Unix()
+	}
+	return res
+}
+
+func newPhotoMeta(meta *model.PhotoMetadata) *PhotoMeta {
+	if meta == nil {
+		return nil
+	}
+	var renditions []Rendition
+	for _, r := range meta.Renditions {
+		renditions = append(renditions, Rendition{Name: r.Name, Width: r.Width, Height: r.Height, Square: r.Square})
+	}
+	return &PhotoMeta{
+		Name:            meta.Name,
+		Ext:             meta.Ext,
+		Format:          meta.Format,
+		ThumbnailFormat: meta.ThumbnailFormat,
+		Width:           meta.Width,
+		Height:          meta.Height,
+		Latitude:        meta.Latitude,
+		Longitude:       meta.Longitude,
+		PeerId:          meta.PeerId,
+		Username:        meta.Username,
+		Created:         meta.Created.Unix(),
+		Added:           meta.Added.Unix(),
+		renditions:      renditions,
+	}
+}
+
+func newProfile(prof *model.Profile) *Profile {
+	var updated int64
+	if !prof.Updated.IsZero() {
+		updated = prof.Updated.Unix()
+	}
+	return &Profile{
+		Id:          prof.Id,
+		Username:    prof.Username,
+		AvatarId:    prof.AvatarId,
+		Avatar:      prof.Avatar,
+		DisplayName: prof.Fields[model.ProfileDisplayName],
+		Bio:         prof.Fields[model.ProfileBio],
+		CafeAddr:    prof.Fields[model.ProfileCafeAddr],
+		Threads:     prof.Fields[model.ProfileThreads],
+		Updated:     updated,
+		fields:      prof.Fields,
+	}
+}
+
+func newTokens(tokens *repo.CafeTokens) *Tokens {
+	return &Tokens{Access: tokens.Access, Refresh: tokens.Refresh}
+}
+
+func newNotification(note *model.Notification) *Notification {
+	return &Notification{
+		Type:           note.Type,
+		MessageId:      note.MessageId,
+		ThreadId:       note.ThreadId,
+		ThreadName:     note.ThreadName,
+		AuthorId:       note.AuthorId,
+		AuthorUsername: note.AuthorUsername,
+	}
+}
+
+func newSyncSummary(result *wallet.SyncResult) *SyncSummary {
+	return &SyncSummary{
+		Messages: result.Messages,
+		Pins:     result.Pins,
+		Pointers: result.Pointers,
+		Threads:  result.Threads,
+		Backups:  result.Backups,
+		Finished: len(result.Finished),
+		TimedOut: result.TimedOut,
+		Millis:   int64(result.Ended.Sub(result.Started) / time.Millisecond),
+	}
+}
+
+func newRecoverSummary(result *wallet.RecoverResult) *RecoverSummary {
+	return &RecoverSummary{
+		Threads:  result.Threads,
+		Devices:  result.Devices,
+		Username: result.Username,
+		Created:  result.Created.Unix(),
+	}
+}
+
+func newPairingRequest(req *wallet.PairingRequest) *PairingRequest {
+	return &PairingRequest{Code: req.Code, Link: req.Link, Expires: req.Expires.Unix()}
+}
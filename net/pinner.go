@@ -59,19 +59,22 @@ func (p *Pinner) Run() {
 	}
 }
 
-func (p *Pinner) Pin() {
+// Pin flushes pending pin requests to the cafe, returning the number pinned
+func (p *Pinner) Pin() int {
 	p.mux.Lock()
 	defer p.mux.Unlock()
 
 	// check tokens
 	if p.Tokens == nil {
 		log.Debugf("not logged in, pinner aborting")
-		return
+		return 0
 	}
 
-	if err := p.handlePin(""); err != nil {
-		return
+	pinned, err := p.handlePin("")
+	if err != nil {
+		log.Errorf("error handling pin requests: %s", err)
 	}
+	return pinned
 }
 
 func (p *Pinner) Put(id string) error {
@@ -87,24 +90,27 @@ func (p *Pinner) Put(id string) error {
 	return nil
 }
 
-func (p *Pinner) handlePin(offset string) error {
+func (p *Pinner) handlePin(offset string) (int, error) {
 	// get pending pin list
 	prs := p.datastore.PinRequests().List(offset, pinGroupSize)
 	if len(prs) == 0 {
-		return nil
+		return 0, nil
 	}
 	log.Debugf("handling %d pin requests...", len(prs))
 
 	// process them
 	var toDelete []string
 	wg := sync.WaitGroup{}
+	dmux := sync.Mutex{}
 	for _, r := range prs {
 		wg.Add(1)
 		go func(pr repo.PinRequest) {
 			if err := p.send(pr); err != nil {
 				log.Errorf("pin request %s failed: %s", pr.Id, err)
 			} else {
+				dmux.Lock()
 				toDelete = append(toDelete, pr.Id)
+				dmux.Unlock()
 			}
 			wg.Done()
 		}(r)
@@ -120,7 +126,8 @@ func (p *Pinner) handlePin(offset string) error {
 	}
 
 	// keep going
-	return p.handlePin(prs[len(prs)-1].Id)
+	next, err := p.handlePin(prs[len(prs)-1].Id)
+	return len(toDelete) + next, err
 }
 
 func (p *Pinner) send(pr repo.PinRequest) error {
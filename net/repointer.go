@@ -35,16 +35,18 @@ func (r *PointerRepublisher) Run() {
 	}
 }
 
-func (r *PointerRepublisher) Republish() {
+// Republish re-publishes active pointers, returning the number published
+func (r *PointerRepublisher) Republish() int {
 	log.Debug("republishing pointers...")
 
 	republishModerator := r.isModerator()
 	pointers, err := r.datastore.Pointers().GetAll()
 	if err != nil {
 		log.Errorf("error republishing: %s", err)
-		return
+		return 0
 	}
 	ctx := context.Background()
+	published := 0
 
 	for _, pointer := range pointers {
 		switch pointer.Purpose {
@@ -53,10 +55,12 @@ func (r *PointerRepublisher) Republish() {
 				r.datastore.Pointers().Delete(pointer.Value.ID)
 			} else {
 				go repo.PublishPointer(r.ipfs, ctx, pointer)
+				published++
 			}
 		case repo.MODERATOR:
 			if republishModerator {
 				go repo.PublishPointer(r.ipfs, ctx, pointer)
+				published++
 			} else {
 				r.datastore.Pointers().Delete(pointer.Value.ID)
 			}
@@ -64,4 +68,5 @@ func (r *PointerRepublisher) Republish() {
 			continue
 		}
 	}
+	return published
 }
@@ -83,7 +83,8 @@ func (m *MessageRetriever) Run() {
 	}
 }
 
-func (m *MessageRetriever) FetchPointers() {
+// FetchPointers downloads and handles any new offline messages, returning the number fetched
+func (m *MessageRetriever) FetchPointers() int {
 	log.Debug("fetching pointers...")
 
 	ctx, cancel := context.WithCancel(context.Background())
@@ -130,6 +131,7 @@ func (m *MessageRetriever) FetchPointers() {
 
 	m.processQueuedMessages()
 	m.Done()
+	return downloaded
 }
 
 // fetchIPFS will attempt to download an encrypted message using IPFS. If the message downloads successfully, we save the
@@ -8,6 +8,7 @@ import (
 	"github.com/textileio/textile-go/crypto"
 	"github.com/textileio/textile-go/net/common"
 	"github.com/textileio/textile-go/pb"
+	"github.com/textileio/textile-go/wallet/thread"
 	"gx/ipfs/QmZoWKhxUmZ2seW4BzX6fJkNR8hh9PsGModr7q171yq2SS/go-libp2p-peer"
 	libp2pc "gx/ipfs/QmaPbCnUMBohSGo3KnxEa2bHqyJVVeEEcwtqJAYxerieBo/go-libp2p-crypto"
 	"gx/ipfs/QmcZfnkapfECQGcLZaf9B79NRg7cRa9EnZh4LSbkCzwNvY/go-cid"
@@ -30,14 +31,36 @@ func (s *TextileService) HandlerForMsgType(t pb.Message_Type) func(peer.ID, *pb.
 		return s.handleThreadData
 	case pb.Message_THREAD_IGNORE:
 		return s.handleThreadIgnore
+	case pb.Message_THREAD_INVITE_REVOKE:
+		return s.handleThreadInviteRevoke
+	case pb.Message_THREAD_METADATA:
+		return s.handleThreadMetadata
+	case pb.Message_THREAD_ALBUM:
+		return s.handleThreadAlbum
+	case pb.Message_THREAD_ALBUM_MEMBER:
+		return s.handleThreadAlbumMember
 	case pb.Message_THREAD_MERGE:
 		return s.handleThreadMerge
+	case pb.Message_THREAD_RESTORE:
+		return s.handleThreadRestore
+	case pb.Message_THREAD_CAPTION:
+		return s.handleThreadCaption
 	case pb.Message_OFFLINE_ACK:
 		return s.handleOfflineAck
 	case pb.Message_OFFLINE_RELAY:
 		return s.handleOfflineRelay
 	case pb.Message_BLOCK:
 		return s.handleBlock
+	case pb.Message_DEVICE_PAIR:
+		return s.handleDevicePair
+	case pb.Message_DEVICE_REVOKE:
+		return s.handleDeviceRevoke
+	case pb.Message_INVITE_STATUS:
+		return s.handleInviteStatus
+	case pb.Message_THREAD_HEADS:
+		return s.handleThreadHeads
+	case pb.Message_THREAD_BLOCKS:
+		return s.handleThreadBlocks
 	case pb.Message_STORE:
 		return s.handleStore
 	case pb.Message_ERROR:
@@ -317,6 +340,192 @@ func (s *TextileService) handleThreadIgnore(pid peer.ID, pmes *pb.Envelope, opti
 	return nil, nil
 }
 
+func (s *TextileService) handleThreadRestore(pid peer.ID, pmes *pb.Envelope, options interface{}) (*pb.Envelope, error) {
+	log.Debug("received THREAD_RESTORE message")
+	signed, err := unpackMessage(pmes)
+	if err != nil {
+		return nil, err
+	}
+	restore := new(pb.ThreadRestore)
+	if err := proto.Unmarshal(signed.Block, restore); err != nil {
+		return nil, err
+	}
+
+	// load thread
+	threadId := libp2pc.ConfigEncodeKey(restore.Header.ThreadPk)
+	_, thrd := s.getThread(threadId)
+	if thrd == nil {
+		return nil, common.OutOfOrderMessage
+	}
+
+	// verify
+	if err := thrd.Verify(signed); err != nil {
+		return nil, err
+	}
+
+	// handle
+	if _, err := thrd.HandleRestoreBlock(pmes, signed, restore, false); err != nil {
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+func (s *TextileService) handleThreadCaption(pid peer.ID, pmes *pb.Envelope, options interface{}) (*pb.Envelope, error) {
+	log.Debug("received THREAD_CAPTION message")
+	signed, err := unpackMessage(pmes)
+	if err != nil {
+		return nil, err
+	}
+	caption := new(pb.ThreadCaption)
+	if err := proto.Unmarshal(signed.Block, caption); err != nil {
+		return nil, err
+	}
+
+	// load thread
+	threadId := libp2pc.ConfigEncodeKey(caption.Header.ThreadPk)
+	_, thrd := s.getThread(threadId)
+	if thrd == nil {
+		return nil, common.OutOfOrderMessage
+	}
+
+	// verify
+	if err := thrd.Verify(signed); err != nil {
+		return nil, err
+	}
+
+	// handle
+	if _, err := thrd.HandleCaptionBlock(pmes, signed, caption, false); err != nil {
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+func (s *TextileService) handleThreadInviteRevoke(pid peer.ID, pmes *pb.Envelope, options interface{}) (*pb.Envelope, error) {
+	log.Debug("received THREAD_INVITE_REVOKE message")
+	signed, err := unpackMessage(pmes)
+	if err != nil {
+		return nil, err
+	}
+	revoke := new(pb.ThreadInviteRevoke)
+	if err := proto.Unmarshal(signed.Block, revoke); err != nil {
+		return nil, err
+	}
+
+	// load thread
+	threadId := libp2pc.ConfigEncodeKey(revoke.Header.ThreadPk)
+	_, thrd := s.getThread(threadId)
+	if thrd == nil {
+		return nil, common.OutOfOrderMessage
+	}
+
+	// verify
+	if err := thrd.Verify(signed); err != nil {
+		return nil, err
+	}
+
+	// handle
+	if _, err := thrd.HandleInviteRevokeBlock(pmes, signed, revoke, false); err != nil {
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+func (s *TextileService) handleThreadMetadata(pid peer.ID, pmes *pb.Envelope, options interface{}) (*pb.Envelope, error) {
+	log.Debug("received THREAD_METADATA message")
+	signed, err := unpackMessage(pmes)
+	if err != nil {
+		return nil, err
+	}
+	meta := new(pb.ThreadMetadata)
+	if err := proto.Unmarshal(signed.Block, meta); err != nil {
+		return nil, err
+	}
+
+	// load thread
+	threadId := libp2pc.ConfigEncodeKey(meta.Header.ThreadPk)
+	_, thrd := s.getThread(threadId)
+	if thrd == nil {
+		return nil, common.OutOfOrderMessage
+	}
+
+	// verify
+	if err := thrd.Verify(signed); err != nil {
+		return nil, err
+	}
+
+	// handle
+	if _, err := thrd.HandleMetadataBlock(pmes, signed, meta, false); err != nil {
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+func (s *TextileService) handleThreadAlbum(pid peer.ID, pmes *pb.Envelope, options interface{}) (*pb.Envelope, error) {
+	log.Debug("received THREAD_ALBUM message")
+	signed, err := unpackMessage(pmes)
+	if err != nil {
+		return nil, err
+	}
+	album := new(pb.ThreadAlbum)
+	if err := proto.Unmarshal(signed.Block, album); err != nil {
+		return nil, err
+	}
+
+	// load thread
+	threadId := libp2pc.ConfigEncodeKey(album.Header.ThreadPk)
+	_, thrd := s.getThread(threadId)
+	if thrd == nil {
+		return nil, common.OutOfOrderMessage
+	}
+
+	// verify
+	if err := thrd.Verify(signed); err != nil {
+		return nil, err
+	}
+
+	// handle
+	if _, err := thrd.HandleAlbumBlock(pmes, signed, album, false); err != nil {
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+func (s *TextileService) handleThreadAlbumMember(pid peer.ID, pmes *pb.Envelope, options interface{}) (*pb.Envelope, error) {
+	log.Debug("received THREAD_ALBUM_MEMBER message")
+	signed, err := unpackMessage(pmes)
+	if err != nil {
+		return nil, err
+	}
+	member := new(pb.ThreadAlbumMember)
+	if err := proto.Unmarshal(signed.Block, member); err != nil {
+		return nil, err
+	}
+
+	// load thread
+	threadId := libp2pc.ConfigEncodeKey(member.Header.ThreadPk)
+	_, thrd := s.getThread(threadId)
+	if thrd == nil {
+		return nil, common.OutOfOrderMessage
+	}
+
+	// verify
+	if err := thrd.Verify(signed); err != nil {
+		return nil, err
+	}
+
+	// handle
+	if _, err := thrd.HandleAlbumMemberBlock(pmes, signed, member, false); err != nil {
+		return nil, err
+	}
+
+	return nil, nil
+}
+
 func (s *TextileService) handleOfflineAck(pid peer.ID, pmes *pb.Envelope, options interface{}) (*pb.Envelope, error) {
 	if pmes.Message.Payload == nil {
 		return nil, errors.New("payload is nil")
@@ -460,6 +669,137 @@ func (s *TextileService) handleStore(pid peer.ID, pmes *pb.Envelope, options int
 	return s.newEnvelope(message)
 }
 
+func (s *TextileService) handleDevicePair(pid peer.ID, pmes *pb.Envelope, options interface{}) (*pb.Envelope, error) {
+	log.Debugf("received DEVICE_PAIR message from %s", pid.Pretty())
+	if pmes.Message.Payload == nil {
+		return s.errorResponse(errors.New("payload is nil"))
+	}
+	pk, err := senderPubKey(pid, pmes)
+	if err != nil {
+		return s.errorResponse(err)
+	}
+	pair := new(pb.DevicePair)
+	if err := ptypes.UnmarshalAny(pmes.Message.Payload, pair); err != nil {
+		return s.errorResponse(err)
+	}
+	attestation, err := s.pair(pk, pair)
+	if err != nil {
+		return s.errorResponse(err)
+	}
+
+	// respond with our own attestation
+	payload, err := ptypes.MarshalAny(attestation)
+	if err != nil {
+		return nil, err
+	}
+	return s.newEnvelope(&pb.Message{Type: pb.Message_DEVICE_PAIR, Payload: payload})
+}
+
+func (s *TextileService) handleDeviceRevoke(pid peer.ID, pmes *pb.Envelope, options interface{}) (*pb.Envelope, error) {
+	log.Debugf("received DEVICE_REVOKE message from %s", pid.Pretty())
+	pk, err := senderPubKey(pid, pmes)
+	if err != nil {
+		return nil, err
+	}
+	return nil, s.revoke(pk)
+}
+
+func (s *TextileService) handleInviteStatus(pid peer.ID, pmes *pb.Envelope, options interface{}) (*pb.Envelope, error) {
+	log.Debugf("received INVITE_STATUS message from %s", pid.Pretty())
+	if pmes.Message.Payload == nil {
+		return nil, errors.New("payload is nil")
+	}
+	req := new(pb.InviteStatusRequest)
+	if err := ptypes.UnmarshalAny(pmes.Message.Payload, req); err != nil {
+		return nil, err
+	}
+
+	// an invite to a thread we've left is no longer valid
+	status := &pb.InviteStatus{Valid: true}
+	_, thrd := s.getThread(req.ThreadId)
+	if thrd == nil {
+		status = &pb.InviteStatus{Reason: "inviter is no longer in the thread"}
+	} else if err := thrd.ExternalInviteStatus(req.BlockId); err != nil {
+		status = &pb.InviteStatus{Reason: err.Error()}
+	}
+	payload, err := ptypes.MarshalAny(status)
+	if err != nil {
+		return nil, err
+	}
+	return s.newEnvelope(&pb.Message{Type: pb.Message_INVITE_STATUS, Payload: payload})
+}
+
+func (s *TextileService) handleThreadHeads(pid peer.ID, pmes *pb.Envelope, options interface{}) (*pb.Envelope, error) {
+	log.Debugf("received THREAD_HEADS message from %s", pid.Pretty())
+	if pmes.Message.Payload == nil {
+		return nil, errors.New("payload is nil")
+	}
+	req := new(pb.ThreadHeadsRequest)
+	if err := ptypes.UnmarshalAny(pmes.Message.Payload, req); err != nil {
+		return nil, err
+	}
+	thrd, err := s.syncThread(pid, pmes, req.ThreadId)
+	if err != nil {
+		return s.errorResponse(err)
+	}
+	heads, err := thrd.Heads()
+	if err != nil {
+		return nil, err
+	}
+	payload, err := ptypes.MarshalAny(&pb.ThreadHeads{ThreadId: thrd.Id, Heads: heads})
+	if err != nil {
+		return nil, err
+	}
+	return s.newEnvelope(&pb.Message{Type: pb.Message_THREAD_HEADS, Payload: payload})
+}
+
+func (s *TextileService) handleThreadBlocks(pid peer.ID, pmes *pb.Envelope, options interface{}) (*pb.Envelope, error) {
+	log.Debugf("received THREAD_BLOCKS message from %s", pid.Pretty())
+	if pmes.Message.Payload == nil {
+		return nil, errors.New("payload is nil")
+	}
+	req := new(pb.ThreadBlocksRequest)
+	if err := ptypes.UnmarshalAny(pmes.Message.Payload, req); err != nil {
+		return nil, err
+	}
+	thrd, err := s.syncThread(pid, pmes, req.ThreadId)
+	if err != nil {
+		return s.errorResponse(err)
+	}
+	blocks := thrd.BlocksSince(req.Want, req.Have, int(req.Limit))
+	payload, err := ptypes.MarshalAny(&pb.ThreadBlocks{ThreadId: thrd.Id, Blocks: blocks})
+	if err != nil {
+		return nil, err
+	}
+	return s.newEnvelope(&pb.Message{Type: pb.Message_THREAD_BLOCKS, Payload: payload})
+}
+
+// syncThread loads a thread for a sync request, which is only answered for
+// thread peers and our own devices
+func (s *TextileService) syncThread(pid peer.ID, pmes *pb.Envelope, threadId string) (*thread.Thread, error) {
+	pk, err := senderPubKey(pid, pmes)
+	if err != nil {
+		return nil, err
+	}
+	_, thrd := s.getThread(threadId)
+	if thrd == nil {
+		return nil, errors.New("thread not found")
+	}
+	for _, p := range thrd.Peers() {
+		if p.Id == pid.Pretty() {
+			return thrd, nil
+		}
+	}
+	pkb, err := pk.Bytes()
+	if err != nil {
+		return nil, err
+	}
+	if s.datastore.Devices().Get(libp2pc.ConfigEncodeKey(pkb)) != nil {
+		return thrd, nil
+	}
+	return nil, errors.New("peer is not a thread member")
+}
+
 func (s *TextileService) handleError(peer peer.ID, pmes *pb.Envelope, options interface{}) (*pb.Envelope, error) {
 	if pmes.Message.Payload == nil {
 		return nil, errors.New("payload is nil")
@@ -475,6 +815,19 @@ func (s *TextileService) handleError(peer peer.ID, pmes *pb.Envelope, options in
 	return nil, nil
 }
 
+// errorResponse answers a request with an error message, the error is passed along for logging
+func (s *TextileService) errorResponse(err error) (*pb.Envelope, error) {
+	payload, perr := ptypes.MarshalAny(&pb.Error{Message: err.Error()})
+	if perr != nil {
+		return nil, perr
+	}
+	env, perr := s.newEnvelope(&pb.Message{Type: pb.Message_ERROR, Payload: payload})
+	if perr != nil {
+		return nil, perr
+	}
+	return env, err
+}
+
 func unpackMessage(pmes *pb.Envelope) (*pb.SignedThreadBlock, error) {
 	if pmes.Message.Payload == nil {
 		return nil, errors.New("payload is nil")
@@ -485,3 +838,27 @@ func unpackMessage(pmes *pb.Envelope) (*pb.SignedThreadBlock, error) {
 	}
 	return signed, nil
 }
+
+// senderPubKey verifies the envelope signature and returns the sender's public key,
+// which must match the peer on the other end of the stream
+func senderPubKey(pid peer.ID, pmes *pb.Envelope) (libp2pc.PubKey, error) {
+	pk, err := libp2pc.UnmarshalPublicKey(pmes.Pk)
+	if err != nil {
+		return nil, err
+	}
+	id, err := peer.IDFromPublicKey(pk)
+	if err != nil {
+		return nil, err
+	}
+	if id != pid {
+		return nil, errors.New("envelope key does not match sender")
+	}
+	messageb, err := proto.Marshal(pmes.Message)
+	if err != nil {
+		return nil, err
+	}
+	if err := crypto.Verify(pk, messageb, pmes.Sig); err != nil {
+		return nil, err
+	}
+	return pk, nil
+}
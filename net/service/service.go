@@ -35,6 +35,8 @@ type TextileService struct {
 	node      *core.IpfsNode
 	getThread func(string) (*int, *thread.Thread)
 	addThread func(string, libp2pc.PrivKey) (*thread.Thread, error)
+	pair      func(libp2pc.PubKey, *pb.DevicePair) (*pb.SignedDeviceAttestation, error)
+	revoke    func(libp2pc.PubKey) error
 	sender    map[peer.ID]*sender
 	senderlk  sync.Mutex
 }
@@ -44,6 +46,8 @@ func NewService(
 	datastore repo.Datastore,
 	getThread func(string) (*int, *thread.Thread),
 	addThread func(string, libp2pc.PrivKey) (*thread.Thread, error),
+	pair func(libp2pc.PubKey, *pb.DevicePair) (*pb.SignedDeviceAttestation, error),
+	revoke func(libp2pc.PubKey) error,
 ) *TextileService {
 	service := &TextileService{
 		host:      node.PeerHost.(host.Host),
@@ -54,6 +58,8 @@ func NewService(
 		node:      node,
 		getThread: getThread,
 		addThread: addThread,
+		pair:      pair,
+		revoke:    revoke,
 		sender:    make(map[peer.ID]*sender),
 	}
 	node.PeerHost.SetStreamHandler(ProtocolTextile, service.HandleNewStream)
@@ -0,0 +1,209 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// source: device.proto
+
+package pb
+
+import proto "github.com/golang/protobuf/proto"
+import fmt "fmt"
+import math "math"
+import timestamp "github.com/golang/protobuf/ptypes/timestamp"
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.ProtoPackageIsVersion2 // please upgrade the proto package
+
+type DeviceAttestation struct {
+	AccountPk            []byte               `protobuf:"bytes,1,opt,name=accountPk,proto3" json:"accountPk,omitempty"`
+	DevicePk             []byte               `protobuf:"bytes,2,opt,name=devicePk,proto3" json:"devicePk,omitempty"`
+	Name                 string               `protobuf:"bytes,3,opt,name=name,proto3" json:"name,omitempty"`
+	Date                 *timestamp.Timestamp `protobuf:"bytes,4,opt,name=date,proto3" json:"date,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}             `json:"-"`
+	XXX_unrecognized     []byte               `json:"-"`
+	XXX_sizecache        int32                `json:"-"`
+}
+
+func (m *DeviceAttestation) Reset()         { *m = DeviceAttestation{} }
+func (m *DeviceAttestation) String() string { return proto.CompactTextString(m) }
+func (*DeviceAttestation) ProtoMessage()    {}
+func (*DeviceAttestation) Descriptor() ([]byte, []int) {
+	return fileDescriptor_device_7487a4c0c242817a, []int{0}
+}
+func (m *DeviceAttestation) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DeviceAttestation.Unmarshal(m, b)
+}
+func (m *DeviceAttestation) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DeviceAttestation.Marshal(b, m, deterministic)
+}
+func (dst *DeviceAttestation) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DeviceAttestation.Merge(dst, src)
+}
+func (m *DeviceAttestation) XXX_Size() int {
+	return xxx_messageInfo_DeviceAttestation.Size(m)
+}
+func (m *DeviceAttestation) XXX_DiscardUnknown() {
+	xxx_messageInfo_DeviceAttestation.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DeviceAttestation proto.InternalMessageInfo
+
+func (m *DeviceAttestation) GetAccountPk() []byte {
+	if m != nil {
+		return m.AccountPk
+	}
+	return nil
+}
+
+func (m *DeviceAttestation) GetDevicePk() []byte {
+	if m != nil {
+		return m.DevicePk
+	}
+	return nil
+}
+
+func (m *DeviceAttestation) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *DeviceAttestation) GetDate() *timestamp.Timestamp {
+	if m != nil {
+		return m.Date
+	}
+	return nil
+}
+
+type SignedDeviceAttestation struct {
+	Attestation          []byte   `protobuf:"bytes,1,opt,name=attestation,proto3" json:"attestation,omitempty"`
+	Sig                  []byte   `protobuf:"bytes,2,opt,name=sig,proto3" json:"sig,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *SignedDeviceAttestation) Reset()         { *m = SignedDeviceAttestation{} }
+func (m *SignedDeviceAttestation) String() string { return proto.CompactTextString(m) }
+func (*SignedDeviceAttestation) ProtoMessage()    {}
+func (*SignedDeviceAttestation) Descriptor() ([]byte, []int) {
+	return fileDescriptor_device_7487a4c0c242817a, []int{1}
+}
+func (m *SignedDeviceAttestation) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SignedDeviceAttestation.Unmarshal(m, b)
+}
+func (m *SignedDeviceAttestation) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SignedDeviceAttestation.Marshal(b, m, deterministic)
+}
+func (dst *SignedDeviceAttestation) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SignedDeviceAttestation.Merge(dst, src)
+}
+func (m *SignedDeviceAttestation) XXX_Size() int {
+	return xxx_messageInfo_SignedDeviceAttestation.Size(m)
+}
+func (m *SignedDeviceAttestation) XXX_DiscardUnknown() {
+	xxx_messageInfo_SignedDeviceAttestation.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SignedDeviceAttestation proto.InternalMessageInfo
+
+func (m *SignedDeviceAttestation) GetAttestation() []byte {
+	if m != nil {
+		return m.Attestation
+	}
+	return nil
+}
+
+func (m *SignedDeviceAttestation) GetSig() []byte {
+	if m != nil {
+		return m.Sig
+	}
+	return nil
+}
+
+type DevicePair struct {
+	Attestation          *SignedDeviceAttestation `protobuf:"bytes,1,opt,name=attestation,proto3" json:"attestation,omitempty"`
+	Proof                []byte                   `protobuf:"bytes,2,opt,name=proof,proto3" json:"proof,omitempty"`
+	Name                 string                   `protobuf:"bytes,3,opt,name=name,proto3" json:"name,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}                 `json:"-"`
+	XXX_unrecognized     []byte                   `json:"-"`
+	XXX_sizecache        int32                    `json:"-"`
+}
+
+func (m *DevicePair) Reset()         { *m = DevicePair{} }
+func (m *DevicePair) String() string { return proto.CompactTextString(m) }
+func (*DevicePair) ProtoMessage()    {}
+func (*DevicePair) Descriptor() ([]byte, []int) {
+	return fileDescriptor_device_7487a4c0c242817a, []int{2}
+}
+func (m *DevicePair) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DevicePair.Unmarshal(m, b)
+}
+func (m *DevicePair) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DevicePair.Marshal(b, m, deterministic)
+}
+func (dst *DevicePair) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DevicePair.Merge(dst, src)
+}
+func (m *DevicePair) XXX_Size() int {
+	return xxx_messageInfo_DevicePair.Size(m)
+}
+func (m *DevicePair) XXX_DiscardUnknown() {
+	xxx_messageInfo_DevicePair.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DevicePair proto.InternalMessageInfo
+
+func (m *DevicePair) GetAttestation() *SignedDeviceAttestation {
+	if m != nil {
+		return m.Attestation
+	}
+	return nil
+}
+
+func (m *DevicePair) GetProof() []byte {
+	if m != nil {
+		return m.Proof
+	}
+	return nil
+}
+
+func (m *DevicePair) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func init() {
+	proto.RegisterType((*DeviceAttestation)(nil), "DeviceAttestation")
+	proto.RegisterType((*SignedDeviceAttestation)(nil), "SignedDeviceAttestation")
+	proto.RegisterType((*DevicePair)(nil), "DevicePair")
+}
+
+func init() { proto.RegisterFile("device.proto", fileDescriptor_device_7487a4c0c242817a) }
+
+var fileDescriptor_device_7487a4c0c242817a = []byte{
+	// 240 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x74, 0x8f, 0x4f, 0x4b, 0xc3, 0x40,
+	0x10, 0xc5, 0xd9, 0x36, 0x8a, 0x9d, 0xf4, 0xa0, 0x8b, 0xe0, 0x12, 0x04, 0x43, 0x4e, 0x39, 0x6d,
+	0xa1, 0xde, 0xbc, 0x29, 0x5e, 0x85, 0x10, 0x3d, 0x79, 0xdb, 0x24, 0xdb, 0xb0, 0xd4, 0xec, 0x2c,
+	0xdb, 0xa9, 0xdf, 0xc3, 0x6f, 0x2c, 0xdd, 0x4d, 0xad, 0x52, 0x7b, 0x9b, 0x37, 0x7f, 0xde, 0xfc,
+	0x1e, 0xcc, 0x3b, 0xfd, 0x69, 0x5a, 0x2d, 0x9d, 0x47, 0xc2, 0xec, 0xae, 0x47, 0xec, 0x3f, 0xf4,
+	0x22, 0xa8, 0x66, 0xbb, 0x5a, 0x90, 0x19, 0xf4, 0x86, 0xd4, 0xe0, 0xe2, 0x42, 0xf1, 0xc5, 0xe0,
+	0xea, 0x39, 0x5c, 0x3c, 0x12, 0xed, 0x26, 0x64, 0xd0, 0xf2, 0x5b, 0x98, 0xa9, 0xb6, 0xc5, 0xad,
+	0xa5, 0x6a, 0x2d, 0x58, 0xce, 0xca, 0x79, 0x7d, 0x68, 0xf0, 0x0c, 0x2e, 0xe2, 0x93, 0x6a, 0x2d,
+	0x26, 0x61, 0xf8, 0xa3, 0x39, 0x87, 0xc4, 0xaa, 0x41, 0x8b, 0x69, 0xce, 0xca, 0x59, 0x1d, 0x6a,
+	0x2e, 0x21, 0xe9, 0x14, 0x69, 0x91, 0xe4, 0xac, 0x4c, 0x97, 0x99, 0x8c, 0x4c, 0x72, 0xcf, 0x24,
+	0xdf, 0xf6, 0x4c, 0x75, 0xd8, 0x2b, 0x5e, 0xe0, 0xe6, 0xd5, 0xf4, 0x56, 0x77, 0xc7, 0x60, 0x39,
+	0xa4, 0xea, 0x20, 0x47, 0xb4, 0xdf, 0x2d, 0x7e, 0x09, 0xd3, 0x8d, 0xe9, 0x47, 0xae, 0x5d, 0x59,
+	0x78, 0x80, 0x68, 0x54, 0x29, 0xe3, 0xf9, 0xc3, 0xb1, 0x43, 0xba, 0x14, 0xf2, 0xc4, 0xc3, 0xbf,
+	0xde, 0xd7, 0x70, 0xe6, 0x3c, 0xe2, 0x6a, 0x74, 0x8f, 0xe2, 0xbf, 0xc8, 0x4f, 0xc9, 0xfb, 0xc4,
+	0x35, 0xcd, 0x79, 0x88, 0x78, 0xff, 0x3d, 0x00, 0x49, 0x29, 0x3d, 0xdb, 0x94, 0x01, 0x00, 0x00,
+}
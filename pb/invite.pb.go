@@ -0,0 +1,131 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// source: invite.proto
+
+package pb
+
+import proto "github.com/golang/protobuf/proto"
+import fmt "fmt"
+import math "math"
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.ProtoPackageIsVersion2 // please upgrade the proto package
+
+type InviteStatusRequest struct {
+	ThreadId             string   `protobuf:"bytes,1,opt,name=threadId,proto3" json:"threadId,omitempty"`
+	BlockId              string   `protobuf:"bytes,2,opt,name=blockId,proto3" json:"blockId,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *InviteStatusRequest) Reset()         { *m = InviteStatusRequest{} }
+func (m *InviteStatusRequest) String() string { return proto.CompactTextString(m) }
+func (*InviteStatusRequest) ProtoMessage()    {}
+func (*InviteStatusRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_invite_e10090e6ec1de0ec, []int{0}
+}
+func (m *InviteStatusRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_InviteStatusRequest.Unmarshal(m, b)
+}
+func (m *InviteStatusRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_InviteStatusRequest.Marshal(b, m, deterministic)
+}
+func (dst *InviteStatusRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_InviteStatusRequest.Merge(dst, src)
+}
+func (m *InviteStatusRequest) XXX_Size() int {
+	return xxx_messageInfo_InviteStatusRequest.Size(m)
+}
+func (m *InviteStatusRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_InviteStatusRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_InviteStatusRequest proto.InternalMessageInfo
+
+func (m *InviteStatusRequest) GetThreadId() string {
+	if m != nil {
+		return m.ThreadId
+	}
+	return ""
+}
+
+func (m *InviteStatusRequest) GetBlockId() string {
+	if m != nil {
+		return m.BlockId
+	}
+	return ""
+}
+
+type InviteStatus struct {
+	Valid                bool     `protobuf:"varint,1,opt,name=valid,proto3" json:"valid,omitempty"`
+	Reason               string   `protobuf:"bytes,2,opt,name=reason,proto3" json:"reason,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *InviteStatus) Reset()         { *m = InviteStatus{} }
+func (m *InviteStatus) String() string { return proto.CompactTextString(m) }
+func (*InviteStatus) ProtoMessage()    {}
+func (*InviteStatus) Descriptor() ([]byte, []int) {
+	return fileDescriptor_invite_e10090e6ec1de0ec, []int{1}
+}
+func (m *InviteStatus) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_InviteStatus.Unmarshal(m, b)
+}
+func (m *InviteStatus) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_InviteStatus.Marshal(b, m, deterministic)
+}
+func (dst *InviteStatus) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_InviteStatus.Merge(dst, src)
+}
+func (m *InviteStatus) XXX_Size() int {
+	return xxx_messageInfo_InviteStatus.Size(m)
+}
+func (m *InviteStatus) XXX_DiscardUnknown() {
+	xxx_messageInfo_InviteStatus.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_InviteStatus proto.InternalMessageInfo
+
+func (m *InviteStatus) GetValid() bool {
+	if m != nil {
+		return m.Valid
+	}
+	return false
+}
+
+func (m *InviteStatus) GetReason() string {
+	if m != nil {
+		return m.Reason
+	}
+	return ""
+}
+
+func init() {
+	proto.RegisterType((*InviteStatusRequest)(nil), "InviteStatusRequest")
+	proto.RegisterType((*InviteStatus)(nil), "InviteStatus")
+}
+
+func init() { proto.RegisterFile("invite.proto", fileDescriptor_invite_e10090e6ec1de0ec) }
+
+var fileDescriptor_invite_e10090e6ec1de0ec = []byte{
+	// 137 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xe2, 0xe2, 0xc9, 0xcc, 0x2b, 0xcb,
+	0x2c, 0x49, 0xd5, 0x2b, 0x28, 0xca, 0x2f, 0xc9, 0x57, 0xf2, 0xe6, 0x12, 0xf6, 0x04, 0xf3, 0x83,
+	0x4b, 0x12, 0x4b, 0x4a, 0x8b, 0x83, 0x52, 0x0b, 0x4b, 0x53, 0x8b, 0x4b, 0x84, 0xa4, 0xb8, 0x38,
+	0x4a, 0x32, 0x8a, 0x52, 0x13, 0x53, 0x3c, 0x53, 0x24, 0x18, 0x15, 0x18, 0x35, 0x38, 0x83, 0xe0,
+	0x7c, 0x21, 0x09, 0x2e, 0xf6, 0xa4, 0x9c, 0xfc, 0xe4, 0x6c, 0xcf, 0x14, 0x09, 0x26, 0xb0, 0x14,
+	0x8c, 0xab, 0x64, 0xc3, 0xc5, 0x83, 0x6c, 0x98, 0x90, 0x08, 0x17, 0x6b, 0x59, 0x62, 0x4e, 0x26,
+	0xc4, 0x08, 0x8e, 0x20, 0x08, 0x47, 0x48, 0x8c, 0x8b, 0xad, 0x28, 0x35, 0xb1, 0x38, 0x3f, 0x0f,
+	0xaa, 0x1d, 0xca, 0x73, 0x62, 0x89, 0x62, 0x2a, 0x48, 0x4a, 0x62, 0x03, 0xbb, 0xcb, 0x18, 0x30,
+	0x00, 0x85, 0xe3, 0x03, 0x1e, 0xa7, 0x00, 0x00, 0x00,
+}
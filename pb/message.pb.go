@@ -33,14 +33,25 @@ const (
 	Message_MODERATOR_REMOVE       Message_Type = 7
 	Message_STORE                  Message_Type = 8
 	Message_BLOCK                  Message_Type = 9
+	Message_DEVICE_PAIR            Message_Type = 10
+	Message_DEVICE_REVOKE          Message_Type = 11
+	Message_INVITE_STATUS          Message_Type = 12
+	Message_THREAD_HEADS           Message_Type = 13
+	Message_THREAD_BLOCKS          Message_Type = 14
 	Message_THREAD_INVITE          Message_Type = 100
 	Message_THREAD_EXTERNAL_INVITE Message_Type = 101
 	Message_THREAD_JOIN            Message_Type = 102
 	Message_THREAD_LEAVE           Message_Type = 103
 	Message_THREAD_DATA            Message_Type = 104
 	Message_THREAD_ANNOTATION      Message_Type = 105
+	Message_THREAD_INVITE_REVOKE   Message_Type = 106
+	Message_THREAD_METADATA        Message_Type = 107
+	Message_THREAD_ALBUM           Message_Type = 108
+	Message_THREAD_ALBUM_MEMBER    Message_Type = 109
 	Message_THREAD_IGNORE          Message_Type = 200
 	Message_THREAD_MERGE           Message_Type = 201
+	Message_THREAD_RESTORE         Message_Type = 202
+	Message_THREAD_CAPTION         Message_Type = 203
 	Message_ERROR                  Message_Type = 500
 )
 
@@ -55,14 +66,25 @@ var Message_Type_name = map[int32]string{
 	7:   "MODERATOR_REMOVE",
 	8:   "STORE",
 	9:   "BLOCK",
+	10:  "DEVICE_PAIR",
+	11:  "DEVICE_REVOKE",
+	12:  "INVITE_STATUS",
+	13:  "THREAD_HEADS",
+	14:  "THREAD_BLOCKS",
 	100: "THREAD_INVITE",
 	101: "THREAD_EXTERNAL_INVITE",
 	102: "THREAD_JOIN",
 	103: "THREAD_LEAVE",
 	104: "THREAD_DATA",
 	105: "THREAD_ANNOTATION",
+	106: "THREAD_INVITE_REVOKE",
+	107: "THREAD_METADATA",
+	108: "THREAD_ALBUM",
+	109: "THREAD_ALBUM_MEMBER",
 	200: "THREAD_IGNORE",
 	201: "THREAD_MERGE",
+	202: "THREAD_RESTORE",
+	203: "THREAD_CAPTION",
 	500: "ERROR",
 }
 var Message_Type_value = map[string]int32{
@@ -76,14 +98,25 @@ var Message_Type_value = map[string]int32{
 	"MODERATOR_REMOVE":       7,
 	"STORE":                  8,
 	"BLOCK":                  9,
+	"DEVICE_PAIR":            10,
+	"DEVICE_REVOKE":          11,
+	"INVITE_STATUS":          12,
+	"THREAD_HEADS":           13,
+	"THREAD_BLOCKS":          14,
 	"THREAD_INVITE":          100,
 	"THREAD_EXTERNAL_INVITE": 101,
 	"THREAD_JOIN":            102,
 	"THREAD_LEAVE":           103,
 	"THREAD_DATA":            104,
 	"THREAD_ANNOTATION":      105,
+	"THREAD_INVITE_REVOKE":   106,
+	"THREAD_METADATA":        107,
+	"THREAD_ALBUM":           108,
+	"THREAD_ALBUM_MEMBER":    109,
 	"THREAD_IGNORE":          200,
 	"THREAD_MERGE":           201,
+	"THREAD_RESTORE":         202,
+	"THREAD_CAPTION":         203,
 	"ERROR":                  500,
 }
 
@@ -91,7 +124,7 @@ func (x Message_Type) String() string {
 	return proto.EnumName(Message_Type_name, int32(x))
 }
 func (Message_Type) EnumDescriptor() ([]byte, []int) {
-	return fileDescriptor_message_f50553c7fd9774b3, []int{0, 0}
+	return fileDescriptor_message_ed6d4f9d97e162e7, []int{0, 0}
 }
 
 type Chat_Flag int32
@@ -117,7 +150,7 @@ func (x Chat_Flag) String() string {
 	return proto.EnumName(Chat_Flag_name, int32(x))
 }
 func (Chat_Flag) EnumDescriptor() ([]byte, []int) {
-	return fileDescriptor_message_f50553c7fd9774b3, []int{2, 0}
+	return fileDescriptor_message_ed6d4f9d97e162e7, []int{2, 0}
 }
 
 type Message struct {
@@ -134,7 +167,7 @@ func (m *Message) Reset()         { *m = Message{} }
 func (m *Message) String() string { return proto.CompactTextString(m) }
 func (*Message) ProtoMessage()    {}
 func (*Message) Descriptor() ([]byte, []int) {
-	return fileDescriptor_message_f50553c7fd9774b3, []int{0}
+	return fileDescriptor_message_ed6d4f9d97e162e7, []int{0}
 }
 func (m *Message) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_Message.Unmarshal(m, b)
@@ -195,7 +228,7 @@ func (m *Envelope) Reset()         { *m = Envelope{} }
 func (m *Envelope) String() string { return proto.CompactTextString(m) }
 func (*Envelope) ProtoMessage()    {}
 func (*Envelope) Descriptor() ([]byte, []int) {
-	return fileDescriptor_message_f50553c7fd9774b3, []int{1}
+	return fileDescriptor_message_ed6d4f9d97e162e7, []int{1}
 }
 func (m *Envelope) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_Envelope.Unmarshal(m, b)
@@ -251,7 +284,7 @@ func (m *Chat) Reset()         { *m = Chat{} }
 func (m *Chat) String() string { return proto.CompactTextString(m) }
 func (*Chat) ProtoMessage()    {}
 func (*Chat) Descriptor() ([]byte, []int) {
-	return fileDescriptor_message_f50553c7fd9774b3, []int{2}
+	return fileDescriptor_message_ed6d4f9d97e162e7, []int{2}
 }
 func (m *Chat) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_Chat.Unmarshal(m, b)
@@ -317,7 +350,7 @@ func (m *CidList) Reset()         { *m = CidList{} }
 func (m *CidList) String() string { return proto.CompactTextString(m) }
 func (*CidList) ProtoMessage()    {}
 func (*CidList) Descriptor() ([]byte, []int) {
-	return fileDescriptor_message_f50553c7fd9774b3, []int{3}
+	return fileDescriptor_message_ed6d4f9d97e162e7, []int{3}
 }
 func (m *CidList) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_CidList.Unmarshal(m, b)
@@ -356,7 +389,7 @@ func (m *Block) Reset()         { *m = Block{} }
 func (m *Block) String() string { return proto.CompactTextString(m) }
 func (*Block) ProtoMessage()    {}
 func (*Block) Descriptor() ([]byte, []int) {
-	return fileDescriptor_message_f50553c7fd9774b3, []int{4}
+	return fileDescriptor_message_ed6d4f9d97e162e7, []int{4}
 }
 func (m *Block) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_Block.Unmarshal(m, b)
@@ -402,7 +435,7 @@ func (m *Error) Reset()         { *m = Error{} }
 func (m *Error) String() string { return proto.CompactTextString(m) }
 func (*Error) ProtoMessage()    {}
 func (*Error) Descriptor() ([]byte, []int) {
-	return fileDescriptor_message_f50553c7fd9774b3, []int{5}
+	return fileDescriptor_message_ed6d4f9d97e162e7, []int{5}
 }
 func (m *Error) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_Error.Unmarshal(m, b)
@@ -447,48 +480,54 @@ func init() {
 	proto.RegisterEnum("Chat_Flag", Chat_Flag_name, Chat_Flag_value)
 }
 
-func init() { proto.RegisterFile("message.proto", fileDescriptor_message_f50553c7fd9774b3) }
-
-var fileDescriptor_message_f50553c7fd9774b3 = []byte{
-	// 629 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x64, 0x52, 0x51, 0x6e, 0xd3, 0x40,
-	0x10, 0xad, 0x1d, 0xbb, 0x4e, 0x26, 0x49, 0xd9, 0xae, 0x0a, 0x32, 0x15, 0x94, 0xe0, 0xaf, 0xf0,
-	0xe3, 0x4a, 0xa9, 0x38, 0xc0, 0x36, 0xd9, 0xa4, 0xa6, 0x8e, 0x5d, 0x6d, 0x4d, 0xa1, 0xfc, 0x54,
-	0x4e, 0xbc, 0x75, 0x4d, 0xd3, 0xd8, 0xd8, 0x2e, 0x28, 0xf7, 0x03, 0x09, 0x8e, 0xc1, 0x1d, 0x38,
-	0x00, 0xda, 0x8d, 0x4d, 0x03, 0xfc, 0xcd, 0xbc, 0x99, 0x7d, 0xfb, 0x66, 0xe6, 0x41, 0xf7, 0x8e,
-	0x17, 0x45, 0x18, 0x73, 0x3b, 0xcb, 0xd3, 0x32, 0xdd, 0x7f, 0x1a, 0xa7, 0x69, 0xbc, 0xe0, 0x87,
-	0x32, 0x9b, 0xdd, 0x5f, 0x1f, 0x86, 0xcb, 0x55, 0x55, 0x7a, 0xf1, 0x6f, 0xa9, 0x4c, 0xee, 0x78,
-	0x51, 0x86, 0x77, 0xd9, 0xba, 0xc1, 0xfa, 0xd9, 0x00, 0x63, 0xba, 0x66, 0xc3, 0x2f, 0x41, 0x2b,
-	0x57, 0x19, 0x37, 0x95, 0x9e, 0xd2, 0xdf, 0x19, 0x74, 0xed, 0x0a, 0xb7, 0x83, 0x55, 0xc6, 0x99,
-	0x2c, 0x61, 0x1b, 0x8c, 0x2c, 0x5c, 0x2d, 0xd2, 0x30, 0x32, 0xd5, 0x9e, 0xd2, 0x6f, 0x0f, 0xf6,
-	0xec, 0xf5, 0x0f, 0x76, 0xfd, 0x83, 0x4d, 0x96, 0x2b, 0x56, 0x37, 0xe1, 0x67, 0xd0, 0xca, 0xf9,
-	0xa7, 0x7b, 0x5e, 0x94, 0x4e, 0x64, 0x36, 0x7a, 0x4a, 0x5f, 0x67, 0x0f, 0x00, 0x3e, 0x00, 0x48,
-	0x0a, 0xc6, 0x8b, 0x2c, 0x5d, 0x16, 0xdc, 0xd4, 0x7a, 0x4a, 0xbf, 0xc9, 0x36, 0x10, 0xeb, 0xab,
-	0x0a, 0x9a, 0xf8, 0x1c, 0x37, 0x41, 0x3b, 0x73, 0xbc, 0x09, 0xda, 0x12, 0xd1, 0xf0, 0x84, 0x04,
-	0x48, 0xc1, 0x00, 0xdb, 0x63, 0xdf, 0x75, 0xfd, 0x77, 0x48, 0xc5, 0x1d, 0x68, 0xbe, 0xf5, 0xaa,
-	0xac, 0x81, 0x1f, 0x41, 0xdb, 0x1f, 0x8f, 0x5d, 0xc7, 0xa3, 0x57, 0x64, 0x78, 0x8a, 0x34, 0xbc,
-	0x0b, 0xdd, 0x1a, 0x60, 0xd4, 0x25, 0x97, 0x48, 0x17, 0xd0, 0xd4, 0x1f, 0x51, 0x46, 0x02, 0x9f,
-	0x5d, 0x91, 0xd1, 0x08, 0x6d, 0xe3, 0x3d, 0x40, 0x0f, 0x10, 0xa3, 0x53, 0xff, 0x82, 0x22, 0x03,
-	0xb7, 0x40, 0x3f, 0x0f, 0x7c, 0x46, 0x51, 0x53, 0x84, 0xc7, 0xae, 0x3f, 0x3c, 0x45, 0x2d, 0xf1,
-	0x3c, 0x38, 0x61, 0x94, 0x8c, 0xae, 0x1c, 0xef, 0xc2, 0x09, 0x28, 0x8a, 0xf0, 0x3e, 0x3c, 0xa9,
-	0x20, 0xfa, 0x3e, 0xa0, 0xcc, 0x23, 0x6e, 0x5d, 0xe3, 0x42, 0x51, 0x55, 0x7b, 0xe3, 0x3b, 0x1e,
-	0xba, 0xc6, 0x08, 0x3a, 0x15, 0xe0, 0x52, 0x72, 0x41, 0x51, 0xbc, 0xd1, 0x32, 0x22, 0x01, 0x41,
-	0x37, 0xf8, 0x31, 0xec, 0x56, 0x00, 0xf1, 0x3c, 0x3f, 0x20, 0x81, 0xe3, 0x7b, 0x28, 0xc1, 0xf8,
-	0xe1, 0xe7, 0x89, 0x27, 0x74, 0x7d, 0x57, 0xf0, 0xee, 0x1f, 0xb6, 0x29, 0x65, 0x13, 0x8a, 0x7e,
-	0x88, 0xed, 0xe8, 0x94, 0x31, 0x9f, 0xa1, 0x5f, 0x0d, 0xeb, 0x0c, 0x9a, 0x74, 0xf9, 0x99, 0x2f,
-	0xd2, 0x8c, 0x63, 0x0b, 0x8c, 0xca, 0x3c, 0xf2, 0xcc, 0xed, 0x41, 0xb3, 0x3e, 0x33, 0xab, 0x0b,
-	0x78, 0x07, 0xd4, 0xec, 0x56, 0xde, 0xb7, 0xc3, 0xd4, 0xec, 0x16, 0x23, 0x68, 0x14, 0x49, 0x2c,
-	0xcf, 0xd7, 0x61, 0x22, 0xb4, 0xbe, 0x29, 0xa0, 0x0d, 0x6f, 0xc2, 0x52, 0xb4, 0x26, 0x91, 0x64,
-	0x6a, 0x31, 0x35, 0x89, 0xb0, 0x09, 0x46, 0x71, 0x3f, 0xfb, 0xc8, 0xe7, 0xa5, 0x7c, 0xdf, 0x62,
-	0x75, 0x8a, 0x6d, 0xd0, 0xa2, 0xb0, 0xe4, 0x92, 0xa5, 0x3d, 0xd8, 0xff, 0xcf, 0x36, 0x41, 0x6d,
-	0x4c, 0x26, 0xfb, 0x04, 0x53, 0x2d, 0x54, 0x5b, 0x33, 0xd5, 0xf2, 0x0e, 0x40, 0xbb, 0x5e, 0x84,
-	0xb1, 0xa9, 0x4b, 0x9b, 0x82, 0x2d, 0x84, 0xd8, 0xe3, 0x45, 0x18, 0x33, 0x89, 0x5b, 0xaf, 0x40,
-	0x13, 0x19, 0x6e, 0x83, 0x31, 0xa5, 0xe7, 0xe7, 0x64, 0x42, 0xd1, 0x96, 0x70, 0x4b, 0x70, 0x29,
-	0x3d, 0xa4, 0x08, 0x0f, 0x89, 0x65, 0x21, 0xd5, 0x7a, 0x0e, 0xc6, 0x30, 0x89, 0xdc, 0xa4, 0x28,
-	0x31, 0x06, 0x6d, 0x9e, 0x44, 0x85, 0xa9, 0xf4, 0x1a, 0xfd, 0x16, 0x93, 0xb1, 0x75, 0x04, 0xfa,
-	0xf1, 0x22, 0x9d, 0xdf, 0x0a, 0x31, 0x79, 0xf8, 0x65, 0x14, 0x96, 0xa1, 0x9c, 0xb5, 0xc3, 0xea,
-	0x54, 0xec, 0x66, 0x9e, 0x44, 0xd5, 0xb0, 0x22, 0xb4, 0x5e, 0x83, 0x4e, 0xf3, 0x3c, 0xcd, 0x25,
-	0x63, 0x1a, 0xad, 0xf7, 0xdc, 0x65, 0x32, 0xde, 0x9c, 0x4a, 0xfd, 0x6b, 0xaa, 0x63, 0xed, 0x83,
-	0x9a, 0xcd, 0x66, 0xdb, 0x72, 0x1f, 0x47, 0xbf, 0x03, 0x00, 0x00, 0xff, 0xff, 0x86, 0xa5, 0xa2,
-	0xcc, 0xe2, 0x03, 0x00, 0x00,
+func init() { proto.RegisterFile("message.proto", fileDescriptor_message_ed6d4f9d97e162e7) }
+
+var fileDescriptor_message_ed6d4f9d97e162e7 = []byte{
+	// 731 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x64, 0x93, 0xdb, 0x72, 0xf2, 0x54,
+	0x14, 0xc7, 0xbf, 0x84, 0xa4, 0xc0, 0xe2, 0xd0, 0xdd, 0xdd, 0xaa, 0xb1, 0xa3, 0x15, 0x73, 0x85,
+	0x37, 0xe9, 0x0c, 0x1d, 0x1f, 0x60, 0x43, 0x36, 0x34, 0x36, 0x07, 0x66, 0x93, 0xa2, 0xf5, 0x86,
+	0x09, 0x24, 0xa5, 0x69, 0x81, 0x44, 0x92, 0xea, 0xf0, 0x3e, 0xbe, 0x88, 0x17, 0xce, 0x78, 0x78,
+	0x1d, 0x1f, 0xc0, 0xd9, 0x9b, 0xc4, 0xa6, 0x7e, 0x77, 0x6b, 0xfd, 0xd6, 0xca, 0x7f, 0x1d, 0xb2,
+	0x17, 0x74, 0xb6, 0x51, 0x96, 0x05, 0xeb, 0xc8, 0x48, 0xf7, 0x49, 0x9e, 0x5c, 0x7e, 0xbe, 0x4e,
+	0x92, 0xf5, 0x26, 0xba, 0x16, 0xde, 0xf2, 0xf5, 0xf1, 0x3a, 0xd8, 0x1d, 0x8a, 0xd0, 0x57, 0xff,
+	0x0f, 0xe5, 0xf1, 0x36, 0xca, 0xf2, 0x60, 0x9b, 0x1e, 0x13, 0xf4, 0xdf, 0x54, 0xa8, 0x3b, 0x47,
+	0x35, 0xfc, 0x35, 0x28, 0xf9, 0x21, 0x8d, 0x34, 0xa9, 0x27, 0xf5, 0xbb, 0x83, 0x8e, 0x51, 0x70,
+	0xc3, 0x3f, 0xa4, 0x11, 0x13, 0x21, 0x6c, 0x40, 0x3d, 0x0d, 0x0e, 0x9b, 0x24, 0x08, 0x35, 0xb9,
+	0x27, 0xf5, 0x5b, 0x83, 0x0b, 0xe3, 0x58, 0xc1, 0x28, 0x2b, 0x18, 0x64, 0x77, 0x60, 0x65, 0x12,
+	0xfe, 0x02, 0x9a, 0xfb, 0xe8, 0xa7, 0xd7, 0x28, 0xcb, 0xad, 0x50, 0xab, 0xf5, 0xa4, 0xbe, 0xca,
+	0xde, 0x00, 0xbe, 0x02, 0x88, 0x33, 0x16, 0x65, 0x69, 0xb2, 0xcb, 0x22, 0x4d, 0xe9, 0x49, 0xfd,
+	0x06, 0xab, 0x10, 0xfd, 0x57, 0x05, 0x14, 0x5e, 0x1c, 0x37, 0x40, 0x99, 0x5a, 0xee, 0x04, 0x7d,
+	0xe0, 0xd6, 0xe8, 0x96, 0xf8, 0x48, 0xc2, 0x00, 0x27, 0x63, 0xcf, 0xb6, 0xbd, 0xef, 0x91, 0x8c,
+	0xdb, 0xd0, 0xb8, 0x77, 0x0b, 0xaf, 0x86, 0x4f, 0xa1, 0xe5, 0x8d, 0xc7, 0xb6, 0xe5, 0xd2, 0x05,
+	0x19, 0xdd, 0x21, 0x05, 0x9f, 0x41, 0xa7, 0x04, 0x8c, 0xda, 0xe4, 0x01, 0xa9, 0x1c, 0x39, 0x9e,
+	0x49, 0x19, 0xf1, 0x3d, 0xb6, 0x20, 0xa6, 0x89, 0x4e, 0xf0, 0x05, 0xa0, 0x37, 0xc4, 0xa8, 0xe3,
+	0xcd, 0x29, 0xaa, 0xe3, 0x26, 0xa8, 0x33, 0xdf, 0x63, 0x14, 0x35, 0xb8, 0x39, 0xb4, 0xbd, 0xd1,
+	0x1d, 0x6a, 0xf2, 0x12, 0x26, 0x9d, 0x5b, 0x23, 0xba, 0x98, 0x12, 0x8b, 0x21, 0xe0, 0x7a, 0x05,
+	0x60, 0x74, 0xee, 0xdd, 0x51, 0xd4, 0xe2, 0xc8, 0x72, 0xe7, 0x96, 0x4f, 0x17, 0x33, 0x9f, 0xf8,
+	0xf7, 0x33, 0xd4, 0xc6, 0x08, 0xda, 0xfe, 0x2d, 0xa3, 0xc4, 0x5c, 0xdc, 0x52, 0x62, 0xce, 0x50,
+	0x87, 0x27, 0x15, 0x44, 0x48, 0xcf, 0x50, 0xb7, 0x82, 0x8e, 0x9f, 0xa3, 0x10, 0x5f, 0xc2, 0xa7,
+	0x05, 0xa2, 0x3f, 0xf8, 0x94, 0xb9, 0xc4, 0x2e, 0x63, 0x11, 0x6f, 0xa5, 0x88, 0x7d, 0xe7, 0x59,
+	0x2e, 0x7a, 0xac, 0x14, 0xb1, 0x29, 0x99, 0x53, 0xb4, 0xae, 0xa4, 0x98, 0xc4, 0x27, 0xe8, 0x09,
+	0x7f, 0x02, 0x67, 0x05, 0x20, 0xae, 0xeb, 0xf9, 0xc4, 0xb7, 0x3c, 0x17, 0xc5, 0x58, 0x83, 0x8b,
+	0x77, 0x95, 0xcb, 0x59, 0x9e, 0xf1, 0x39, 0x9c, 0x16, 0x11, 0x87, 0xfa, 0x44, 0xa8, 0xbc, 0x54,
+	0x0a, 0x11, 0x7b, 0x78, 0xef, 0xa0, 0x0d, 0xfe, 0x0c, 0xce, 0xab, 0x64, 0xe1, 0x50, 0x67, 0x48,
+	0x19, 0xda, 0x62, 0xfc, 0x36, 0xd3, 0xc4, 0xe5, 0xdb, 0xfc, 0x43, 0xc2, 0x67, 0xff, 0x7d, 0xee,
+	0x50, 0x36, 0xa1, 0xe8, 0x4f, 0x09, 0x9f, 0x43, 0xb7, 0x40, 0x8c, 0x1e, 0xb7, 0xfe, 0x57, 0x15,
+	0x8e, 0xc8, 0x54, 0x74, 0xfa, 0x37, 0xff, 0xfb, 0x2a, 0x65, 0xcc, 0x63, 0xe8, 0x9f, 0x9a, 0x3e,
+	0x85, 0x06, 0xdd, 0xfd, 0x1c, 0x6d, 0x92, 0x34, 0xc2, 0x3a, 0xd4, 0x8b, 0xe3, 0x10, 0xcf, 0xb8,
+	0x35, 0x68, 0x94, 0xcf, 0x98, 0x95, 0x01, 0xdc, 0x05, 0x39, 0x7d, 0x11, 0xef, 0xb7, 0xcd, 0xe4,
+	0x94, 0xcf, 0x51, 0xcb, 0xe2, 0xb5, 0x78, 0x9e, 0x6d, 0xc6, 0x4d, 0xfd, 0x77, 0x09, 0x94, 0xd1,
+	0x53, 0x90, 0xf3, 0xd4, 0x38, 0x14, 0x4a, 0x4d, 0x26, 0xc7, 0x21, 0xd6, 0xa0, 0x9e, 0xbd, 0x2e,
+	0x9f, 0xa3, 0x55, 0x2e, 0xbe, 0x6f, 0xb2, 0xd2, 0xc5, 0x06, 0x28, 0x61, 0x90, 0x47, 0x42, 0xa5,
+	0x35, 0xb8, 0xfc, 0xe8, 0x2c, 0xfc, 0xf2, 0xf0, 0x98, 0xc8, 0xe3, 0x4a, 0x65, 0xa3, 0xca, 0x51,
+	0xa9, 0x6c, 0xef, 0x0a, 0x94, 0xc7, 0x4d, 0xb0, 0xd6, 0x54, 0x71, 0x86, 0x60, 0xf0, 0x46, 0x8c,
+	0xf1, 0x26, 0x58, 0x33, 0xc1, 0xf5, 0x6f, 0x40, 0xe1, 0x1e, 0x6e, 0x41, 0xdd, 0xa1, 0xb3, 0x19,
+	0x99, 0x50, 0xf4, 0x81, 0x5f, 0x83, 0xff, 0x20, 0x6e, 0x44, 0xe2, 0x37, 0xc2, 0xd7, 0x85, 0x64,
+	0xfd, 0x4b, 0xa8, 0x8f, 0xe2, 0xd0, 0x8e, 0xb3, 0x1c, 0x63, 0x50, 0x56, 0x71, 0x98, 0x69, 0x52,
+	0xaf, 0xd6, 0x6f, 0x32, 0x61, 0xeb, 0x37, 0xa0, 0x0e, 0x37, 0xc9, 0xea, 0x85, 0x37, 0xb3, 0x0f,
+	0x7e, 0x31, 0x83, 0x3c, 0x10, 0xb3, 0xb6, 0x59, 0xe9, 0xf2, 0xdd, 0xac, 0xe2, 0xb0, 0x18, 0x96,
+	0x9b, 0xfa, 0xb7, 0xa0, 0xd2, 0xfd, 0x3e, 0xd9, 0x0b, 0xc5, 0x24, 0x3c, 0xee, 0xb9, 0xc3, 0x84,
+	0x5d, 0x9d, 0x4a, 0x7e, 0x37, 0xd5, 0x50, 0xf9, 0x51, 0x4e, 0x97, 0xcb, 0x13, 0xb1, 0x8f, 0x9b,
+	0x7f, 0x07, 0x00, 0x45, 0x29, 0x41, 0x3b, 0xc2, 0x04, 0x00, 0x00,
 }
@@ -39,7 +39,30 @@ func (x ThreadData_Type) String() string {
 	return proto.EnumName(ThreadData_Type_name, int32(x))
 }
 func (ThreadData_Type) EnumDescriptor() ([]byte, []int) {
-	return fileDescriptor_thread_blocks_b0b2ea8bb265da2e, []int{6, 0}
+	return fileDescriptor_thread_blocks_21f1476320176838, []int{6, 0}
+}
+
+type ThreadAlbumMember_Action int32
+
+const (
+	ThreadAlbumMember_ADD    ThreadAlbumMember_Action = 0
+	ThreadAlbumMember_REMOVE ThreadAlbumMember_Action = 1
+)
+
+var ThreadAlbumMember_Action_name = map[int32]string{
+	0: "ADD",
+	1: "REMOVE",
+}
+var ThreadAlbumMember_Action_value = map[string]int32{
+	"ADD":    0,
+	"REMOVE": 1,
+}
+
+func (x ThreadAlbumMember_Action) String() string {
+	return proto.EnumName(ThreadAlbumMember_Action_name, int32(x))
+}
+func (ThreadAlbumMember_Action) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_thread_blocks_21f1476320176838, []int{14, 0}
 }
 
 type ThreadBlockHeader struct {
@@ -56,7 +79,7 @@ func (m *ThreadBlockHeader) Reset()         { *m = ThreadBlockHeader{} }
 func (m *ThreadBlockHeader) String() string { return proto.CompactTextString(m) }
 func (*ThreadBlockHeader) ProtoMessage()    {}
 func (*ThreadBlockHeader) Descriptor() ([]byte, []int) {
-	return fileDescriptor_thread_blocks_b0b2ea8bb265da2e, []int{0}
+	return fileDescriptor_thread_blocks_21f1476320176838, []int{0}
 }
 func (m *ThreadBlockHeader) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_ThreadBlockHeader.Unmarshal(m, b)
@@ -116,7 +139,7 @@ func (m *SignedThreadBlock) Reset()         { *m = SignedThreadBlock{} }
 func (m *SignedThreadBlock) String() string { return proto.CompactTextString(m) }
 func (*SignedThreadBlock) ProtoMessage()    {}
 func (*SignedThreadBlock) Descriptor() ([]byte, []int) {
-	return fileDescriptor_thread_blocks_b0b2ea8bb265da2e, []int{1}
+	return fileDescriptor_thread_blocks_21f1476320176838, []int{1}
 }
 func (m *SignedThreadBlock) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_SignedThreadBlock.Unmarshal(m, b)
@@ -164,7 +187,7 @@ func (m *ThreadInvite) Reset()         { *m = ThreadInvite{} }
 func (m *ThreadInvite) String() string { return proto.CompactTextString(m) }
 func (*ThreadInvite) ProtoMessage()    {}
 func (*ThreadInvite) Descriptor() ([]byte, []int) {
-	return fileDescriptor_thread_blocks_b0b2ea8bb265da2e, []int{2}
+	return fileDescriptor_thread_blocks_21f1476320176838, []int{2}
 }
 func (m *ThreadInvite) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_ThreadInvite.Unmarshal(m, b)
@@ -213,19 +236,21 @@ func (m *ThreadInvite) GetInviteeId() string {
 }
 
 type ThreadExternalInvite struct {
-	Header               *ThreadBlockHeader `protobuf:"bytes,1,opt,name=header,proto3" json:"header,omitempty"`
-	SkCipher             []byte             `protobuf:"bytes,2,opt,name=skCipher,proto3" json:"skCipher,omitempty"`
-	SuggestedName        string             `protobuf:"bytes,3,opt,name=suggestedName,proto3" json:"suggestedName,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}           `json:"-"`
-	XXX_unrecognized     []byte             `json:"-"`
-	XXX_sizecache        int32              `json:"-"`
+	Header               *ThreadBlockHeader   `protobuf:"bytes,1,opt,name=header,proto3" json:"header,omitempty"`
+	SkCipher             []byte               `protobuf:"bytes,2,opt,name=skCipher,proto3" json:"skCipher,omitempty"`
+	SuggestedName        string               `protobuf:"bytes,3,opt,name=suggestedName,proto3" json:"suggestedName,omitempty"`
+	Expires              *timestamp.Timestamp `protobuf:"bytes,4,opt,name=expires,proto3" json:"expires,omitempty"`
+	MaxUses              int32                `protobuf:"varint,5,opt,name=maxUses,proto3" json:"maxUses,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}             `json:"-"`
+	XXX_unrecognized     []byte               `json:"-"`
+	XXX_sizecache        int32                `json:"-"`
 }
 
 func (m *ThreadExternalInvite) Reset()         { *m = ThreadExternalInvite{} }
 func (m *ThreadExternalInvite) String() string { return proto.CompactTextString(m) }
 func (*ThreadExternalInvite) ProtoMessage()    {}
 func (*ThreadExternalInvite) Descriptor() ([]byte, []int) {
-	return fileDescriptor_thread_blocks_b0b2ea8bb265da2e, []int{3}
+	return fileDescriptor_thread_blocks_21f1476320176838, []int{3}
 }
 func (m *ThreadExternalInvite) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_ThreadExternalInvite.Unmarshal(m, b)
@@ -266,6 +291,20 @@ func (m *ThreadExternalInvite) GetSuggestedName() string {
 	return ""
 }
 
+func (m *ThreadExternalInvite) GetExpires() *timestamp.Timestamp {
+	if m != nil {
+		return m.Expires
+	}
+	return nil
+}
+
+func (m *ThreadExternalInvite) GetMaxUses() int32 {
+	if m != nil {
+		return m.MaxUses
+	}
+	return 0
+}
+
 type ThreadJoin struct {
 	Header               *ThreadBlockHeader `protobuf:"bytes,1,opt,name=header,proto3" json:"header,omitempty"`
 	InviterPk            []byte             `protobuf:"bytes,2,opt,name=inviterPk,proto3" json:"inviterPk,omitempty"`
@@ -279,7 +318,7 @@ func (m *ThreadJoin) Reset()         { *m = ThreadJoin{} }
 func (m *ThreadJoin) String() string { return proto.CompactTextString(m) }
 func (*ThreadJoin) ProtoMessage()    {}
 func (*ThreadJoin) Descriptor() ([]byte, []int) {
-	return fileDescriptor_thread_blocks_b0b2ea8bb265da2e, []int{4}
+	return fileDescriptor_thread_blocks_21f1476320176838, []int{4}
 }
 func (m *ThreadJoin) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_ThreadJoin.Unmarshal(m, b)
@@ -331,7 +370,7 @@ func (m *ThreadLeave) Reset()         { *m = ThreadLeave{} }
 func (m *ThreadLeave) String() string { return proto.CompactTextString(m) }
 func (*ThreadLeave) ProtoMessage()    {}
 func (*ThreadLeave) Descriptor() ([]byte, []int) {
-	return fileDescriptor_thread_blocks_b0b2ea8bb265da2e, []int{5}
+	return fileDescriptor_thread_blocks_21f1476320176838, []int{5}
 }
 func (m *ThreadLeave) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_ThreadLeave.Unmarshal(m, b)
@@ -373,7 +412,7 @@ func (m *ThreadData) Reset()         { *m = ThreadData{} }
 func (m *ThreadData) String() string { return proto.CompactTextString(m) }
 func (*ThreadData) ProtoMessage()    {}
 func (*ThreadData) Descriptor() ([]byte, []int) {
-	return fileDescriptor_thread_blocks_b0b2ea8bb265da2e, []int{6}
+	return fileDescriptor_thread_blocks_21f1476320176838, []int{6}
 }
 func (m *ThreadData) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_ThreadData.Unmarshal(m, b)
@@ -440,7 +479,7 @@ func (m *ThreadIgnore) Reset()         { *m = ThreadIgnore{} }
 func (m *ThreadIgnore) String() string { return proto.CompactTextString(m) }
 func (*ThreadIgnore) ProtoMessage()    {}
 func (*ThreadIgnore) Descriptor() ([]byte, []int) {
-	return fileDescriptor_thread_blocks_b0b2ea8bb265da2e, []int{7}
+	return fileDescriptor_thread_blocks_21f1476320176838, []int{7}
 }
 func (m *ThreadIgnore) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_ThreadIgnore.Unmarshal(m, b)
@@ -474,6 +513,152 @@ func (m *ThreadIgnore) GetDataId() string {
 	return ""
 }
 
+type ThreadRestore struct {
+	Header               *ThreadBlockHeader `protobuf:"bytes,1,opt,name=header,proto3" json:"header,omitempty"`
+	DataId               string             `protobuf:"bytes,2,opt,name=dataId,proto3" json:"dataId,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}           `json:"-"`
+	XXX_unrecognized     []byte             `json:"-"`
+	XXX_sizecache        int32              `json:"-"`
+}
+
+func (m *ThreadRestore) Reset()         { *m = ThreadRestore{} }
+func (m *ThreadRestore) String() string { return proto.CompactTextString(m) }
+func (*ThreadRestore) ProtoMessage()    {}
+func (*ThreadRestore) Descriptor() ([]byte, []int) {
+	return fileDescriptor_thread_blocks_21f1476320176838, []int{8}
+}
+func (m *ThreadRestore) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ThreadRestore.Unmarshal(m, b)
+}
+func (m *ThreadRestore) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ThreadRestore.Marshal(b, m, deterministic)
+}
+func (dst *ThreadRestore) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ThreadRestore.Merge(dst, src)
+}
+func (m *ThreadRestore) XXX_Size() int {
+	return xxx_messageInfo_ThreadRestore.Size(m)
+}
+func (m *ThreadRestore) XXX_DiscardUnknown() {
+	xxx_messageInfo_ThreadRestore.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ThreadRestore proto.InternalMessageInfo
+
+func (m *ThreadRestore) GetHeader() *ThreadBlockHeader {
+	if m != nil {
+		return m.Header
+	}
+	return nil
+}
+
+func (m *ThreadRestore) GetDataId() string {
+	if m != nil {
+		return m.DataId
+	}
+	return ""
+}
+
+type ThreadCaption struct {
+	Header               *ThreadBlockHeader `protobuf:"bytes,1,opt,name=header,proto3" json:"header,omitempty"`
+	DataId               string             `protobuf:"bytes,2,opt,name=dataId,proto3" json:"dataId,omitempty"`
+	CaptionCipher        []byte             `protobuf:"bytes,3,opt,name=captionCipher,proto3" json:"captionCipher,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}           `json:"-"`
+	XXX_unrecognized     []byte             `json:"-"`
+	XXX_sizecache        int32              `json:"-"`
+}
+
+func (m *ThreadCaption) Reset()         { *m = ThreadCaption{} }
+func (m *ThreadCaption) String() string { return proto.CompactTextString(m) }
+func (*ThreadCaption) ProtoMessage()    {}
+func (*ThreadCaption) Descriptor() ([]byte, []int) {
+	return fileDescriptor_thread_blocks_21f1476320176838, []int{9}
+}
+func (m *ThreadCaption) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ThreadCaption.Unmarshal(m, b)
+}
+func (m *ThreadCaption) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ThreadCaption.Marshal(b, m, deterministic)
+}
+func (dst *ThreadCaption) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ThreadCaption.Merge(dst, src)
+}
+func (m *ThreadCaption) XXX_Size() int {
+	return xxx_messageInfo_ThreadCaption.Size(m)
+}
+func (m *ThreadCaption) XXX_DiscardUnknown() {
+	xxx_messageInfo_ThreadCaption.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ThreadCaption proto.InternalMessageInfo
+
+func (m *ThreadCaption) GetHeader() *ThreadBlockHeader {
+	if m != nil {
+		return m.Header
+	}
+	return nil
+}
+
+func (m *ThreadCaption) GetDataId() string {
+	if m != nil {
+		return m.DataId
+	}
+	return ""
+}
+
+func (m *ThreadCaption) GetCaptionCipher() []byte {
+	if m != nil {
+		return m.CaptionCipher
+	}
+	return nil
+}
+
+type ThreadInviteRevoke struct {
+	Header               *ThreadBlockHeader `protobuf:"bytes,1,opt,name=header,proto3" json:"header,omitempty"`
+	BlockId              string             `protobuf:"bytes,2,opt,name=blockId,proto3" json:"blockId,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}           `json:"-"`
+	XXX_unrecognized     []byte             `json:"-"`
+	XXX_sizecache        int32              `json:"-"`
+}
+
+func (m *ThreadInviteRevoke) Reset()         { *m = ThreadInviteRevoke{} }
+func (m *ThreadInviteRevoke) String() string { return proto.CompactTextString(m) }
+func (*ThreadInviteRevoke) ProtoMessage()    {}
+func (*ThreadInviteRevoke) Descriptor() ([]byte, []int) {
+	return fileDescriptor_thread_blocks_21f1476320176838, []int{10}
+}
+func (m *ThreadInviteRevoke) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ThreadInviteRevoke.Unmarshal(m, b)
+}
+func (m *ThreadInviteRevoke) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ThreadInviteRevoke.Marshal(b, m, deterministic)
+}
+func (dst *ThreadInviteRevoke) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ThreadInviteRevoke.Merge(dst, src)
+}
+func (m *ThreadInviteRevoke) XXX_Size() int {
+	return xxx_messageInfo_ThreadInviteRevoke.Size(m)
+}
+func (m *ThreadInviteRevoke) XXX_DiscardUnknown() {
+	xxx_messageInfo_ThreadInviteRevoke.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ThreadInviteRevoke proto.InternalMessageInfo
+
+func (m *ThreadInviteRevoke) GetHeader() *ThreadBlockHeader {
+	if m != nil {
+		return m.Header
+	}
+	return nil
+}
+
+func (m *ThreadInviteRevoke) GetBlockId() string {
+	if m != nil {
+		return m.BlockId
+	}
+	return ""
+}
+
 type ThreadMerge struct {
 	Header               *ThreadBlockHeader `protobuf:"bytes,1,opt,name=header,proto3" json:"header,omitempty"`
 	XXX_NoUnkeyedLiteral struct{}           `json:"-"`
@@ -485,7 +670,7 @@ func (m *ThreadMerge) Reset()         { *m = ThreadMerge{} }
 func (m *ThreadMerge) String() string { return proto.CompactTextString(m) }
 func (*ThreadMerge) ProtoMessage()    {}
 func (*ThreadMerge) Descriptor() ([]byte, []int) {
-	return fileDescriptor_thread_blocks_b0b2ea8bb265da2e, []int{8}
+	return fileDescriptor_thread_blocks_21f1476320176838, []int{11}
 }
 func (m *ThreadMerge) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_ThreadMerge.Unmarshal(m, b)
@@ -512,6 +697,176 @@ func (m *ThreadMerge) GetHeader() *ThreadBlockHeader {
 	return nil
 }
 
+type ThreadMetadata struct {
+	Header               *ThreadBlockHeader `protobuf:"bytes,1,opt,name=header,proto3" json:"header,omitempty"`
+	NameCipher           []byte             `protobuf:"bytes,2,opt,name=nameCipher,proto3" json:"nameCipher,omitempty"`
+	DescriptionCipher    []byte             `protobuf:"bytes,3,opt,name=descriptionCipher,proto3" json:"descriptionCipher,omitempty"`
+	CoverId              string             `protobuf:"bytes,4,opt,name=coverId,proto3" json:"coverId,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}           `json:"-"`
+	XXX_unrecognized     []byte             `json:"-"`
+	XXX_sizecache        int32              `json:"-"`
+}
+
+func (m *ThreadMetadata) Reset()         { *m = ThreadMetadata{} }
+func (m *ThreadMetadata) String() string { return proto.CompactTextString(m) }
+func (*ThreadMetadata) ProtoMessage()    {}
+func (*ThreadMetadata) Descriptor() ([]byte, []int) {
+	return fileDescriptor_thread_blocks_21f1476320176838, []int{12}
+}
+func (m *ThreadMetadata) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ThreadMetadata.Unmarshal(m, b)
+}
+func (m *ThreadMetadata) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ThreadMetadata.Marshal(b, m, deterministic)
+}
+func (dst *ThreadMetadata) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ThreadMetadata.Merge(dst, src)
+}
+func (m *ThreadMetadata) XXX_Size() int {
+	return xxx_messageInfo_ThreadMetadata.Size(m)
+}
+func (m *ThreadMetadata) XXX_DiscardUnknown() {
+	xxx_messageInfo_ThreadMetadata.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ThreadMetadata proto.InternalMessageInfo
+
+func (m *ThreadMetadata) GetHeader() *ThreadBlockHeader {
+	if m != nil {
+		return m.Header
+	}
+	return nil
+}
+
+func (m *ThreadMetadata) GetNameCipher() []byte {
+	if m != nil {
+		return m.NameCipher
+	}
+	return nil
+}
+
+func (m *ThreadMetadata) GetDescriptionCipher() []byte {
+	if m != nil {
+		return m.DescriptionCipher
+	}
+	return nil
+}
+
+func (m *ThreadMetadata) GetCoverId() string {
+	if m != nil {
+		return m.CoverId
+	}
+	return ""
+}
+
+type ThreadAlbum struct {
+	Header               *ThreadBlockHeader `protobuf:"bytes,1,opt,name=header,proto3" json:"header,omitempty"`
+	NameCipher           []byte             `protobuf:"bytes,2,opt,name=nameCipher,proto3" json:"nameCipher,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}           `json:"-"`
+	XXX_unrecognized     []byte             `json:"-"`
+	XXX_sizecache        int32              `json:"-"`
+}
+
+func (m *ThreadAlbum) Reset()         { *m = ThreadAlbum{} }
+func (m *ThreadAlbum) String() string { return proto.CompactTextString(m) }
+func (*ThreadAlbum) ProtoMessage()    {}
+func (*ThreadAlbum) Descriptor() ([]byte, []int) {
+	return fileDescriptor_thread_blocks_21f1476320176838, []int{13}
+}
+func (m *ThreadAlbum) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ThreadAlbum.Unmarshal(m, b)
+}
+func (m *ThreadAlbum) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ThreadAlbum.Marshal(b, m, deterministic)
+}
+func (dst *ThreadAlbum) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ThreadAlbum.Merge(dst, src)
+}
+func (m *ThreadAlbum) XXX_Size() int {
+	return xxx_messageInfo_ThreadAlbum.Size(m)
+}
+func (m *ThreadAlbum) XXX_DiscardUnknown() {
+	xxx_messageInfo_ThreadAlbum.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ThreadAlbum proto.InternalMessageInfo
+
+func (m *ThreadAlbum) GetHeader() *ThreadBlockHeader {
+	if m != nil {
+		return m.Header
+	}
+	return nil
+}
+
+func (m *ThreadAlbum) GetNameCipher() []byte {
+	if m != nil {
+		return m.NameCipher
+	}
+	return nil
+}
+
+type ThreadAlbumMember struct {
+	Header               *ThreadBlockHeader       `protobuf:"bytes,1,opt,name=header,proto3" json:"header,omitempty"`
+	AlbumId              string                   `protobuf:"bytes,2,opt,name=albumId,proto3" json:"albumId,omitempty"`
+	DataId               string                   `protobuf:"bytes,3,opt,name=dataId,proto3" json:"dataId,omitempty"`
+	Action               ThreadAlbumMember_Action `protobuf:"varint,4,opt,name=action,proto3,enum=ThreadAlbumMember_Action" json:"action,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}                 `json:"-"`
+	XXX_unrecognized     []byte                   `json:"-"`
+	XXX_sizecache        int32                    `json:"-"`
+}
+
+func (m *ThreadAlbumMember) Reset()         { *m = ThreadAlbumMember{} }
+func (m *ThreadAlbumMember) String() string { return proto.CompactTextString(m) }
+func (*ThreadAlbumMember) ProtoMessage()    {}
+func (*ThreadAlbumMember) Descriptor() ([]byte, []int) {
+	return fileDescriptor_thread_blocks_21f1476320176838, []int{14}
+}
+func (m *ThreadAlbumMember) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ThreadAlbumMember.Unmarshal(m, b)
+}
+func (m *ThreadAlbumMember) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ThreadAlbumMember.Marshal(b, m, deterministic)
+}
+func (dst *ThreadAlbumMember) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ThreadAlbumMember.Merge(dst, src)
+}
+func (m *ThreadAlbumMember) XXX_Size() int {
+	return xxx_messageInfo_ThreadAlbumMember.Size(m)
+}
+func (m *ThreadAlbumMember) XXX_DiscardUnknown() {
+	xxx_messageInfo_ThreadAlbumMember.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ThreadAlbumMember proto.InternalMessageInfo
+
+func (m *ThreadAlbumMember) GetHeader() *ThreadBlockHeader {
+	if m != nil {
+		return m.Header
+	}
+	return nil
+}
+
+func (m *ThreadAlbumMember) GetAlbumId() string {
+	if m != nil {
+		return m.AlbumId
+	}
+	return ""
+}
+
+func (m *ThreadAlbumMember) GetDataId() string {
+	if m != nil {
+		return m.DataId
+	}
+	return ""
+}
+
+func (m *ThreadAlbumMember) GetAction() ThreadAlbumMember_Action {
+	if m != nil {
+		return m.Action
+	}
+	return ThreadAlbumMember_ADD
+}
+
 func init() {
 	proto.RegisterType((*ThreadBlockHeader)(nil), "ThreadBlockHeader")
 	proto.RegisterType((*SignedThreadBlock)(nil), "SignedThreadBlock")
@@ -521,41 +876,60 @@ func init() {
 	proto.RegisterType((*ThreadLeave)(nil), "ThreadLeave")
 	proto.RegisterType((*ThreadData)(nil), "ThreadData")
 	proto.RegisterType((*ThreadIgnore)(nil), "ThreadIgnore")
+	proto.RegisterType((*ThreadRestore)(nil), "ThreadRestore")
+	proto.RegisterType((*ThreadCaption)(nil), "ThreadCaption")
+	proto.RegisterType((*ThreadInviteRevoke)(nil), "ThreadInviteRevoke")
 	proto.RegisterType((*ThreadMerge)(nil), "ThreadMerge")
+	proto.RegisterType((*ThreadMetadata)(nil), "ThreadMetadata")
+	proto.RegisterType((*ThreadAlbum)(nil), "ThreadAlbum")
+	proto.RegisterType((*ThreadAlbumMember)(nil), "ThreadAlbumMember")
 	proto.RegisterEnum("ThreadData_Type", ThreadData_Type_name, ThreadData_Type_value)
-}
-
-func init() { proto.RegisterFile("thread_blocks.proto", fileDescriptor_thread_blocks_b0b2ea8bb265da2e) }
-
-var fileDescriptor_thread_blocks_b0b2ea8bb265da2e = []byte{
-	// 462 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xc4, 0x53, 0x4d, 0x6f, 0xd3, 0x40,
-	0x10, 0xc5, 0xa9, 0x13, 0xea, 0x69, 0x40, 0xe9, 0x52, 0x21, 0x2b, 0x20, 0x11, 0xad, 0x7a, 0x88,
-	0x38, 0xb8, 0x52, 0x39, 0x71, 0x2d, 0x54, 0x34, 0x08, 0x68, 0xb4, 0xf5, 0x01, 0x71, 0x41, 0x9b,
-	0x7a, 0xb0, 0x2d, 0x27, 0xde, 0xd5, 0x7a, 0x53, 0x91, 0x3b, 0x7f, 0x81, 0x0b, 0x7f, 0x8c, 0xbf,
-	0x83, 0x76, 0xd7, 0x1f, 0x8d, 0x38, 0xf9, 0xd4, 0xe3, 0x7b, 0x33, 0x7e, 0xf3, 0xde, 0xec, 0x18,
-	0x9e, 0xe9, 0x4c, 0x21, 0x4f, 0xbe, 0xaf, 0xd6, 0xe2, 0xb6, 0xa8, 0x22, 0xa9, 0x84, 0x16, 0xd3,
-	0x57, 0xa9, 0x10, 0xe9, 0x1a, 0xcf, 0x2c, 0x5a, 0x6d, 0x7f, 0x9c, 0xe9, 0x7c, 0x83, 0x95, 0xe6,
-	0x1b, 0xe9, 0x1a, 0xe8, 0x6f, 0x0f, 0x8e, 0x63, 0xfb, 0xe1, 0x85, 0xf9, 0xee, 0x0a, 0x79, 0x82,
-	0x8a, 0x44, 0xe0, 0x27, 0x5c, 0x63, 0xe8, 0xcd, 0xbc, 0xf9, 0xd1, 0xf9, 0x34, 0x72, 0x2a, 0x51,
-	0xa3, 0x12, 0xc5, 0x8d, 0x0a, 0xb3, 0x7d, 0x24, 0x84, 0xc7, 0x92, 0x2b, 0x2c, 0x75, 0x15, 0x0e,
-	0x66, 0x07, 0xf3, 0x80, 0x35, 0x90, 0x4c, 0xe1, 0xd0, 0xf9, 0x5a, 0x16, 0xe1, 0xc1, 0xcc, 0x9b,
-	0x8f, 0x59, 0x8b, 0x4d, 0x8d, 0x6f, 0x75, 0x26, 0xd4, 0xb2, 0x08, 0x7d, 0x57, 0x6b, 0x30, 0xfd,
-	0x00, 0xc7, 0x37, 0x79, 0x5a, 0x62, 0x72, 0xcf, 0x1c, 0x39, 0x81, 0xa1, 0x4d, 0x67, 0x7d, 0x8d,
-	0x99, 0x03, 0xe4, 0x25, 0x04, 0x4e, 0xf2, 0x26, 0x4f, 0xc3, 0x81, 0xad, 0x74, 0x04, 0xfd, 0xe3,
-	0xc1, 0xd8, 0x69, 0x2c, 0xca, 0xbb, 0x5c, 0x23, 0x79, 0x0d, 0xa3, 0xcc, 0xa6, 0xac, 0xd3, 0x91,
-	0xe8, 0xbf, 0xfc, 0xac, 0xee, 0x30, 0x0e, 0xab, 0xe2, 0x5d, 0x2e, 0x33, 0x54, 0xb5, 0x72, 0x8b,
-	0xc9, 0x29, 0x3c, 0xa9, 0xb6, 0x69, 0x8a, 0x95, 0xc6, 0xe4, 0x0b, 0xdf, 0xa0, 0x8d, 0x17, 0xb0,
-	0x7d, 0xd2, 0x98, 0xcb, 0xed, 0x5c, 0x5c, 0x24, 0x36, 0x64, 0xc0, 0x3a, 0x82, 0xfe, 0xf2, 0xe0,
-	0xc4, 0x4d, 0xbf, 0xfc, 0xa9, 0x51, 0x95, 0x7c, 0xfd, 0x10, 0x26, 0xa9, 0x04, 0x70, 0xf2, 0x1f,
-	0x45, 0x5e, 0xf6, 0x9a, 0xdd, 0xc6, 0x33, 0x6f, 0x58, 0xef, 0xbe, 0x25, 0xcc, 0x59, 0xd8, 0x27,
-	0x5a, 0x24, 0xf5, 0xdc, 0x06, 0xd2, 0xb7, 0x70, 0xe4, 0x44, 0x3f, 0x21, 0xbf, 0xeb, 0x15, 0x97,
-	0xfe, 0xf5, 0x1a, 0xb7, 0xef, 0xb9, 0xe6, 0xbd, 0xdc, 0x9e, 0x82, 0xaf, 0x77, 0x12, 0xad, 0xd1,
-	0xa7, 0xe7, 0x93, 0xa8, 0x93, 0x89, 0xe2, 0x9d, 0x44, 0x66, 0xab, 0xe4, 0x39, 0x8c, 0x12, 0xae,
-	0x79, 0x6b, 0xba, 0x46, 0x26, 0x6b, 0x81, 0xbb, 0x7a, 0xd1, 0xee, 0x5e, 0x3b, 0xc2, 0x6c, 0xfa,
-	0x96, 0x4b, 0x9d, 0x8b, 0xb2, 0xee, 0x18, 0xda, 0x8e, 0x7d, 0x92, 0xbe, 0x00, 0xdf, 0x4c, 0x22,
-	0x01, 0x0c, 0x97, 0x57, 0xd7, 0xf1, 0xf5, 0xe4, 0x11, 0x39, 0x04, 0x3f, 0xbe, 0xfc, 0x1a, 0x4f,
-	0x3c, 0xca, 0xda, 0x4b, 0x4d, 0x4b, 0xa1, 0xfa, 0x1d, 0x41, 0x67, 0x7a, 0x70, 0xdf, 0x74, 0xb7,
-	0xe8, 0xcf, 0xa8, 0xd2, 0x5e, 0x92, 0x17, 0xfe, 0xb7, 0x81, 0x5c, 0xad, 0x46, 0xf6, 0xa7, 0x7f,
-	0xf3, 0x2f, 0x00, 0x00, 0xff, 0xff, 0xea, 0x31, 0xea, 0xf2, 0x5f, 0x04, 0x00, 0x00,
+	proto.RegisterEnum("ThreadAlbumMember_Action", ThreadAlbumMember_Action_name, ThreadAlbumMember_Action_value)
+}
+
+func init() { proto.RegisterFile("thread_blocks.proto", fileDescriptor_thread_blocks_21f1476320176838) }
+
+var fileDescriptor_thread_blocks_21f1476320176838 = []byte{
+	// 652 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xc4, 0x55, 0xdb, 0x6e, 0xd3, 0x40,
+	0x10, 0xad, 0x13, 0x27, 0x6d, 0xa6, 0x17, 0xa5, 0x4b, 0x85, 0x4c, 0xb9, 0x45, 0xab, 0x3e, 0x44,
+	0x08, 0xb9, 0xa2, 0xf0, 0xc2, 0x63, 0x2f, 0x11, 0x2d, 0xa2, 0xb4, 0xda, 0x06, 0x04, 0x7d, 0x41,
+	0x9b, 0x78, 0x70, 0xac, 0xc4, 0x17, 0xad, 0x37, 0x51, 0xf3, 0x21, 0xbc, 0xf0, 0xcc, 0x9f, 0xf0,
+	0x0b, 0x48, 0xfc, 0x0e, 0xda, 0x5d, 0xdb, 0x49, 0x15, 0x2a, 0x64, 0x29, 0x12, 0x8f, 0x67, 0x76,
+	0xe6, 0xec, 0x39, 0x33, 0xe3, 0x35, 0xdc, 0x93, 0x03, 0x81, 0xdc, 0xfb, 0xd2, 0x1b, 0xc5, 0xfd,
+	0x61, 0xea, 0x26, 0x22, 0x96, 0xf1, 0xee, 0x53, 0x3f, 0x8e, 0xfd, 0x11, 0xee, 0x6b, 0xd4, 0x1b,
+	0x7f, 0xdd, 0x97, 0x41, 0x88, 0xa9, 0xe4, 0x61, 0x62, 0x12, 0xe8, 0x37, 0x0b, 0xb6, 0xbb, 0xba,
+	0xf0, 0x48, 0xd5, 0x9d, 0x22, 0xf7, 0x50, 0x10, 0x17, 0x6c, 0x8f, 0x4b, 0x74, 0xac, 0x96, 0xd5,
+	0x5e, 0x3f, 0xd8, 0x75, 0x0d, 0x8b, 0x9b, 0xb3, 0xb8, 0xdd, 0x9c, 0x85, 0xe9, 0x3c, 0xe2, 0xc0,
+	0x6a, 0xc2, 0x05, 0x46, 0x32, 0x75, 0x2a, 0xad, 0x6a, 0xbb, 0xc1, 0x72, 0x48, 0x76, 0x61, 0xcd,
+	0xe8, 0xba, 0x1c, 0x3a, 0xd5, 0x96, 0xd5, 0xde, 0x60, 0x05, 0x56, 0x67, 0x7c, 0x2c, 0x07, 0xb1,
+	0xb8, 0x1c, 0x3a, 0xb6, 0x39, 0xcb, 0x31, 0x7d, 0x03, 0xdb, 0x57, 0x81, 0x1f, 0xa1, 0x37, 0x27,
+	0x8e, 0xec, 0x40, 0x4d, 0xbb, 0xd3, 0xba, 0x36, 0x98, 0x01, 0xe4, 0x11, 0x34, 0x0c, 0xe5, 0x55,
+	0xe0, 0x3b, 0x15, 0x7d, 0x32, 0x0b, 0xd0, 0xef, 0x16, 0x6c, 0x18, 0x8e, 0xb3, 0x68, 0x12, 0x48,
+	0x24, 0xcf, 0xa0, 0x3e, 0xd0, 0x2e, 0x33, 0x77, 0xc4, 0x5d, 0xf0, 0xcf, 0xb2, 0x0c, 0xa5, 0x30,
+	0x1d, 0x1e, 0x07, 0xc9, 0x00, 0x45, 0xc6, 0x5c, 0x60, 0xb2, 0x07, 0x9b, 0xe9, 0xd8, 0xf7, 0x31,
+	0x95, 0xe8, 0xbd, 0xe7, 0x21, 0x6a, 0x7b, 0x0d, 0x76, 0x3b, 0xa8, 0xc4, 0x05, 0xfa, 0x5e, 0x3c,
+	0xf3, 0xb4, 0xc9, 0x06, 0x9b, 0x05, 0xe8, 0x2f, 0x0b, 0x76, 0xcc, 0xed, 0x9d, 0x1b, 0x89, 0x22,
+	0xe2, 0xa3, 0xff, 0x22, 0xf2, 0x15, 0xac, 0xe2, 0x4d, 0x12, 0x08, 0x4c, 0x1d, 0xfb, 0x9f, 0x13,
+	0xcf, 0x53, 0xd5, 0xd0, 0x43, 0x7e, 0xf3, 0x21, 0xc5, 0xd4, 0xa9, 0xb5, 0xac, 0x76, 0x8d, 0xe5,
+	0x90, 0x26, 0x00, 0x46, 0xee, 0xdb, 0x38, 0x88, 0x4a, 0x79, 0x29, 0xda, 0xa5, 0x76, 0x22, 0x9b,
+	0x65, 0x11, 0x50, 0x37, 0xea, 0x91, 0x9f, 0x79, 0x99, 0x8f, 0x1c, 0xd2, 0xd7, 0xb0, 0x6e, 0x48,
+	0xdf, 0x21, 0x9f, 0x94, 0x6a, 0x1f, 0xfd, 0x6d, 0xe5, 0x6a, 0x4f, 0xb8, 0xe4, 0xa5, 0xd4, 0xee,
+	0x81, 0x2d, 0xa7, 0x09, 0x6a, 0xa1, 0x5b, 0x07, 0x4d, 0x77, 0x46, 0xe3, 0x76, 0xa7, 0x09, 0x32,
+	0x7d, 0x4a, 0xee, 0x43, 0xdd, 0xe3, 0x92, 0x17, 0xa2, 0x33, 0xa4, 0xbc, 0x0e, 0x71, 0x9a, 0x0d,
+	0xce, 0xec, 0xff, 0x2c, 0xa0, 0x26, 0xd7, 0xe7, 0x89, 0x0c, 0xe2, 0x28, 0xcb, 0xa8, 0xe9, 0x8c,
+	0xdb, 0x41, 0xfa, 0x10, 0x6c, 0x75, 0x13, 0x69, 0x40, 0xed, 0xf2, 0xf4, 0xa2, 0x7b, 0xd1, 0x5c,
+	0x21, 0x6b, 0x60, 0x77, 0x3b, 0x9f, 0xba, 0x4d, 0x8b, 0xb2, 0x62, 0xf3, 0xfd, 0x28, 0x16, 0xe5,
+	0x96, 0x6a, 0x26, 0xba, 0x32, 0x2f, 0x9a, 0x5e, 0xc1, 0xa6, 0x29, 0x62, 0x98, 0xca, 0x65, 0x91,
+	0x4e, 0x73, 0xd2, 0x63, 0x63, 0x6e, 0x19, 0xa4, 0x8b, 0x0d, 0xac, 0xfe, 0xad, 0x81, 0xd7, 0x40,
+	0xe6, 0x5f, 0x07, 0x86, 0x93, 0x78, 0x58, 0xce, 0xd4, 0xdc, 0x52, 0x56, 0xee, 0x58, 0xca, 0x73,
+	0x14, 0x7e, 0xb9, 0xa5, 0xfc, 0x61, 0xc1, 0x56, 0x5e, 0x2b, 0xb9, 0x57, 0x76, 0x31, 0x9f, 0x00,
+	0x44, 0x3c, 0xc4, 0x5b, 0x8f, 0xc2, 0x5c, 0x84, 0x3c, 0x87, 0x6d, 0x0f, 0xd3, 0xbe, 0x08, 0x16,
+	0xfb, 0xb3, 0x78, 0xa0, 0x1c, 0xf6, 0xe3, 0x09, 0x8a, 0xe2, 0x05, 0xcb, 0x21, 0xfd, 0x9c, 0x3b,
+	0x3c, 0x1c, 0xf5, 0xc6, 0xe1, 0x32, 0x25, 0xd2, 0x9f, 0xc5, 0x8f, 0x49, 0x73, 0x9f, 0x63, 0xd8,
+	0x43, 0x51, 0x76, 0x30, 0x5c, 0x95, 0xce, 0x06, 0x93, 0xc1, 0x3b, 0xbf, 0xc8, 0x17, 0x50, 0xe7,
+	0x7d, 0x65, 0x5c, 0xfb, 0xdc, 0x3a, 0x78, 0xe0, 0x2e, 0x28, 0x70, 0x0f, 0x75, 0x02, 0xcb, 0x12,
+	0xe9, 0x63, 0xa8, 0x9b, 0x08, 0x59, 0x85, 0xea, 0xe1, 0xc9, 0x49, 0x73, 0x85, 0x00, 0xd4, 0x59,
+	0xe7, 0xfc, 0xe2, 0x63, 0xa7, 0x69, 0x1d, 0xd9, 0xd7, 0x95, 0xa4, 0xd7, 0xab, 0xeb, 0x67, 0xf4,
+	0xe5, 0x9f, 0x01, 0x00, 0x03, 0x13, 0x56, 0x41, 0xa3, 0x07, 0x00, 0x00,
 }
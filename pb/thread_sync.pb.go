@@ -0,0 +1,294 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// source: thread_sync.proto
+
+package pb
+
+import proto "github.com/golang/protobuf/proto"
+import fmt "fmt"
+import math "math"
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.ProtoPackageIsVersion2 // please upgrade the proto package
+
+type ThreadHeadsRequest struct {
+	ThreadId             string   `protobuf:"bytes,1,opt,name=threadId,proto3" json:"threadId,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ThreadHeadsRequest) Reset()         { *m = ThreadHeadsRequest{} }
+func (m *ThreadHeadsRequest) String() string { return proto.CompactTextString(m) }
+func (*ThreadHeadsRequest) ProtoMessage()    {}
+func (*ThreadHeadsRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_thread_sync_611893c9e09f879f, []int{0}
+}
+func (m *ThreadHeadsRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ThreadHeadsRequest.Unmarshal(m, b)
+}
+func (m *ThreadHeadsRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ThreadHeadsRequest.Marshal(b, m, deterministic)
+}
+func (dst *ThreadHeadsRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ThreadHeadsRequest.Merge(dst, src)
+}
+func (m *ThreadHeadsRequest) XXX_Size() int {
+	return xxx_messageInfo_ThreadHeadsRequest.Size(m)
+}
+func (m *ThreadHeadsRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ThreadHeadsRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ThreadHeadsRequest proto.InternalMessageInfo
+
+func (m *ThreadHeadsRequest) GetThreadId() string {
+	if m != nil {
+		return m.ThreadId
+	}
+	return ""
+}
+
+type ThreadHeads struct {
+	ThreadId             string   `protobuf:"bytes,1,opt,name=threadId,proto3" json:"threadId,omitempty"`
+	Heads                []string `protobuf:"bytes,2,rep,name=heads,proto3" json:"heads,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ThreadHeads) Reset()         { *m = ThreadHeads{} }
+func (m *ThreadHeads) String() string { return proto.CompactTextString(m) }
+func (*ThreadHeads) ProtoMessage()    {}
+func (*ThreadHeads) Descriptor() ([]byte, []int) {
+	return fileDescriptor_thread_sync_611893c9e09f879f, []int{1}
+}
+func (m *ThreadHeads) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ThreadHeads.Unmarshal(m, b)
+}
+func (m *ThreadHeads) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ThreadHeads.Marshal(b, m, deterministic)
+}
+func (dst *ThreadHeads) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ThreadHeads.Merge(dst, src)
+}
+func (m *ThreadHeads) XXX_Size() int {
+	return xxx_messageInfo_ThreadHeads.Size(m)
+}
+func (m *ThreadHeads) XXX_DiscardUnknown() {
+	xxx_messageInfo_ThreadHeads.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ThreadHeads proto.InternalMessageInfo
+
+func (m *ThreadHeads) GetThreadId() string {
+	if m != nil {
+		return m.ThreadId
+	}
+	return ""
+}
+
+func (m *ThreadHeads) GetHeads() []string {
+	if m != nil {
+		return m.Heads
+	}
+	return nil
+}
+
+type ThreadBlocksRequest struct {
+	ThreadId             string   `protobuf:"bytes,1,opt,name=threadId,proto3" json:"threadId,omitempty"`
+	Want                 []string `protobuf:"bytes,2,rep,name=want,proto3" json:"want,omitempty"`
+	Have                 []string `protobuf:"bytes,3,rep,name=have,proto3" json:"have,omitempty"`
+	Limit                int32    `protobuf:"varint,4,opt,name=limit,proto3" json:"limit,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ThreadBlocksRequest) Reset()         { *m = ThreadBlocksRequest{} }
+func (m *ThreadBlocksRequest) String() string { return proto.CompactTextString(m) }
+func (*ThreadBlocksRequest) ProtoMessage()    {}
+func (*ThreadBlocksRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_thread_sync_611893c9e09f879f, []int{2}
+}
+func (m *ThreadBlocksRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ThreadBlocksRequest.Unmarshal(m, b)
+}
+func (m *ThreadBlocksRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ThreadBlocksRequest.Marshal(b, m, deterministic)
+}
+func (dst *ThreadBlocksRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ThreadBlocksRequest.Merge(dst, src)
+}
+func (m *ThreadBlocksRequest) XXX_Size() int {
+	return xxx_messageInfo_ThreadBlocksRequest.Size(m)
+}
+func (m *ThreadBlocksRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ThreadBlocksRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ThreadBlocksRequest proto.InternalMessageInfo
+
+func (m *ThreadBlocksRequest) GetThreadId() string {
+	if m != nil {
+		return m.ThreadId
+	}
+	return ""
+}
+
+func (m *ThreadBlocksRequest) GetWant() []string {
+	if m != nil {
+		return m.Want
+	}
+	return nil
+}
+
+func (m *ThreadBlocksRequest) GetHave() []string {
+	if m != nil {
+		return m.Have
+	}
+	return nil
+}
+
+func (m *ThreadBlocksRequest) GetLimit() int32 {
+	if m != nil {
+		return m.Limit
+	}
+	return 0
+}
+
+type ThreadBlocks struct {
+	ThreadId             string       `protobuf:"bytes,1,opt,name=threadId,proto3" json:"threadId,omitempty"`
+	Blocks               []*SyncBlock `protobuf:"bytes,2,rep,name=blocks,proto3" json:"blocks,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}     `json:"-"`
+	XXX_unrecognized     []byte       `json:"-"`
+	XXX_sizecache        int32        `json:"-"`
+}
+
+func (m *ThreadBlocks) Reset()         { *m = ThreadBlocks{} }
+func (m *ThreadBlocks) String() string { return proto.CompactTextString(m) }
+func (*ThreadBlocks) ProtoMessage()    {}
+func (*ThreadBlocks) Descriptor() ([]byte, []int) {
+	return fileDescriptor_thread_sync_611893c9e09f879f, []int{3}
+}
+func (m *ThreadBlocks) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ThreadBlocks.Unmarshal(m, b)
+}
+func (m *ThreadBlocks) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ThreadBlocks.Marshal(b, m, deterministic)
+}
+func (dst *ThreadBlocks) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ThreadBlocks.Merge(dst, src)
+}
+func (m *ThreadBlocks) XXX_Size() int {
+	return xxx_messageInfo_ThreadBlocks.Size(m)
+}
+func (m *ThreadBlocks) XXX_DiscardUnknown() {
+	xxx_messageInfo_ThreadBlocks.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ThreadBlocks proto.InternalMessageInfo
+
+func (m *ThreadBlocks) GetThreadId() string {
+	if m != nil {
+		return m.ThreadId
+	}
+	return ""
+}
+
+func (m *ThreadBlocks) GetBlocks() []*SyncBlock {
+	if m != nil {
+		return m.Blocks
+	}
+	return nil
+}
+
+type SyncBlock struct {
+	Id                   string   `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Parents              []string `protobuf:"bytes,2,rep,name=parents,proto3" json:"parents,omitempty"`
+	Envelope             []byte   `protobuf:"bytes,3,opt,name=envelope,proto3" json:"envelope,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *SyncBlock) Reset()         { *m = SyncBlock{} }
+func (m *SyncBlock) String() string { return proto.CompactTextString(m) }
+func (*SyncBlock) ProtoMessage()    {}
+func (*SyncBlock) Descriptor() ([]byte, []int) {
+	return fileDescriptor_thread_sync_611893c9e09f879f, []int{4}
+}
+func (m *SyncBlock) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SyncBlock.Unmarshal(m, b)
+}
+func (m *SyncBlock) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SyncBlock.Marshal(b, m, deterministic)
+}
+func (dst *SyncBlock) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SyncBlock.Merge(dst, src)
+}
+func (m *SyncBlock) XXX_Size() int {
+	return xxx_messageInfo_SyncBlock.Size(m)
+}
+func (m *SyncBlock) XXX_DiscardUnknown() {
+	xxx_messageInfo_SyncBlock.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SyncBlock proto.InternalMessageInfo
+
+func (m *SyncBlock) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+func (m *SyncBlock) GetParents() []string {
+	if m != nil {
+		return m.Parents
+	}
+	return nil
+}
+
+func (m *SyncBlock) GetEnvelope() []byte {
+	if m != nil {
+		return m.Envelope
+	}
+	return nil
+}
+
+func init() {
+	proto.RegisterType((*ThreadHeadsRequest)(nil), "ThreadHeadsRequest")
+	proto.RegisterType((*ThreadHeads)(nil), "ThreadHeads")
+	proto.RegisterType((*ThreadBlocksRequest)(nil), "ThreadBlocksRequest")
+	proto.RegisterType((*ThreadBlocks)(nil), "ThreadBlocks")
+	proto.RegisterType((*SyncBlock)(nil), "SyncBlock")
+}
+
+func init() { proto.RegisterFile("thread_sync.proto", fileDescriptor_thread_sync_611893c9e09f879f) }
+
+var fileDescriptor_thread_sync_611893c9e09f879f = []byte{
+	// 239 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x8c, 0x91, 0xc1, 0x4b, 0xc3, 0x30,
+	0x14, 0xc6, 0x69, 0xd7, 0x4d, 0xfb, 0x36, 0x04, 0xa3, 0x87, 0xe0, 0xa9, 0xe4, 0x94, 0xd3, 0x10,
+	0xfd, 0x03, 0x84, 0x9d, 0xf4, 0x22, 0x18, 0x3d, 0x79, 0x91, 0xb4, 0x79, 0xd0, 0x60, 0x4d, 0x62,
+	0x1b, 0x27, 0xfb, 0xef, 0x25, 0xc9, 0x5a, 0x76, 0x2a, 0xde, 0xde, 0xf7, 0xe5, 0x7b, 0xdf, 0x2f,
+	0x21, 0x70, 0xe9, 0xdb, 0x1e, 0xa5, 0xfa, 0x18, 0x0e, 0xa6, 0xd9, 0xba, 0xde, 0x7a, 0xcb, 0x6e,
+	0x81, 0xbc, 0x45, 0xf3, 0x11, 0xa5, 0x1a, 0x04, 0x7e, 0xff, 0xe0, 0xe0, 0xc9, 0x0d, 0x9c, 0xa7,
+	0xe8, 0x93, 0xa2, 0x59, 0x95, 0xf1, 0x52, 0x4c, 0x9a, 0x3d, 0xc0, 0xfa, 0x64, 0x63, 0x2e, 0x4a,
+	0xae, 0x61, 0xd9, 0x86, 0x10, 0xcd, 0xab, 0x05, 0x2f, 0x45, 0x12, 0xcc, 0xc2, 0x55, 0x2a, 0xd8,
+	0x75, 0xb6, 0xf9, 0xfc, 0x0f, 0x93, 0x10, 0x28, 0x7e, 0xa5, 0xf1, 0xc7, 0x9e, 0x38, 0x07, 0xaf,
+	0x95, 0x7b, 0xa4, 0x8b, 0xe4, 0x85, 0x39, 0x00, 0x3b, 0xfd, 0xa5, 0x3d, 0x2d, 0xaa, 0x8c, 0x2f,
+	0x45, 0x12, 0xec, 0x19, 0x36, 0xa7, 0xc0, 0x59, 0x12, 0x83, 0x55, 0x1d, 0x53, 0x91, 0xb5, 0xbe,
+	0x83, 0xed, 0xeb, 0xc1, 0x34, 0x71, 0x51, 0x1c, 0x4f, 0xd8, 0x0b, 0x94, 0x93, 0x49, 0x2e, 0x20,
+	0xd7, 0x63, 0x4d, 0xae, 0x15, 0xa1, 0x70, 0xe6, 0x64, 0x8f, 0xc6, 0x8f, 0xaf, 0x1e, 0x65, 0xc0,
+	0xa2, 0xd9, 0x63, 0x67, 0x5d, 0xb8, 0x74, 0xc6, 0x37, 0x62, 0xd2, 0xbb, 0xe2, 0x3d, 0x77, 0x75,
+	0xbd, 0x8a, 0x7f, 0x72, 0xff, 0x37, 0x00, 0x43, 0x13, 0xc9, 0x10, 0xa8, 0x01, 0x00, 0x00,
+}
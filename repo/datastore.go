@@ -12,7 +12,14 @@ type Datastore interface {
 	Threads() ThreadStore
 	Devices() DeviceStore
 	Peers() PeerStore
+	Contacts() ContactStore
+	ProfileCache() ProfileCacheStore
+	Search() SearchStore
 	Blocks() BlockStore
+	MissingBlocks() MissingBlockStore
+	PhotoHashes() PhotoHashStore
+	PhotoImports() PhotoImportStore
+	AlbumPhotos() AlbumPhotoStore
 	OfflineMessages() OfflineMessageStore
 	Pointers() PointerStore
 	PinRequests() PinRequestStore
@@ -32,6 +39,10 @@ type ConfigStore interface {
 	Configure(created time.Time) error
 	GetCreationDate() (time.Time, error)
 	IsEncrypted() bool
+	IsIdentityDerived() bool
+	NextKeyIndex(purpose string) (int, error)
+	GetKeyIndex(purpose string) int
+	ReserveKeyIndexes(purpose string, next int) error
 }
 
 type ProfileStore interface {
@@ -40,7 +51,19 @@ type ProfileStore interface {
 	GetUsername() (string, error)
 	SetAvatarId(id string) error
 	GetAvatarId() (string, error)
+	SetAvatar(id string) error
+	GetAvatar() (string, error)
 	GetTokens() (tokens *CafeTokens, err error)
+	SetField(key string, value string) error
+	GetFields() (map[string]string, error)
+}
+
+type ProfileCacheStore interface {
+	Queryable
+	Put(profile *CachedProfile) error
+	Get(id string) *CachedProfile
+	List(query string) []CachedProfile
+	Delete(id string) error
 }
 
 type ThreadStore interface {
@@ -49,6 +72,7 @@ type ThreadStore interface {
 	Get(id string) *Thread
 	List(query string) []Thread
 	UpdateHead(id string, head string) error
+	UpdateMeta(id string, name string, description string, coverId string, metaId string) error
 	Delete(id string) error
 }
 
@@ -60,6 +84,17 @@ type DeviceStore interface {
 	Delete(id string) error
 }
 
+type ContactStore interface {
+	Queryable
+	Add(contact *Contact) error
+	Get(id string) *Contact
+	GetByUsername(username string) *Contact
+	List(query string) []Contact
+	UpdateProfile(id string, username string, avatarId string) error
+	UpdateLastSeen(id string, date time.Time) error
+	Delete(id string) error
+}
+
 type PeerStore interface {
 	Queryable
 	Add(peer *Peer) error
@@ -76,10 +111,21 @@ type BlockStore interface {
 	Get(id string) *Block
 	GetByDataId(dataId string) *Block
 	List(offset string, limit int, query string) []Block
+	ListByDataId(threadId string, dataId string) []Block
+	ListByDataIds(threadId string, dataIds []string) []Block
 	Delete(id string) error
 	DeleteByThreadId(threadId string) error
 }
 
+type SearchStore interface {
+	Queryable
+	Index(entry *SearchEntry) error
+	Search(query string, filters *SearchFilters) []SearchEntry
+	Has(blockId string) bool
+	Delete(blockId string) error
+	DeleteByThreadId(threadId string) error
+}
+
 type OfflineMessageStore interface {
 	Queryable
 	Put(url string) error
@@ -99,6 +145,36 @@ type PointerStore interface {
 	GetAll() ([]Pointer, error)
 }
 
+type MissingBlockStore interface {
+	Queryable
+	Put(mb *MissingBlock) error
+	List(threadId string, before time.Time, limit int) []MissingBlock
+	Delete(id string) error
+	DeleteByThreadId(threadId string) error
+}
+
+type PhotoHashStore interface {
+	Queryable
+	Add(ph *PhotoHash) error
+	Get(hash string) *PhotoHash
+	Delete(hash string) error
+}
+
+type PhotoImportStore interface {
+	Queryable
+	Add(pi *PhotoImport) error
+	Get(threadId string, path string) *PhotoImport
+	DeleteByThreadId(threadId string) error
+}
+
+type AlbumPhotoStore interface {
+	Queryable
+	Add(ap *AlbumPhoto) error
+	Delete(albumId string, dataId string) error
+	ListPhotos(threadId string, albumId string, offset string, limit int) []Block
+	DeleteByThreadId(threadId string) error
+}
+
 type PinRequestStore interface {
 	Queryable
 	Put(pr *PinRequest) error
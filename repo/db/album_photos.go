@@ -0,0 +1,75 @@
+package db
+
+import (
+	"database/sql"
+	"github.com/textileio/textile-go/repo"
+	"sync"
+)
+
+type AlbumPhotoDB struct {
+	modelStore
+}
+
+func NewAlbumPhotoStore(db *sql.DB, lock *sync.Mutex) repo.AlbumPhotoStore {
+	return &AlbumPhotoDB{modelStore{db, lock}}
+}
+
+func (c *AlbumPhotoDB) Add(ap *repo.AlbumPhoto) error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	tx, err := c.db.Begin()
+	if err != nil {
+		return err
+	}
+	stm := `insert or replace into albumphotos(albumId, dataId, threadId) values(?,?,?)`
+	stmt, err := tx.Prepare(stm)
+	if err != nil {
+		log.Errorf("error in tx prepare: %s", err)
+		return err
+	}
+	defer stmt.Close()
+	_, err = stmt.Exec(
+		ap.AlbumId,
+		ap.DataId,
+		ap.ThreadId,
+	)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	tx.Commit()
+	return nil
+}
+
+func (c *AlbumPhotoDB) Delete(albumId string, dataId string) error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	_, err := c.db.Exec("delete from albumphotos where albumId=? and dataId=?", albumId, dataId)
+	return err
+}
+
+// ListPhotos paginates the photo blocks in an album, newest first. A photo added to a
+// thread more than once is listed by its first block.
+func (c *AlbumPhotoDB) ListPhotos(threadId string, albumId string, offset string, limit int) []repo.Block {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	stm := `select b.* from blocks b join albumphotos a on a.dataId=b.dataId and a.threadId=b.threadId
+    where a.albumId=? and b.threadId=? and b.type=?
+    and b.id=(select id from blocks where threadId=b.threadId and type=b.type and dataId=b.dataId order by date asc limit 1)`
+	args := []interface{}{albumId, threadId, int(repo.PhotoBlock)}
+	if offset != "" {
+		stm += " and b.date<(select date from blocks where id=?)"
+		args = append(args, offset)
+	}
+	stm += " order by b.date desc limit ?;"
+	args = append(args, limit)
+	blocks := &BlockDB{c.modelStore}
+	return blocks.handleQuery(stm, args...)
+}
+
+func (c *AlbumPhotoDB) DeleteByThreadId(threadId string) error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	_, err := c.db.Exec("delete from albumphotos where threadId=?", threadId)
+	return err
+}
@@ -0,0 +1,78 @@
+package db
+
+import (
+	"database/sql"
+	"github.com/textileio/textile-go/repo"
+	"sync"
+	"testing"
+	"time"
+)
+
+var apdb repo.AlbumPhotoStore
+var apbdb repo.BlockStore
+
+func init() {
+	setupAlbumPhotoDB()
+}
+
+func setupAlbumPhotoDB() {
+	conn, _ := sql.Open("sqlite3", ":memory:")
+	initDatabaseTables(conn, "")
+	lock := new(sync.Mutex)
+	apdb = NewAlbumPhotoStore(conn, lock)
+	apbdb = NewBlockStore(conn, lock)
+}
+
+func TestAlbumPhotoDB_Add(t *testing.T) {
+	for i, dataId := range []string{"Qmdata1", "Qmdata2", "Qmdata3"} {
+		if err := apbdb.Add(&repo.Block{
+			Id:       "Qmblock" + dataId,
+			Date:     time.Now().Add(time.Duration(i) * time.Minute),
+			ThreadId: "thread",
+			AuthorPk: "author",
+			Type:     repo.PhotoBlock,
+			DataId:   dataId,
+		}); err != nil {
+			t.Fatal(err)
+		}
+		if err := apdb.Add(&repo.AlbumPhoto{AlbumId: "Qmalbum", DataId: dataId, ThreadId: "thread"}); err != nil {
+			t.Error(err)
+		}
+	}
+	if err := apdb.Add(&repo.AlbumPhoto{AlbumId: "Qmalbum", DataId: "Qmdata1", ThreadId: "thread"}); err != nil {
+		t.Errorf("re-adding album photo failed: %s", err)
+	}
+}
+
+func TestAlbumPhotoDB_ListPhotos(t *testing.T) {
+	page := apdb.ListPhotos("thread", "Qmalbum", "", 2)
+	if len(page) != 2 || page[0].DataId != "Qmdata3" {
+		t.Error("list photos returned bad first page")
+		return
+	}
+	page = apdb.ListPhotos("thread", "Qmalbum", page[1].Id, 2)
+	if len(page) != 1 || page[0].DataId != "Qmdata1" {
+		t.Error("list photos returned bad second page")
+	}
+	if len(apdb.ListPhotos("other", "Qmalbum", "", -1)) != 0 {
+		t.Error("listed album photos from another thread")
+	}
+}
+
+func TestAlbumPhotoDB_Delete(t *testing.T) {
+	if err := apdb.Delete("Qmalbum", "Qmdata2"); err != nil {
+		t.Error(err)
+	}
+	if len(apdb.ListPhotos("thread", "Qmalbum", "", -1)) != 2 {
+		t.Error("delete failed")
+	}
+}
+
+func TestAlbumPhotoDB_DeleteByThreadId(t *testing.T) {
+	if err := apdb.DeleteByThreadId("thread"); err != nil {
+		t.Error(err)
+	}
+	if len(apdb.ListPhotos("thread", "Qmalbum", "", -1)) != 0 {
+		t.Error("delete by thread id failed")
+	}
+}
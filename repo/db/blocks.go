@@ -9,6 +9,9 @@ import (
 	"time"
 )
 
+// maxQueryParams is the most data ids bound to a single list query
+const maxQueryParams = 500
+
 type BlockDB struct {
 	modelStore
 }
@@ -53,7 +56,7 @@ func (c *BlockDB) Add(block *repo.Block) error {
 func (c *BlockDB) Get(id string) *repo.Block {
 	c.lock.Lock()
 	defer c.lock.Unlock()
-	ret := c.handleQuery("select * from blocks where id='" + id + "';")
+	ret := c.handleQuery("select * from blocks where id=?;", id)
 	if len(ret) == 0 {
 		return nil
 	}
@@ -63,7 +66,7 @@ func (c *BlockDB) Get(id string) *repo.Block {
 func (c *BlockDB) GetByDataId(dataId string) *repo.Block {
 	c.lock.Lock()
 	defer c.lock.Unlock()
-	ret := c.handleQuery("select * from blocks where dataId='" + dataId + "';")
+	ret := c.handleQuery("select * from blocks where dataId=?;", dataId)
 	if len(ret) == 0 {
 		return nil
 	}
@@ -90,6 +93,33 @@ func (c *BlockDB) List(offset string, limit int, query string) []repo.Block {
 	return c.handleQuery(stm)
 }
 
+func (c *BlockDB) ListByDataId(threadId string, dataId string) []repo.Block {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	return c.handleQuery("select * from blocks where threadId=? and dataId=? order by date desc;", threadId, dataId)
+}
+
+func (c *BlockDB) ListByDataIds(threadId string, dataIds []string) []repo.Block {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	var ret []repo.Block
+	for len(dataIds) > 0 {
+		// stay under sqlite's bound parameter limit
+		n := len(dataIds)
+		if n > maxQueryParams {
+			n = maxQueryParams
+		}
+		args := []interface{}{threadId}
+		for _, id := range dataIds[:n] {
+			args = append(args, id)
+		}
+		stm := "select * from blocks where threadId=? and dataId in (?" + strings.Repeat(",?", n-1) + ") order by date desc;"
+		ret = append(ret, c.handleQuery(stm, args...)...)
+		dataIds = dataIds[n:]
+	}
+	return ret
+}
+
 func (c *BlockDB) Delete(id string) error {
 	c.lock.Lock()
 	defer c.lock.Unlock()
@@ -104,9 +134,9 @@ func (c *BlockDB) DeleteByThreadId(threadId string) error {
 	return err
 }
 
-func (c *BlockDB) handleQuery(stm string) []repo.Block {
+func (c *BlockDB) handleQuery(stm string, args ...interface{}) []repo.Block {
 	var ret []repo.Block
-	rows, err := c.db.Query(stm)
+	rows, err := c.db.Query(stm, args...)
 	if err != nil {
 		log.Errorf("error in db query: %s", err)
 		return nil
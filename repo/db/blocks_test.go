@@ -3,6 +3,7 @@ package db
 import (
 	"crypto/rand"
 	"database/sql"
+	"fmt"
 	"github.com/textileio/textile-go/crypto"
 	"github.com/textileio/textile-go/repo"
 	libp2pc "gx/ipfs/QmaPbCnUMBohSGo3KnxEa2bHqyJVVeEEcwtqJAYxerieBo/go-libp2p-crypto"
@@ -153,6 +154,21 @@ func TestBlockDB_List(t *testing.T) {
 	}
 }
 
+func TestBlockDB_ListByDataIds(t *testing.T) {
+	list := bdb.ListByDataIds(threadId, []string{"Qm789", "Qm456", "nope"})
+	if len(list) != 1 || list[0].Id != "fghijk" {
+		t.Error("returned incorrect blocks")
+		return
+	}
+	var many []string
+	for i := 0; i < 1200; i++ {
+		many = append(many, fmt.Sprintf("Qm%d", i))
+	}
+	if len(bdb.ListByDataIds(threadId, many)) != 1 {
+		t.Error("returned incorrect number of blocks across batches")
+	}
+}
+
 func TestBlockDB_Delete(t *testing.T) {
 	err := bdb.Delete("abcde")
 	if err != nil {
@@ -3,6 +3,7 @@ package db
 import (
 	"database/sql"
 	"github.com/textileio/textile-go/repo"
+	"strconv"
 	"sync"
 	"time"
 )
@@ -40,6 +41,12 @@ func (c *ConfigDB) Configure(created time.Time) error {
 		tx.Rollback()
 		return err
 	}
+	// new identities are derived from the mnemonic, older repos lack this key
+	_, err = stmt.Exec("identity", "mnemonic")
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
 	tx.Commit()
 	return nil
 }
@@ -71,3 +78,95 @@ func (c *ConfigDB) IsEncrypted() bool {
 	}
 	return false
 }
+
+// IsIdentityDerived returns whether or not the identity was derived from the account mnemonic
+func (c *ConfigDB) IsIdentityDerived() bool {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	var value []byte
+	if err := c.db.QueryRow("select value from config where key=?", "identity").Scan(&value); err != nil {
+		return false
+	}
+	return string(value) == "mnemonic"
+}
+
+// NextKeyIndex reserves and returns the next key derivation index for purpose
+func (c *ConfigDB) NextKeyIndex(purpose string) (int, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	key := "key_index_" + purpose
+	tx, err := c.db.Begin()
+	if err != nil {
+		return 0, err
+	}
+	var value []byte
+	index := 0
+	err = tx.QueryRow("select value from config where key=?", key).Scan(&value)
+	switch err {
+	case nil:
+		index, err = strconv.Atoi(string(value))
+		if err != nil {
+			tx.Rollback()
+			return 0, err
+		}
+	case sql.ErrNoRows:
+	default:
+		tx.Rollback()
+		return 0, err
+	}
+	if _, err := tx.Exec("insert or replace into config(key, value) values(?,?)", key, strconv.Itoa(index+1)); err != nil {
+		tx.Rollback()
+		return 0, err
+	}
+	return index, tx.Commit()
+}
+
+// GetKeyIndex returns the next key derivation index for purpose without reserving it
+func (c *ConfigDB) GetKeyIndex(purpose string) int {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	var value []byte
+	if err := c.db.QueryRow("select value from config where key=?", "key_index_"+purpose).Scan(&value); err != nil {
+		return 0
+	}
+	index, err := strconv.Atoi(string(value))
+	if err != nil {
+		return 0
+	}
+	return index
+}
+
+// ReserveKeyIndexes raises the next key derivation index for purpose to at least next,
+// e.g., to skip indexes already used by this account on another install
+func (c *ConfigDB) ReserveKeyIndexes(purpose string, next int) error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	key := "key_index_" + purpose
+	tx, err := c.db.Begin()
+	if err != nil {
+		return err
+	}
+	var value []byte
+	err = tx.QueryRow("select value from config where key=?", key).Scan(&value)
+	switch err {
+	case nil:
+		index, err := strconv.Atoi(string(value))
+		if err != nil {
+			tx.Rollback()
+			return err
+		}
+		if index >= next {
+			tx.Rollback()
+			return nil
+		}
+	case sql.ErrNoRows:
+	default:
+		tx.Rollback()
+		return err
+	}
+	if _, err := tx.Exec("insert or replace into config(key, value) values(?,?)", key, strconv.Itoa(next)); err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
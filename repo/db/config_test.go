@@ -46,3 +46,56 @@ func TestConfigDB_IsEncrypted(t *testing.T) {
 		t.Error("IsEncrypted returned incorrectly")
 	}
 }
+
+func TestConfigDB_IsIdentityDerived(t *testing.T) {
+	if !testDB.Config().IsIdentityDerived() {
+		t.Error("configured identity should be derived")
+	}
+	if _, err := testDB.db.Exec("delete from config where key='identity'"); err != nil {
+		t.Fatal(err)
+	}
+	if testDB.Config().IsIdentityDerived() {
+		t.Error("identity without a marker should not be derived")
+	}
+	if _, err := testDB.db.Exec("insert into config(key, value) values('identity', 'mnemonic')"); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestConfigDB_NextKeyIndex(t *testing.T) {
+	for i := 0; i < 3; i++ {
+		index, err := testDB.Config().NextKeyIndex("thread")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if index != i {
+			t.Errorf("expected key index %d, got %d", i, index)
+		}
+	}
+	index, err := testDB.Config().NextKeyIndex("device")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if index != 0 {
+		t.Error("key indexes are not tracked per purpose")
+	}
+}
+
+func TestConfigDB_ReserveKeyIndexes(t *testing.T) {
+	if err := testDB.Config().ReserveKeyIndexes("reserved", 5); err != nil {
+		t.Fatal(err)
+	}
+	if err := testDB.Config().ReserveKeyIndexes("reserved", 2); err != nil {
+		t.Fatal(err)
+	}
+	if index := testDB.Config().GetKeyIndex("reserved"); index != 5 {
+		t.Errorf("expected next key index 5, got %d", index)
+	}
+	index, err := testDB.Config().NextKeyIndex("reserved")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if index != 5 {
+		t.Errorf("reserved key index was handed out: %d", index)
+	}
+}
@@ -0,0 +1,127 @@
+package db
+
+import (
+	"database/sql"
+	"github.com/textileio/textile-go/repo"
+	"sync"
+	"time"
+)
+
+type ContactDB struct {
+	modelStore
+}
+
+const contactColumns = "id, pk, username, avatarId, added, lastSeen"
+
+func NewContactStore(db *sql.DB, lock *sync.Mutex) repo.ContactStore {
+	return &ContactDB{modelStore{db, lock}}
+}
+
+func (c *ContactDB) Add(contact *repo.Contact) error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	tx, err := c.db.Begin()
+	if err != nil {
+		return err
+	}
+	stm := `insert into contacts(id, pk, username, avatarId, added, lastSeen) values(?,?,?,?,?,?)`
+	stmt, err := tx.Prepare(stm)
+	if err != nil {
+		log.Errorf("error in tx prepare: %s", err)
+		return err
+	}
+	defer stmt.Close()
+	_, err = stmt.Exec(
+		contact.Id,
+		contact.PubKey,
+		contact.Username,
+		contact.AvatarId,
+		int(contact.Added.Unix()),
+		int(contact.LastSeen.Unix()),
+	)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	tx.Commit()
+	return nil
+}
+
+func (c *ContactDB) Get(id string) *repo.Contact {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	ret := c.handleQuery("select "+contactColumns+" from contacts where id=?;", id)
+	if len(ret) == 0 {
+		return nil
+	}
+	return &ret[0]
+}
+
+func (c *ContactDB) GetByUsername(username string) *repo.Contact {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	ret := c.handleQuery("select "+contactColumns+" from contacts where username=? order by lastSeen desc;", username)
+	if len(ret) == 0 {
+		return nil
+	}
+	return &ret[0]
+}
+
+func (c *ContactDB) List(query string) []repo.Contact {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	q := ""
+	if query != "" {
+		q = " where " + query
+	}
+	return c.handleQuery("select " + contactColumns + " from contacts" + q + " order by lastSeen desc;")
+}
+
+func (c *ContactDB) UpdateProfile(id string, username string, avatarId string) error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	_, err := c.db.Exec("update contacts set username=?, avatarId=? where id=?", username, avatarId, id)
+	return err
+}
+
+func (c *ContactDB) UpdateLastSeen(id string, date time.Time) error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	_, err := c.db.Exec("update contacts set lastSeen=? where id=? and lastSeen<?", int(date.Unix()), id, int(date.Unix()))
+	return err
+}
+
+func (c *ContactDB) Delete(id string) error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	_, err := c.db.Exec("delete from contacts where id=?", id)
+	return err
+}
+
+func (c *ContactDB) handleQuery(stm string, args ...interface{}) []repo.Contact {
+	var ret []repo.Contact
+	rows, err := c.db.Query(stm, args...)
+	if err != nil {
+		log.Errorf("error in db query: %s", err)
+		return nil
+	}
+	for rows.Next() {
+		var id, username, avatarId string
+		var pk []byte
+		var addedInt, lastSeenInt int
+		if err := rows.Scan(&id, &pk, &username, &avatarId, &addedInt, &lastSeenInt); err != nil {
+			log.Errorf("error in db scan: %s", err)
+			continue
+		}
+		contact := repo.Contact{
+			Id:       id,
+			PubKey:   pk,
+			Username: username,
+			AvatarId: avatarId,
+			Added:    time.Unix(int64(addedInt), 0),
+			LastSeen: time.Unix(int64(lastSeenInt), 0),
+		}
+		ret = append(ret, contact)
+	}
+	return ret
+}
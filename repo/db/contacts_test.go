@@ -0,0 +1,105 @@
+package db
+
+import (
+	"database/sql"
+	"github.com/textileio/textile-go/repo"
+	"sync"
+	"testing"
+	"time"
+)
+
+var contdb repo.ContactStore
+
+func init() {
+	setupContactDB()
+}
+
+func setupContactDB() {
+	conn, _ := sql.Open("sqlite3", ":memory:")
+	initDatabaseTables(conn, "")
+	contdb = NewContactStore(conn, new(sync.Mutex))
+}
+
+func TestContactDB_Add(t *testing.T) {
+	err := contdb.Add(&repo.Contact{
+		Id:       "abcde",
+		PubKey:   []byte("pk"),
+		Added:    time.Now(),
+		LastSeen: time.Now(),
+	})
+	if err != nil {
+		t.Error(err)
+	}
+	stmt, err := contdb.PrepareQuery("select id from contacts where id=?")
+	defer stmt.Close()
+	var id string
+	err = stmt.QueryRow("abcde").Scan(&id)
+	if err != nil {
+		t.Error(err)
+	}
+	if id != "abcde" {
+		t.Errorf(`expected "abcde" got %s`, id)
+	}
+}
+
+func TestContactDB_Get(t *testing.T) {
+	if contdb.Get("abcde") == nil {
+		t.Error("could not get contact")
+	}
+	if contdb.Get("x' or '1'='1") != nil {
+		t.Error("get should treat the id as a value")
+	}
+}
+
+func TestContactDB_UpdateProfile(t *testing.T) {
+	if err := contdb.UpdateProfile("abcde", "mr.o'toole", "Qm123"); err != nil {
+		t.Error(err)
+	}
+	contact := contdb.GetByUsername("mr.o'toole")
+	if contact == nil {
+		t.Error("could not get contact by username")
+		return
+	}
+	if contact.Id != "abcde" || contact.AvatarId != "Qm123" {
+		t.Error("incorrect contact profile")
+	}
+}
+
+func TestContactDB_UpdateLastSeen(t *testing.T) {
+	later := time.Now().Add(time.Hour)
+	if err := contdb.UpdateLastSeen("abcde", later); err != nil {
+		t.Error(err)
+	}
+	if err := contdb.UpdateLastSeen("abcde", time.Now().Add(-time.Hour)); err != nil {
+		t.Error(err)
+	}
+	contact := contdb.Get("abcde")
+	if contact == nil {
+		t.Error("could not get contact")
+		return
+	}
+	if contact.LastSeen.Unix() != later.Unix() {
+		t.Error("last seen should only move forward")
+	}
+}
+
+func TestContactDB_List(t *testing.T) {
+	setupContactDB()
+	for _, id := range []string{"abc", "def"} {
+		if err := contdb.Add(&repo.Contact{Id: id, PubKey: []byte(id), Added: time.Now(), LastSeen: time.Now()}); err != nil {
+			t.Error(err)
+		}
+	}
+	if len(contdb.List("")) != 2 {
+		t.Error("returned incorrect number of contacts")
+	}
+}
+
+func TestContactDB_Delete(t *testing.T) {
+	if err := contdb.Delete("abc"); err != nil {
+		t.Error(err)
+	}
+	if contdb.Get("abc") != nil {
+		t.Error("delete failed")
+	}
+}
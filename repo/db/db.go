@@ -2,22 +2,36 @@ package db
 
 import (
 	"database/sql"
+	"errors"
+	"fmt"
 	_ "github.com/mutecomm/go-sqlcipher"
 	"github.com/op/go-logging"
 	"github.com/textileio/textile-go/repo"
+	"net/url"
+	"os"
 	"path"
+	"strings"
 	"sync"
 )
 
 var log = logging.MustGetLogger("db")
 
+var ErrInvalidPassword = errors.New("invalid datastore password")
+
 type SQLiteDatastore struct {
 	config          repo.ConfigStore
 	profile         repo.ProfileStore
 	threads         repo.ThreadStore
 	devices         repo.DeviceStore
 	peers           repo.PeerStore
+	contacts        repo.ContactStore
+	profileCache    repo.ProfileCacheStore
+	search          repo.SearchStore
 	blocks          repo.BlockStore
+	missingBlocks   repo.MissingBlockStore
+	photoHashes     repo.PhotoHashStore
+	photoImports    repo.PhotoImportStore
+	albumPhotos     repo.AlbumPhotoStore
 	offlineMessages repo.OfflineMessageStore
 	pointers        repo.PointerStore
 	pinRequests     repo.PinRequestStore
@@ -26,16 +40,22 @@ type SQLiteDatastore struct {
 }
 
 func Create(repoPath, password string) (*SQLiteDatastore, error) {
-	var dbPath string
-	dbPath = path.Join(repoPath, "datastore", "mainnet.db")
-	conn, err := sql.Open("sqlite3", dbPath)
+	dbPath := path.Join(repoPath, "datastore", "mainnet.db")
+	return open(dbPath, password)
+}
+
+// open connects to the database at dbPath. the key is set in the dsn
+// so that every pooled connection is keyed, not just the first one.
+// the driver wraps the key in double quotes, so those are escaped here.
+func open(dbPath string, password string) (*SQLiteDatastore, error) {
+	dsn := dbPath
+	if password != "" {
+		dsn += "?_pragma_key=" + url.QueryEscape(strings.Replace(password, `"`, `""`, -1))
+	}
+	conn, err := sql.Open("sqlite3", dsn)
 	if err != nil {
 		return nil, err
 	}
-	if password != "" {
-		p := "pragma key='" + password + "';"
-		conn.Exec(p)
-	}
 	mux := new(sync.Mutex)
 	sqliteDB := &SQLiteDatastore{
 		config:          NewConfigStore(conn, mux, dbPath),
@@ -43,7 +63,14 @@ func Create(repoPath, password string) (*SQLiteDatastore, error) {
 		threads:         NewThreadStore(conn, mux),
 		devices:         NewDeviceStore(conn, mux),
 		peers:           NewPeerStore(conn, mux),
+		contacts:        NewContactStore(conn, mux),
+		profileCache:    NewProfileCacheStore(conn, mux),
+		search:          NewSearchStore(conn, mux),
 		blocks:          NewBlockStore(conn, mux),
+		missingBlocks:   NewMissingBlockStore(conn, mux),
+		photoHashes:     NewPhotoHashStore(conn, mux),
+		photoImports:    NewPhotoImportStore(conn, mux),
+		albumPhotos:     NewAlbumPhotoStore(conn, mux),
 		offlineMessages: NewOfflineMessageStore(conn, mux),
 		pointers:        NewPointerStore(conn, mux),
 		pinRequests:     NewPinRequestStore(conn, mux),
@@ -82,10 +109,38 @@ func (d *SQLiteDatastore) Peers() repo.PeerStore {
 	return d.peers
 }
 
+func (d *SQLiteDatastore) Contacts() repo.ContactStore {
+	return d.contacts
+}
+
+func (d *SQLiteDatastore) ProfileCache() repo.ProfileCacheStore {
+	return d.profileCache
+}
+
+func (d *SQLiteDatastore) Search() repo.SearchStore {
+	return d.search
+}
+
 func (d *SQLiteDatastore) Blocks() repo.BlockStore {
 	return d.blocks
 }
 
+func (d *SQLiteDatastore) MissingBlocks() repo.MissingBlockStore {
+	return d.missingBlocks
+}
+
+func (d *SQLiteDatastore) PhotoHashes() repo.PhotoHashStore {
+	return d.photoHashes
+}
+
+func (d *SQLiteDatastore) PhotoImports() repo.PhotoImportStore {
+	return d.photoImports
+}
+
+func (d *SQLiteDatastore) AlbumPhotos() repo.AlbumPhotoStore {
+	return d.albumPhotos
+}
+
 func (d *SQLiteDatastore) OfflineMessages() repo.OfflineMessageStore {
 	return d.offlineMessages
 }
@@ -114,6 +169,9 @@ func (d *SQLiteDatastore) Copy(dbPath string, password string) error {
 		if err := rows.Scan(&name); err != nil {
 			return err
 		}
+		if skipCopy(name) {
+			continue
+		}
 		tables = append(tables, name)
 	}
 	if password == "" {
@@ -122,7 +180,7 @@ func (d *SQLiteDatastore) Copy(dbPath string, password string) error {
 			cp = cp + "insert into plaintext." + name + " select * from main." + name + ";"
 		}
 	} else {
-		cp = `attach database '` + dbPath + `' as encrypted key '` + password + `';`
+		cp = `attach database '` + dbPath + `' as encrypted key '` + quote(password) + `';`
 		for _, name := range tables {
 			cp = cp + "insert into encrypted." + name + " select * from main." + name + ";"
 		}
@@ -136,6 +194,67 @@ func (d *SQLiteDatastore) Copy(dbPath string, password string) error {
 	return nil
 }
 
+// ftsShadowSuffixes are the backing tables fts4 keeps for each virtual table
+var ftsShadowSuffixes = []string{"_content", "_segments", "_segdir", "_docsize", "_stat"}
+
+// skipCopy reports whether a table is internal to sqlite. fts shadow tables
+// are filled by inserting through their virtual table, which is copied as usual.
+func skipCopy(name string) bool {
+	if strings.HasPrefix(name, "sqlite_") {
+		return true
+	}
+	for _, suffix := range ftsShadowSuffixes {
+		if strings.HasSuffix(name, suffix) {
+			return true
+		}
+	}
+	return false
+}
+
+// ChangePassword re-keys the datastore at repoPath by copying it into a new
+// database encrypted with next, then swapping it into place.
+// An empty next password leaves the datastore unencrypted.
+func ChangePassword(repoPath, current, next string) error {
+	dbPath := path.Join(repoPath, "datastore", "mainnet.db")
+	tmpPath := dbPath + ".rekey"
+	if err := os.RemoveAll(tmpPath); err != nil {
+		return err
+	}
+
+	// ensure we can read the current datastore
+	src, err := open(dbPath, current)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+	if _, err := src.Config().GetCreationDate(); err != nil {
+		return ErrInvalidPassword
+	}
+	if err := src.Migrate(); err != nil {
+		return err
+	}
+
+	// create an empty target with the same schema
+	dst, err := open(tmpPath, next)
+	if err != nil {
+		return err
+	}
+	if err := dst.InitTables(""); err != nil {
+		dst.Close()
+		os.Remove(tmpPath)
+		return err
+	}
+	dst.Close()
+
+	// copy rows over and swap
+	if err := src.Copy(tmpPath, next); err != nil {
+		os.Remove(tmpPath)
+		return err
+	}
+	src.Close()
+	return os.Rename(tmpPath, dbPath)
+}
+
 func (d *SQLiteDatastore) InitTables(password string) error {
 	return initDatabaseTables(d.db, password)
 }
@@ -143,25 +262,43 @@ func (d *SQLiteDatastore) InitTables(password string) error {
 func initDatabaseTables(db *sql.DB, password string) error {
 	var sqlStmt string
 	if password != "" {
-		sqlStmt = "PRAGMA key = '" + password + "';"
+		sqlStmt = "PRAGMA key = '" + quote(password) + "';"
 	}
 	sqlStmt += `
 	create table config (key text primary key not null, value blob);
     create table profile (key text primary key not null, value blob);
-    create table threads (id text primary key not null, name text not null, sk blob not null, head text not null);
-    create table devices (id text primary key not null, name text not null);
+    create table threads (id text primary key not null, name text not null, sk blob not null, head text not null, description text not null default '', coverId text not null default '', metaId text not null default '');
+    create table devices (id text primary key not null, name text not null, attestation blob);
     create table peers (row text primary key not null, id text not null, pk blob not null, threadId text not null);
     create unique index peer_threadId_id on peers (threadId, id);
+    create table contacts (id text primary key not null, pk blob not null, username text not null, avatarId text not null, added integer not null, lastSeen integer not null);
+    create index contact_username on contacts (username);
+    create table profilecache (id text primary key not null, profile blob not null, cached integer not null);
+    create table searchattrs (blockId text primary key not null, threadId text not null, dataId text not null, authorId text not null, date integer not null, taken integer not null, latitude real not null, longitude real not null);
+    create index searchattrs_threadId on searchattrs (threadId);
+    create virtual table searchtext using fts4(blockId, caption, name, notindexed=blockId);
     create table blocks (id text primary key not null, date integer not null, parents text not null, threadId text not null, authorPk text not null, type integer not null, dataId text, dataKeyCipher blob, dataCaptionCipher blob);
     create index block_dataId on blocks (dataId);
     create index block_threadId_type_date on blocks (threadId, type, date);
+    create table missingblocks (id text primary key not null, threadId text not null, attempts integer not null, added integer not null, tried integer not null);
+    create index missingblock_threadId_tried on missingblocks (threadId, tried);
+    create table photohashes (hash text primary key not null, dataId text not null, key blob not null, added integer not null);
+    create table photoimports (path text not null, threadId text not null, dataId text not null, blockId text not null, added integer not null, primary key (threadId, path));
+    create table albumphotos (albumId text not null, dataId text not null, threadId text not null, primary key (albumId, dataId));
+    create index albumphoto_threadId on albumphotos (threadId);
     create table offlinemessages (url text primary key not null, date integer, message blob);
 	create table pointers (id text primary key not null, key text, address text, cancelId text, purpose integer, date integer);
     create table pinrequests (id text primary key not null, date integer);
 	`
+	sqlStmt += fmt.Sprintf("pragma user_version = %d;", len(migrations))
 	_, err := db.Exec(sqlStmt)
 	if err != nil {
 		return err
 	}
 	return nil
 }
+
+// quote escapes single quotes for use in a sql string literal
+func quote(value string) string {
+	return strings.Replace(value, "'", "''", -1)
+}
@@ -0,0 +1,100 @@
+package db
+
+import (
+	"github.com/textileio/textile-go/repo"
+	"io/ioutil"
+	"os"
+	"path"
+	"testing"
+	"time"
+)
+
+func TestChangePassword(t *testing.T) {
+	repoPath, err := ioutil.TempDir("", "textile_db")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(repoPath)
+	os.MkdirAll(path.Join(repoPath, "datastore"), os.ModePerm)
+	store, err := Create(repoPath, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	store.config.Init("")
+	created := time.Now()
+	store.config.Configure(created)
+	if err := store.search.Index(&repo.SearchEntry{
+		BlockId:  "b1",
+		ThreadId: "t1",
+		DataId:   "d1",
+		AuthorId: "a1",
+		Caption:  "Sunset at the beach",
+		Date:     created,
+	}); err != nil {
+		t.Fatal(err)
+	}
+	store.Close()
+
+	if err := ChangePassword(repoPath, "", "LetMeIn"); err != nil {
+		t.Fatal(err)
+	}
+	if err := ChangePassword(repoPath, "", "Nope"); err != ErrInvalidPassword {
+		t.Error("change password with wrong current password should fail")
+	}
+
+	store, err = Create(repoPath, "LetMeIn")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer store.Close()
+	if store.config.IsEncrypted() {
+		t.Error("re-keyed datastore is not readable with new password")
+	}
+	date, err := store.config.GetCreationDate()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if date.Unix() != created.Unix() {
+		t.Error("re-keyed datastore lost rows")
+	}
+	if len(store.search.Search("sunset", nil)) != 1 {
+		t.Error("re-keyed datastore lost search rows")
+	}
+}
+
+func TestChangePasswordQuoted(t *testing.T) {
+	repoPath, err := ioutil.TempDir("", "textile_db")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(repoPath)
+	os.MkdirAll(path.Join(repoPath, "datastore"), os.ModePerm)
+	store, err := Create(repoPath, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	store.config.Init("")
+	created := time.Now()
+	store.config.Configure(created)
+	store.Close()
+
+	password := `say "cheese" isn't it`
+	if err := ChangePassword(repoPath, "", password); err != nil {
+		t.Fatal(err)
+	}
+	store, err = Create(repoPath, password)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer store.Close()
+	date, err := store.config.GetCreationDate()
+	if err != nil {
+		t.Fatalf("datastore is not readable with a quoted password: %s", err)
+	}
+	if date.Unix() != created.Unix() {
+		t.Error("re-keyed datastore lost rows")
+	}
+	if err := ChangePassword(repoPath, `say "cheese"`, "next"); err != ErrInvalidPassword {
+		t.Error("change password with a partial quoted password should fail")
+	}
+}
@@ -10,6 +10,8 @@ type DeviceDB struct {
 	modelStore
 }
 
+const deviceColumns = "id, name, attestation"
+
 func NewDeviceStore(db *sql.DB, lock *sync.Mutex) repo.DeviceStore {
 	return &DeviceDB{modelStore{db, lock}}
 }
@@ -21,7 +23,7 @@ func (c *DeviceDB) Add(device *repo.Device) error {
 	if err != nil {
 		return err
 	}
-	stm := `insert into devices(id, name) values(?,?)`
+	stm := `insert into devices(id, name, attestation) values(?,?,?)`
 	stmt, err := tx.Prepare(stm)
 	if err != nil {
 		log.Errorf("error in tx prepare: %s", err)
@@ -31,6 +33,7 @@ func (c *DeviceDB) Add(device *repo.Device) error {
 	_, err = stmt.Exec(
 		device.Id,
 		device.Name,
+		device.Attestation,
 	)
 	if err != nil {
 		tx.Rollback()
@@ -43,7 +46,7 @@ func (c *DeviceDB) Add(device *repo.Device) error {
 func (c *DeviceDB) Get(id string) *repo.Device {
 	c.lock.Lock()
 	defer c.lock.Unlock()
-	ret := c.handleQuery("select * from devices where id='" + id + "';")
+	ret := c.handleQuery("select " + deviceColumns + " from devices where id='" + id + "';")
 	if len(ret) == 0 {
 		return nil
 	}
@@ -57,7 +60,7 @@ func (c *DeviceDB) List(query string) []repo.Device {
 	if query != "" {
 		q = " where " + query
 	}
-	return c.handleQuery("select * from devices" + q + ";")
+	return c.handleQuery("select " + deviceColumns + " from devices" + q + ";")
 }
 
 func (c *DeviceDB) Delete(id string) error {
@@ -76,13 +79,15 @@ func (c *DeviceDB) handleQuery(stm string) []repo.Device {
 	}
 	for rows.Next() {
 		var id, name string
-		if err := rows.Scan(&id, &name); err != nil {
+		var attestation []byte
+		if err := rows.Scan(&id, &name, &attestation); err != nil {
 			log.Errorf("error in db scan: %s", err)
 			continue
 		}
 		device := repo.Device{
-			Id:   id,
-			Name: name,
+			Id:          id,
+			Name:        name,
+			Attestation: attestation,
 		}
 		ret = append(ret, device)
 	}
@@ -0,0 +1,76 @@
+package db
+
+import (
+	"bytes"
+	"database/sql"
+	"github.com/textileio/textile-go/repo"
+	"sync"
+	"testing"
+)
+
+var devdb repo.DeviceStore
+
+func init() {
+	setupDeviceDB()
+}
+
+func setupDeviceDB() {
+	conn, _ := sql.Open("sqlite3", ":memory:")
+	initDatabaseTables(conn, "")
+	devdb = NewDeviceStore(conn, new(sync.Mutex))
+}
+
+func TestDeviceDB_Add(t *testing.T) {
+	err := devdb.Add(&repo.Device{
+		Id:          "abcde",
+		Name:        "boom",
+		Attestation: []byte("signed"),
+	})
+	if err != nil {
+		t.Error(err)
+	}
+	stmt, err := devdb.PrepareQuery("select id from devices where id=?")
+	defer stmt.Close()
+	var id string
+	err = stmt.QueryRow("abcde").Scan(&id)
+	if err != nil {
+		t.Error(err)
+	}
+	if id != "abcde" {
+		t.Errorf(`expected "abcde" got %s`, id)
+	}
+}
+
+func TestDeviceDB_Get(t *testing.T) {
+	dev := devdb.Get("abcde")
+	if dev == nil {
+		t.Error("could not get device")
+		return
+	}
+	if !bytes.Equal(dev.Attestation, []byte("signed")) {
+		t.Error("incorrect attestation")
+	}
+}
+
+func TestDeviceDB_List(t *testing.T) {
+	setupDeviceDB()
+	if err := devdb.Add(&repo.Device{Id: "abc", Name: "one"}); err != nil {
+		t.Error(err)
+	}
+	if err := devdb.Add(&repo.Device{Id: "def", Name: "two"}); err != nil {
+		t.Error(err)
+	}
+	list := devdb.List("")
+	if len(list) != 2 {
+		t.Error("returned incorrect number of devices")
+	}
+}
+
+func TestDeviceDB_Delete(t *testing.T) {
+	if err := devdb.Delete("abc"); err != nil {
+		t.Error(err)
+	}
+	if devdb.Get("abc") != nil {
+		t.Error("delete failed")
+	}
+}
@@ -0,0 +1,173 @@
+package db
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/textileio/textile-go/repo"
+)
+
+// migrations bring an existing datastore up to the current schema, in order.
+// The datastore's user_version pragma holds how many have been applied.
+// New repos are created with the current schema and skip them all.
+// Every migration is idempotent, so renumbering them is safe.
+var migrations = []func(tx *sql.Tx) error{
+	migrateDeviceAttestations,
+	migrateContacts,
+	migrateProfileCache,
+	migrateSearch,
+	migrateMissingBlocks,
+	migrateThreadMetadata,
+	migrateAlbumPhotos,
+	migratePhotoHashes,
+	migratePhotoImports,
+}
+
+// Migrate applies any migrations the datastore hasn't seen yet
+func (d *SQLiteDatastore) Migrate() error {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	return migrate(d.db)
+}
+
+func migrate(db *sql.DB) error {
+	var version int
+	if err := db.QueryRow("pragma user_version;").Scan(&version); err != nil {
+		return err
+	}
+	for i := version; i < len(migrations); i++ {
+		tx, err := db.Begin()
+		if err != nil {
+			return err
+		}
+		if err := migrations[i](tx); err != nil {
+			tx.Rollback()
+			return errors.New(fmt.Sprintf("migration %d failed: %s", i+1, err))
+		}
+		if _, err := tx.Exec(fmt.Sprintf("pragma user_version = %d;", i+1)); err != nil {
+			tx.Rollback()
+			return err
+		}
+		if err := tx.Commit(); err != nil {
+			return err
+		}
+		log.Infof("migrated datastore to version %d", i+1)
+	}
+	return nil
+}
+
+// addColumn adds a column to a table unless it's already there
+func addColumn(tx *sql.Tx, table string, column string, def string) error {
+	rows, err := tx.Query(fmt.Sprintf("pragma table_info(%s);", table))
+	if err != nil {
+		return err
+	}
+	var found bool
+	for rows.Next() {
+		var cid, notnull, pk int
+		var name, ctype string
+		var dflt sql.NullString
+		if err := rows.Scan(&cid, &name, &ctype, &notnull, &dflt, &pk); err != nil {
+			rows.Close()
+			return err
+		}
+		if name == column {
+			found = true
+		}
+	}
+	rows.Close()
+	if found {
+		return nil
+	}
+	_, err = tx.Exec(fmt.Sprintf("alter table %s add column %s %s;", table, column, def))
+	return err
+}
+
+// migrateDeviceAttestations adds the account signature over each paired device
+func migrateDeviceAttestations(tx *sql.Tx) error {
+	return addColumn(tx, "devices", "attestation", "blob")
+}
+
+// migrateContacts adds the contacts table
+func migrateContacts(tx *sql.Tx) error {
+	_, err := tx.Exec(`
+    create table if not exists contacts (id text primary key not null, pk blob not null, username text not null, avatarId text not null, added integer not null, lastSeen integer not null);
+    create index if not exists contact_username on contacts (username);
+	`)
+	return err
+}
+
+// migrateProfileCache adds the peer profile cache table
+func migrateProfileCache(tx *sql.Tx) error {
+	_, err := tx.Exec(`
+    create table if not exists profilecache (id text primary key not null, profile blob not null, cached integer not null);
+	`)
+	return err
+}
+
+// migrateSearch adds the search attribute and full text tables, existing photos are backfilled on start
+func migrateSearch(tx *sql.Tx) error {
+	_, err := tx.Exec(`
+    create table if not exists searchattrs (blockId text primary key not null, threadId text not null, dataId text not null, authorId text not null, date integer not null, taken integer not null, latitude real not null, longitude real not null);
+    create index if not exists searchattrs_threadId on searchattrs (threadId);
+    create virtual table if not exists searchtext using fts4(blockId, caption, name, notindexed=blockId);
+	`)
+	return err
+}
+
+// migrateMissingBlocks adds the table of blocks a thread walk could not reach
+func migrateMissingBlocks(tx *sql.Tx) error {
+	_, err := tx.Exec(`
+    create table if not exists missingblocks (id text primary key not null, threadId text not null, attempts integer not null, added integer not null, tried integer not null);
+    create index if not exists missingblock_threadId_tried on missingblocks (threadId, tried);
+	`)
+	return err
+}
+
+// migrateThreadMetadata adds thread description, cover and latest metadata block columns
+func migrateThreadMetadata(tx *sql.Tx) error {
+	for _, column := range []string{"description", "coverId", "metaId"} {
+		if err := addColumn(tx, "threads", column, "text not null default ''"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// migrateAlbumPhotos adds the album membership index and fills it from membership blocks,
+// taking the newest add or remove for each photo. Same-second ties go to the higher id.
+func migrateAlbumPhotos(tx *sql.Tx) error {
+	_, err := tx.Exec(`
+    create table if not exists albumphotos (albumId text not null, dataId text not null, threadId text not null, primary key (albumId, dataId));
+    create index if not exists albumphoto_threadId on albumphotos (threadId);
+	`)
+	if err != nil {
+		return err
+	}
+
+	// membership data ids are album-<albumId>-<dataId>
+	_, err = tx.Exec(`
+    insert or replace into albumphotos(albumId, dataId, threadId)
+    select substr(m.dataId, 7, instr(substr(m.dataId, 7), '-') - 1), substr(m.dataId, 7 + instr(substr(m.dataId, 7), '-')), m.threadId
+    from blocks m where m.type=? and not exists (
+        select 1 from blocks n where n.threadId=m.threadId and n.dataId=m.dataId and n.type in (?,?)
+        and (n.date>m.date or (n.date=m.date and n.id>m.id)));
+	`, int(repo.AlbumAddBlock), int(repo.AlbumAddBlock), int(repo.AlbumRemoveBlock))
+	return err
+}
+
+// migratePhotoHashes adds the table of added photo content hashes
+func migratePhotoHashes(tx *sql.Tx) error {
+	_, err := tx.Exec(`
+    create table if not exists photohashes (hash text primary key not null, dataId text not null, key blob not null, added integer not null);
+	`)
+	return err
+}
+
+// migratePhotoImports adds the table of imported photo paths
+func migratePhotoImports(tx *sql.Tx) error {
+	_, err := tx.Exec(`
+    create table if not exists photoimports (path text not null, threadId text not null, dataId text not null, blockId text not null, added integer not null, primary key (threadId, path));
+	`)
+	return err
+}
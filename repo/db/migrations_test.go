@@ -0,0 +1,92 @@
+package db
+
+import (
+	"database/sql"
+	"github.com/textileio/textile-go/repo"
+	"sync"
+	"testing"
+	"time"
+)
+
+// baselineSchema is the schema before any migrations
+const baselineSchema = `
+    create table threads (id text primary key not null, name text not null, sk blob not null, head text not null);
+    create table devices (id text primary key not null, name text not null);
+    create table blocks (id text primary key not null, date integer not null, parents text not null, threadId text not null, authorPk text not null, type integer not null, dataId text, dataKeyCipher blob, dataCaptionCipher blob);
+`
+
+func TestMigrate(t *testing.T) {
+	conn, _ := sql.Open("sqlite3", ":memory:")
+	conn.SetMaxOpenConns(1)
+	if _, err := conn.Exec(baselineSchema); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := conn.Exec("insert into threads(id, name, sk, head) values('t1', 'old', x'00', '');"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := conn.Exec("insert into devices(id, name) values('d1', 'laptop');"); err != nil {
+		t.Fatal(err)
+	}
+	members := []repo.Block{
+		{Id: "m1", Date: time.Unix(1, 0), Type: repo.AlbumAddBlock, DataId: "album-Qmalbum-Qmkept"},
+		{Id: "m2", Date: time.Unix(1, 0), Type: repo.AlbumAddBlock, DataId: "album-Qmalbum-Qmgone"},
+		{Id: "m3", Date: time.Unix(2, 0), Type: repo.AlbumRemoveBlock, DataId: "album-Qmalbum-Qmgone"},
+	}
+	for _, m := range members {
+		if _, err := conn.Exec("insert into blocks(id, date, parents, threadId, authorPk, type, dataId) values(?,?,'','t1','',?,?);",
+			m.Id, m.Date.Unix(), int(m.Type), m.DataId); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := migrate(conn); err != nil {
+		t.Fatalf("migrate failed: %s", err)
+	}
+	if err := migrate(conn); err != nil {
+		t.Fatalf("migrate is not idempotent: %s", err)
+	}
+	lock := new(sync.Mutex)
+	thrd := NewThreadStore(conn, lock).Get("t1")
+	if thrd == nil || thrd.Name != "old" {
+		t.Error("thread lost after migration")
+	}
+	if NewDeviceStore(conn, lock).Get("d1") == nil {
+		t.Error("device lost after migration")
+	}
+	if len(NewContactStore(conn, lock).List("")) != 0 {
+		t.Error("contacts table not usable after migration")
+	}
+	var count int
+	for _, table := range []string{"contacts", "profilecache", "searchattrs", "searchtext", "missingblocks", "albumphotos", "photohashes", "photoimports"} {
+		if err := conn.QueryRow("select count(*) from " + table + ";").Scan(&count); err != nil {
+			t.Errorf("table %s not usable after migration: %s", table, err)
+		}
+	}
+	if err := conn.QueryRow("select count(*) from albumphotos;").Scan(&count); err != nil || count != 1 {
+		t.Fatalf("expected one album photo after migration, got %d", count)
+	}
+	var albumId, dataId string
+	if err := conn.QueryRow("select albumId, dataId from albumphotos;").Scan(&albumId, &dataId); err != nil {
+		t.Fatal(err)
+	}
+	if albumId != "Qmalbum" || dataId != "Qmkept" {
+		t.Errorf("bad album photo after migration: %s %s", albumId, dataId)
+	}
+}
+
+func TestMigrate_NewDatastore(t *testing.T) {
+	conn, _ := sql.Open("sqlite3", ":memory:")
+	conn.SetMaxOpenConns(1)
+	if err := initDatabaseTables(conn, ""); err != nil {
+		t.Fatal(err)
+	}
+	var version int
+	if err := conn.QueryRow("pragma user_version;").Scan(&version); err != nil {
+		t.Fatal(err)
+	}
+	if version != len(migrations) {
+		t.Errorf("new datastore at version %d, expected %d", version, len(migrations))
+	}
+	if err := migrate(conn); err != nil {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,99 @@
+package db
+
+import (
+	"database/sql"
+	"github.com/textileio/textile-go/repo"
+	"strconv"
+	"sync"
+	"time"
+)
+
+type MissingBlockDB struct {
+	modelStore
+}
+
+const missingBlockColumns = "id, threadId, attempts, added, tried"
+
+func NewMissingBlockStore(db *sql.DB, lock *sync.Mutex) repo.MissingBlockStore {
+	return &MissingBlockDB{modelStore{db, lock}}
+}
+
+// Put queues a block, or counts another failed attempt if it's already queued
+func (c *MissingBlockDB) Put(mb *repo.MissingBlock) error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	tx, err := c.db.Begin()
+	if err != nil {
+		return err
+	}
+	res, err := tx.Exec("update missingblocks set attempts=attempts+1, tried=? where id=?", int(mb.Tried.Unix()), mb.Id)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	if updated, _ := res.RowsAffected(); updated > 0 {
+		tx.Commit()
+		return nil
+	}
+	stm := `insert into missingblocks(id, threadId, attempts, added, tried) values(?,?,?,?,?)`
+	if _, err := tx.Exec(stm,
+		mb.Id,
+		mb.ThreadId,
+		mb.Attempts,
+		int(mb.Added.Unix()),
+		int(mb.Tried.Unix()),
+	); err != nil {
+		tx.Rollback()
+		return err
+	}
+	tx.Commit()
+	return nil
+}
+
+// List returns a thread's queued blocks last tried before the given time, least recently tried first
+func (c *MissingBlockDB) List(threadId string, before time.Time, limit int) []repo.MissingBlock {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	stm := "select " + missingBlockColumns + " from missingblocks where threadId=? and tried<? order by tried asc limit " + strconv.Itoa(limit) + ";"
+	return c.handleQuery(stm, threadId, int(before.Unix()))
+}
+
+func (c *MissingBlockDB) Delete(id string) error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	_, err := c.db.Exec("delete from missingblocks where id=?", id)
+	return err
+}
+
+func (c *MissingBlockDB) DeleteByThreadId(threadId string) error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	_, err := c.db.Exec("delete from missingblocks where threadId=?", threadId)
+	return err
+}
+
+func (c *MissingBlockDB) handleQuery(stm string, args ...interface{}) []repo.MissingBlock {
+	var ret []repo.MissingBlock
+	rows, err := c.db.Query(stm, args...)
+	if err != nil {
+		log.Errorf("error in db query: %s", err)
+		return nil
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var id, threadId string
+		var attempts, addedInt, triedInt int
+		if err := rows.Scan(&id, &threadId, &attempts, &addedInt, &triedInt); err != nil {
+			log.Errorf("error in db scan: %s", err)
+			continue
+		}
+		ret = append(ret, repo.MissingBlock{
+			Id:       id,
+			ThreadId: threadId,
+			Attempts: attempts,
+			Added:    time.Unix(int64(addedInt), 0),
+			Tried:    time.Unix(int64(triedInt), 0),
+		})
+	}
+	return ret
+}
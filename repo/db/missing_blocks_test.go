@@ -0,0 +1,73 @@
+package db
+
+import (
+	"database/sql"
+	"github.com/textileio/textile-go/repo"
+	"sync"
+	"testing"
+	"time"
+)
+
+var mbdb repo.MissingBlockStore
+
+func init() {
+	setupMissingBlockDB()
+}
+
+func setupMissingBlockDB() {
+	conn, _ := sql.Open("sqlite3", ":memory:")
+	initDatabaseTables(conn, "")
+	mbdb = NewMissingBlockStore(conn, new(sync.Mutex))
+}
+
+func TestMissingBlockDB_Put(t *testing.T) {
+	tried := time.Now().Add(-time.Minute)
+	mb := &repo.MissingBlock{Id: "abc", ThreadId: "thread", Attempts: 1, Added: tried, Tried: tried}
+	if err := mbdb.Put(mb); err != nil {
+		t.Error(err)
+	}
+	if err := mbdb.Put(mb); err != nil {
+		t.Error(err)
+	}
+	list := mbdb.List("thread", time.Now(), 10)
+	if len(list) != 1 {
+		t.Errorf("expected one missing block, got %d", len(list))
+		return
+	}
+	if list[0].Attempts != 2 {
+		t.Error("put did not count another attempt")
+	}
+}
+
+func TestMissingBlockDB_List(t *testing.T) {
+	setupMissingBlockDB()
+	now := time.Now()
+	if err := mbdb.Put(&repo.MissingBlock{Id: "old", ThreadId: "thread", Attempts: 1, Added: now, Tried: now.Add(-time.Hour)}); err != nil {
+		t.Error(err)
+	}
+	if err := mbdb.Put(&repo.MissingBlock{Id: "new", ThreadId: "thread", Attempts: 1, Added: now, Tried: now}); err != nil {
+		t.Error(err)
+	}
+	if err := mbdb.Put(&repo.MissingBlock{Id: "other", ThreadId: "other", Attempts: 1, Added: now, Tried: now.Add(-time.Hour)}); err != nil {
+		t.Error(err)
+	}
+	due := mbdb.List("thread", now.Add(-time.Minute), 10)
+	if len(due) != 1 || due[0].Id != "old" {
+		t.Error("list returned wrong missing blocks")
+	}
+}
+
+func TestMissingBlockDB_Delete(t *testing.T) {
+	if err := mbdb.Delete("old"); err != nil {
+		t.Error(err)
+	}
+	if len(mbdb.List("thread", time.Now().Add(time.Minute), 10)) != 1 {
+		t.Error("delete did not remove missing block")
+	}
+	if err := mbdb.DeleteByThreadId("thread"); err != nil {
+		t.Error(err)
+	}
+	if len(mbdb.List("thread", time.Now().Add(time.Minute), 10)) != 0 {
+		t.Error("delete by thread id did not remove missing blocks")
+	}
+}
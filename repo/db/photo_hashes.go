@@ -0,0 +1,89 @@
+package db
+
+import (
+	"database/sql"
+	"github.com/textileio/textile-go/repo"
+	"sync"
+	"time"
+)
+
+type PhotoHashDB struct {
+	modelStore
+}
+
+const photoHashColumns = "hash, dataId, key, added"
+
+func NewPhotoHashStore(db *sql.DB, lock *sync.Mutex) repo.PhotoHashStore {
+	return &PhotoHashDB{modelStore{db, lock}}
+}
+
+func (c *PhotoHashDB) Add(ph *repo.PhotoHash) error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	tx, err := c.db.Begin()
+	if err != nil {
+		return err
+	}
+	stm := `insert into photohashes(hash, dataId, key, added) values(?,?,?,?)`
+	stmt, err := tx.Prepare(stm)
+	if err != nil {
+		log.Errorf("error in tx prepare: %s", err)
+		return err
+	}
+	defer stmt.Close()
+	_, err = stmt.Exec(
+		ph.Hash,
+		ph.DataId,
+		ph.KeyCipher,
+		int(ph.Added.Unix()),
+	)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	tx.Commit()
+	return nil
+}
+
+func (c *PhotoHashDB) Get(hash string) *repo.PhotoHash {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	ret := c.handleQuery("select "+photoHashColumns+" from photohashes where hash=?;", hash)
+	if len(ret) == 0 {
+		return nil
+	}
+	return &ret[0]
+}
+
+func (c *PhotoHashDB) Delete(hash string) error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	_, err := c.db.Exec("delete from photohashes where hash=?", hash)
+	return err
+}
+
+func (c *PhotoHashDB) handleQuery(stm string, args ...interface{}) []repo.PhotoHash {
+	var ret []repo.PhotoHash
+	rows, err := c.db.Query(stm, args...)
+	if err != nil {
+		log.Errorf("error in db query: %s", err)
+		return nil
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var hash, dataId string
+		var keyCipher []byte
+		var addedInt int
+		if err := rows.Scan(&hash, &dataId, &keyCipher, &addedInt); err != nil {
+			log.Errorf("error in db scan: %s", err)
+			continue
+		}
+		ret = append(ret, repo.PhotoHash{
+			Hash:      hash,
+			DataId:    dataId,
+			KeyCipher: keyCipher,
+			Added:     time.Unix(int64(addedInt), 0),
+		})
+	}
+	return ret
+}
@@ -0,0 +1,56 @@
+package db
+
+import (
+	"database/sql"
+	"github.com/textileio/textile-go/repo"
+	"sync"
+	"testing"
+	"time"
+)
+
+var phdb repo.PhotoHashStore
+
+func init() {
+	setupPhotoHashDB()
+}
+
+func setupPhotoHashDB() {
+	conn, _ := sql.Open("sqlite3", ":memory:")
+	initDatabaseTables(conn, "")
+	phdb = NewPhotoHashStore(conn, new(sync.Mutex))
+}
+
+func TestPhotoHashDB_Add(t *testing.T) {
+	err := phdb.Add(&repo.PhotoHash{
+		Hash:      "abc",
+		DataId:    "Qm123",
+		KeyCipher: []byte("key"),
+		Added:     time.Now(),
+	})
+	if err != nil {
+		t.Error(err)
+	}
+	if err := phdb.Add(&repo.PhotoHash{Hash: "abc", DataId: "Qm456", KeyCipher: []byte("key"), Added: time.Now()}); err == nil {
+		t.Error("added a duplicate hash")
+	}
+}
+
+func TestPhotoHashDB_Get(t *testing.T) {
+	ph := phdb.Get("abc")
+	if ph == nil {
+		t.Error("could not get photo hash")
+		return
+	}
+	if ph.DataId != "Qm123" || string(ph.KeyCipher) != "key" {
+		t.Error("photo hash fields don't match")
+	}
+}
+
+func TestPhotoHashDB_Delete(t *testing.T) {
+	if err := phdb.Delete("abc"); err != nil {
+		t.Error(err)
+	}
+	if phdb.Get("abc") != nil {
+		t.Error("delete photo hash failed")
+	}
+}
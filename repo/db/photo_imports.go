@@ -0,0 +1,90 @@
+package db
+
+import (
+	"database/sql"
+	"github.com/textileio/textile-go/repo"
+	"sync"
+	"time"
+)
+
+type PhotoImportDB struct {
+	modelStore
+}
+
+const photoImportColumns = "path, threadId, dataId, blockId, added"
+
+func NewPhotoImportStore(db *sql.DB, lock *sync.Mutex) repo.PhotoImportStore {
+	return &PhotoImportDB{modelStore{db, lock}}
+}
+
+func (c *PhotoImportDB) Add(pi *repo.PhotoImport) error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	tx, err := c.db.Begin()
+	if err != nil {
+		return err
+	}
+	stm := `insert into photoimports(path, threadId, dataId, blockId, added) values(?,?,?,?,?)`
+	stmt, err := tx.Prepare(stm)
+	if err != nil {
+		log.Errorf("error in tx prepare: %s", err)
+		return err
+	}
+	defer stmt.Close()
+	_, err = stmt.Exec(
+		pi.Path,
+		pi.ThreadId,
+		pi.DataId,
+		pi.BlockId,
+		int(pi.Added.Unix()),
+	)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	tx.Commit()
+	return nil
+}
+
+func (c *PhotoImportDB) Get(threadId string, path string) *repo.PhotoImport {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	ret := c.handleQuery("select "+photoImportColumns+" from photoimports where threadId=? and path=?;", threadId, path)
+	if len(ret) == 0 {
+		return nil
+	}
+	return &ret[0]
+}
+
+func (c *PhotoImportDB) DeleteByThreadId(threadId string) error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	_, err := c.db.Exec("delete from photoimports where threadId=?", threadId)
+	return err
+}
+
+func (c *PhotoImportDB) handleQuery(stm string, args ...interface{}) []repo.PhotoImport {
+	var ret []repo.PhotoImport
+	rows, err := c.db.Query(stm, args...)
+	if err != nil {
+		log.Errorf("error in db query: %s", err)
+		return nil
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var path, threadId, dataId, blockId string
+		var addedInt int
+		if err := rows.Scan(&path, &threadId, &dataId, &blockId, &addedInt); err != nil {
+			log.Errorf("error in db scan: %s", err)
+			continue
+		}
+		ret = append(ret, repo.PhotoImport{
+			Path:     path,
+			ThreadId: threadId,
+			DataId:   dataId,
+			BlockId:  blockId,
+			Added:    time.Unix(int64(addedInt), 0),
+		})
+	}
+	return ret
+}
@@ -0,0 +1,57 @@
+package db
+
+import (
+	"database/sql"
+	"github.com/textileio/textile-go/repo"
+	"sync"
+	"testing"
+	"time"
+)
+
+var pidb repo.PhotoImportStore
+
+func init() {
+	setupPhotoImportDB()
+}
+
+func setupPhotoImportDB() {
+	conn, _ := sql.Open("sqlite3", ":memory:")
+	initDatabaseTables(conn, "")
+	pidb = NewPhotoImportStore(conn, new(sync.Mutex))
+}
+
+func TestPhotoImportDB_Add(t *testing.T) {
+	err := pidb.Add(&repo.PhotoImport{
+		Path:     "/photos/a.jpg",
+		ThreadId: "thread",
+		DataId:   "Qm123",
+		BlockId:  "Qm456",
+		Added:    time.Now(),
+	})
+	if err != nil {
+		t.Error(err)
+	}
+}
+
+func TestPhotoImportDB_Get(t *testing.T) {
+	pi := pidb.Get("thread", "/photos/a.jpg")
+	if pi == nil {
+		t.Error("could not get photo import")
+		return
+	}
+	if pi.DataId != "Qm123" || pi.BlockId != "Qm456" {
+		t.Error("photo import fields don't match")
+	}
+	if pidb.Get("other", "/photos/a.jpg") != nil {
+		t.Error("got photo import from another thread")
+	}
+}
+
+func TestPhotoImportDB_DeleteByThreadId(t *testing.T) {
+	if err := pidb.DeleteByThreadId("thread"); err != nil {
+		t.Error(err)
+	}
+	if pidb.Get("thread", "/photos/a.jpg") != nil {
+		t.Error("delete photo imports failed")
+	}
+}
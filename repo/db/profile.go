@@ -3,6 +3,7 @@ package db
 import (
 	"database/sql"
 	"github.com/textileio/textile-go/repo"
+	"strings"
 	"sync"
 )
 
@@ -117,6 +118,23 @@ func (c *ProfileDB) GetAvatarId() (string, error) {
 	return avatarId, nil
 }
 
+func (c *ProfileDB) SetAvatar(id string) error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	_, err := c.db.Exec("insert or replace into profile(key, value) values(?,?)", "avatar", id)
+	return err
+}
+
+func (c *ProfileDB) GetAvatar() (string, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	var avatar string
+	if err := c.db.QueryRow("select value from profile where key=?", "avatar").Scan(&avatar); err != nil {
+		return "", err
+	}
+	return avatar, nil
+}
+
 func (c *ProfileDB) GetTokens() (tokens *repo.CafeTokens, err error) {
 	c.lock.Lock()
 	defer c.lock.Unlock()
@@ -143,3 +161,36 @@ func (c *ProfileDB) GetTokens() (tokens *repo.CafeTokens, err error) {
 	tokens = &repo.CafeTokens{Access: accessToken, Refresh: refreshToken}
 	return
 }
+
+// profile fields are namespaced so they can't collide with account keys
+const fieldPrefix = "field_"
+
+func (c *ProfileDB) SetField(key string, value string) error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if value == "" {
+		_, err := c.db.Exec("delete from profile where key=?", fieldPrefix+key)
+		return err
+	}
+	_, err := c.db.Exec("insert or replace into profile(key, value) values(?,?)", fieldPrefix+key, value)
+	return err
+}
+
+func (c *ProfileDB) GetFields() (map[string]string, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	rows, err := c.db.Query("select key, value from profile where key like ?", fieldPrefix+"%")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	fields := make(map[string]string)
+	for rows.Next() {
+		var key, value string
+		if err := rows.Scan(&key, &value); err != nil {
+			return nil, err
+		}
+		fields[strings.TrimPrefix(key, fieldPrefix)] = value
+	}
+	return fields, nil
+}
@@ -0,0 +1,96 @@
+package db
+
+import (
+	"database/sql"
+	"github.com/textileio/textile-go/repo"
+	"sync"
+	"time"
+)
+
+type ProfileCacheDB struct {
+	modelStore
+}
+
+const profileCacheColumns = "id, profile, cached"
+
+func NewProfileCacheStore(db *sql.DB, lock *sync.Mutex) repo.ProfileCacheStore {
+	return &ProfileCacheDB{modelStore{db, lock}}
+}
+
+func (c *ProfileCacheDB) Put(profile *repo.CachedProfile) error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	tx, err := c.db.Begin()
+	if err != nil {
+		return err
+	}
+	stm := `insert or replace into profilecache(id, profile, cached) values(?,?,?)`
+	stmt, err := tx.Prepare(stm)
+	if err != nil {
+		log.Errorf("error in tx prepare: %s", err)
+		return err
+	}
+	defer stmt.Close()
+	_, err = stmt.Exec(
+		profile.Id,
+		profile.Profile,
+		int(profile.Cached.Unix()),
+	)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	tx.Commit()
+	return nil
+}
+
+func (c *ProfileCacheDB) Get(id string) *repo.CachedProfile {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	ret := c.handleQuery("select " + profileCacheColumns + " from profilecache where id='" + id + "';")
+	if len(ret) == 0 {
+		return nil
+	}
+	return &ret[0]
+}
+
+func (c *ProfileCacheDB) List(query string) []repo.CachedProfile {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	q := ""
+	if query != "" {
+		q = " where " + query
+	}
+	return c.handleQuery("select " + profileCacheColumns + " from profilecache" + q + " order by cached asc;")
+}
+
+func (c *ProfileCacheDB) Delete(id string) error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	_, err := c.db.Exec("delete from profilecache where id=?", id)
+	return err
+}
+
+func (c *ProfileCacheDB) handleQuery(stm string) []repo.CachedProfile {
+	var ret []repo.CachedProfile
+	rows, err := c.db.Query(stm)
+	if err != nil {
+		log.Errorf("error in db query: %s", err)
+		return nil
+	}
+	for rows.Next() {
+		var id string
+		var profile []byte
+		var cachedInt int
+		if err := rows.Scan(&id, &profile, &cachedInt); err != nil {
+			log.Errorf("error in db scan: %s", err)
+			continue
+		}
+		ret = append(ret, repo.CachedProfile{
+			Id:      id,
+			Profile: profile,
+			Cached:  time.Unix(int64(cachedInt), 0),
+		})
+	}
+	return ret
+}
@@ -0,0 +1,63 @@
+package db
+
+import (
+	"database/sql"
+	"fmt"
+	"github.com/textileio/textile-go/repo"
+	"sync"
+	"testing"
+	"time"
+)
+
+var pcdb repo.ProfileCacheStore
+
+func init() {
+	setupProfileCacheDB()
+}
+
+func setupProfileCacheDB() {
+	conn, _ := sql.Open("sqlite3", ":memory:")
+	initDatabaseTables(conn, "")
+	pcdb = NewProfileCacheStore(conn, new(sync.Mutex))
+}
+
+func TestProfileCacheDB_Put(t *testing.T) {
+	if err := pcdb.Put(&repo.CachedProfile{Id: "abc", Profile: []byte("v1"), Cached: time.Now()}); err != nil {
+		t.Error(err)
+	}
+	if err := pcdb.Put(&repo.CachedProfile{Id: "abc", Profile: []byte("v2"), Cached: time.Now()}); err != nil {
+		t.Error(err)
+	}
+	cached := pcdb.Get("abc")
+	if cached == nil {
+		t.Error("could not get cached profile")
+		return
+	}
+	if string(cached.Profile) != "v2" {
+		t.Error("put did not replace cached profile")
+	}
+}
+
+func TestProfileCacheDB_List(t *testing.T) {
+	setupProfileCacheDB()
+	old := time.Now().Add(-time.Hour * 2)
+	if err := pcdb.Put(&repo.CachedProfile{Id: "old", Profile: []byte("v1"), Cached: old}); err != nil {
+		t.Error(err)
+	}
+	if err := pcdb.Put(&repo.CachedProfile{Id: "new", Profile: []byte("v1"), Cached: time.Now()}); err != nil {
+		t.Error(err)
+	}
+	stale := pcdb.List(fmt.Sprintf("cached<%d", time.Now().Add(-time.Hour).Unix()))
+	if len(stale) != 1 || stale[0].Id != "old" {
+		t.Error("returned incorrect stale profiles")
+	}
+}
+
+func TestProfileCacheDB_Delete(t *testing.T) {
+	if err := pcdb.Delete("old"); err != nil {
+		t.Error(err)
+	}
+	if pcdb.Get("old") != nil {
+		t.Error("delete failed")
+	}
+}
@@ -71,6 +71,48 @@ func TestProfileDB_GetTokens(t *testing.T) {
 	}
 }
 
+func TestProfileDB_SetAvatar(t *testing.T) {
+	if err := pdb.SetAvatar("Qm..."); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestProfileDB_GetAvatar(t *testing.T) {
+	av, err := pdb.GetAvatar()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if av != "Qm..." {
+		t.Error("got bad avatar")
+	}
+}
+
+func TestProfileDB_SetField(t *testing.T) {
+	if err := pdb.SetField("bio", "just a test"); err != nil {
+		t.Error(err)
+		return
+	}
+	if err := pdb.SetField("display_name", "Boom"); err != nil {
+		t.Error(err)
+		return
+	}
+	if err := pdb.SetField("display_name", ""); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestProfileDB_GetFields(t *testing.T) {
+	fields, err := pdb.GetFields()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if len(fields) != 1 || fields["bio"] != "just a test" {
+		t.Error("got bad fields")
+	}
+}
+
 func TestProfileDB_SignOut(t *testing.T) {
 	err := pdb.SignOut()
 	if err != nil {
@@ -0,0 +1,194 @@
+package db
+
+import (
+	"database/sql"
+	"github.com/textileio/textile-go/repo"
+	"strings"
+	"sync"
+	"time"
+)
+
+// defaultSearchLimit caps results when filters don't
+const defaultSearchLimit = 100
+
+type SearchDB struct {
+	modelStore
+}
+
+func NewSearchStore(db *sql.DB, lock *sync.Mutex) repo.SearchStore {
+	return &SearchDB{modelStore{db, lock}}
+}
+
+func (c *SearchDB) Index(entry *repo.SearchEntry) error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	tx, err := c.db.Begin()
+	if err != nil {
+		return err
+	}
+	var taken int
+	if !entry.Taken.IsZero() {
+		taken = int(entry.Taken.Unix())
+	}
+	stm := `insert or replace into searchattrs(blockId, threadId, dataId, authorId, date, taken, latitude, longitude) values(?,?,?,?,?,?,?,?)`
+	if _, err := tx.Exec(stm,
+		entry.BlockId,
+		entry.ThreadId,
+		entry.DataId,
+		entry.AuthorId,
+		int(entry.Date.Unix()),
+		taken,
+		entry.Latitude,
+		entry.Longitude,
+	); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	// fts tables have no unique constraints, so replace by hand
+	if _, err := tx.Exec("delete from searchtext where blockId=?", entry.BlockId); err != nil {
+		tx.Rollback()
+		return err
+	}
+	if _, err := tx.Exec("insert into searchtext(blockId, caption, name) values(?,?,?)", entry.BlockId, entry.Caption, entry.Name); err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+func (c *SearchDB) Search(query string, filters *repo.SearchFilters) []repo.SearchEntry {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if filters == nil {
+		filters = new(repo.SearchFilters)
+	}
+	var where []string
+	var args []interface{}
+	if match := ftsQuery(query); match != "" {
+		where = append(where, "a.blockId in (select blockId from searchtext where searchtext match ?)")
+		args = append(args, match)
+	}
+	if filters.ThreadId != "" {
+		where = append(where, "a.threadId=?")
+		args = append(args, filters.ThreadId)
+	}
+	if filters.AuthorId != "" {
+		where = append(where, "a.authorId=?")
+		args = append(args, filters.AuthorId)
+	}
+	when := "(case when a.taken>0 then a.taken else a.date end)"
+	if !filters.After.IsZero() {
+		where = append(where, when+">=?")
+		args = append(args, int(filters.After.Unix()))
+	}
+	if !filters.Before.IsZero() {
+		where = append(where, when+"<?")
+		args = append(args, int(filters.Before.Unix()))
+	}
+	if b := filters.Bounds; b != nil {
+		where = append(where, "a.latitude!=0 and a.longitude!=0 and a.latitude between ? and ? and a.longitude between ? and ?")
+		args = append(args, b.MinLat, b.MaxLat, b.MinLon, b.MaxLon)
+	}
+	limit := filters.Limit
+	if limit <= 0 {
+		limit = defaultSearchLimit
+	}
+	args = append(args, limit)
+
+	stm := "select a.blockId, a.threadId, a.dataId, a.authorId, t.caption, t.name, a.date, a.taken, a.latitude, a.longitude from searchattrs a join searchtext t on t.blockId=a.blockId"
+	if len(where) > 0 {
+		stm += " where " + strings.Join(where, " and ")
+	}
+	stm += " order by " + when + " desc limit ?;"
+	return c.handleQuery(stm, args...)
+}
+
+func (c *SearchDB) Has(blockId string) bool {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	stmt, err := c.db.Prepare("select blockId from searchattrs where blockId=?")
+	if err != nil {
+		return false
+	}
+	defer stmt.Close()
+	var ret string
+	if err := stmt.QueryRow(blockId).Scan(&ret); err != nil {
+		return false
+	}
+	return true
+}
+
+func (c *SearchDB) Delete(blockId string) error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if _, err := c.db.Exec("delete from searchattrs where blockId=?", blockId); err != nil {
+		return err
+	}
+	_, err := c.db.Exec("delete from searchtext where blockId=?", blockId)
+	return err
+}
+
+func (c *SearchDB) DeleteByThreadId(threadId string) error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if _, err := c.db.Exec("delete from searchtext where blockId in (select blockId from searchattrs where threadId=?)", threadId); err != nil {
+		return err
+	}
+	_, err := c.db.Exec("delete from searchattrs where threadId=?", threadId)
+	return err
+}
+
+func (c *SearchDB) handleQuery(stm string, args ...interface{}) []repo.SearchEntry {
+	var ret []repo.SearchEntry
+	rows, err := c.db.Query(stm, args...)
+	if err != nil {
+		log.Errorf("error in db query: %s", err)
+		return nil
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var blockId, threadId, dataId, authorId, caption, name string
+		var dateInt, takenInt int
+		var latitude, longitude float64
+		if err := rows.Scan(&blockId, &threadId, &dataId, &authorId, &caption, &name, &dateInt, &takenInt, &latitude, &longitude); err != nil {
+			log.Errorf("error in db scan: %s", err)
+			continue
+		}
+		entry := repo.SearchEntry{
+			BlockId:   blockId,
+			ThreadId:  threadId,
+			DataId:    dataId,
+			AuthorId:  authorId,
+			Caption:   caption,
+			Name:      name,
+			Date:      time.Unix(int64(dateInt), 0),
+			Latitude:  latitude,
+			Longitude: longitude,
+		}
+		if takenInt > 0 {
+			entry.Taken = time.Unix(int64(takenInt), 0)
+		}
+		ret = append(ret, entry)
+	}
+	return ret
+}
+
+// ftsQuery turns free text into prefix terms that must all match,
+// dropping fts syntax so user input can't produce a malformed query
+func ftsQuery(query string) string {
+	var terms []string
+	for _, word := range strings.Fields(query) {
+		word = strings.Map(func(r rune) rune {
+			if r == '"' || r == '*' || r == '^' {
+				return -1
+			}
+			return r
+		}, word)
+		if word == "" {
+			continue
+		}
+		terms = append(terms, `"`+word+`*"`)
+	}
+	return strings.Join(terms, " ")
+}
@@ -0,0 +1,114 @@
+package db
+
+import (
+	"database/sql"
+	"github.com/textileio/textile-go/repo"
+	"sync"
+	"testing"
+	"time"
+)
+
+var sdb repo.SearchStore
+
+func init() {
+	setupSearchDB()
+}
+
+func setupSearchDB() {
+	conn, _ := sql.Open("sqlite3", ":memory:")
+	initDatabaseTables(conn, "")
+	sdb = NewSearchStore(conn, new(sync.Mutex))
+}
+
+func TestSearchDB_Index(t *testing.T) {
+	if err := sdb.Index(&repo.SearchEntry{
+		BlockId:   "b1",
+		ThreadId:  "t1",
+		DataId:    "d1",
+		AuthorId:  "a1",
+		Caption:   "Sunset at the beach",
+		Name:      "IMG_0001",
+		Date:      time.Now(),
+		Taken:     time.Now().Add(-time.Hour * 24 * 30),
+		Latitude:  40.7,
+		Longitude: -74,
+	}); err != nil {
+		t.Error(err)
+	}
+	if err := sdb.Index(&repo.SearchEntry{
+		BlockId:  "b2",
+		ThreadId: "t2",
+		DataId:   "d2",
+		AuthorId: "a2",
+		Caption:  "dinner",
+		Name:     "IMG_0002",
+		Date:     time.Now(),
+	}); err != nil {
+		t.Error(err)
+	}
+
+	// re-indexing replaces
+	if err := sdb.Index(&repo.SearchEntry{
+		BlockId:  "b2",
+		ThreadId: "t2",
+		DataId:   "d2",
+		AuthorId: "a2",
+		Caption:  "late dinner",
+		Name:     "IMG_0002",
+		Date:     time.Now(),
+	}); err != nil {
+		t.Error(err)
+	}
+	if len(sdb.Search("dinner", nil)) != 1 {
+		t.Error("re-index did not replace entry")
+	}
+}
+
+func TestSearchDB_Search(t *testing.T) {
+	if res := sdb.Search("sun", nil); len(res) != 1 || res[0].BlockId != "b1" {
+		t.Error("prefix search failed")
+	}
+	if res := sdb.Search("img", nil); len(res) != 2 {
+		t.Error("name search failed")
+	}
+	if res := sdb.Search("img", &repo.SearchFilters{ThreadId: "t2"}); len(res) != 1 || res[0].BlockId != "b2" {
+		t.Error("thread filter failed")
+	}
+	if res := sdb.Search("", &repo.SearchFilters{Before: time.Now().Add(-time.Hour * 24)}); len(res) != 1 || res[0].BlockId != "b1" {
+		t.Error("date filter failed")
+	}
+	bounds := &repo.SearchBounds{MinLat: 40, MaxLat: 41, MinLon: -75, MaxLon: -73}
+	if res := sdb.Search("", &repo.SearchFilters{Bounds: bounds}); len(res) != 1 || res[0].BlockId != "b1" {
+		t.Error("bounds filter failed")
+	}
+	if res := sdb.Search(`"sun*`, nil); len(res) != 1 {
+		t.Error("search with fts syntax failed")
+	}
+}
+
+func TestSearchDB_Has(t *testing.T) {
+	if !sdb.Has("b1") {
+		t.Error("has failed")
+	}
+	if sdb.Has("nope") {
+		t.Error("has should be false for unindexed block")
+	}
+}
+
+func TestSearchDB_Delete(t *testing.T) {
+	if err := sdb.Delete("b1"); err != nil {
+		t.Error(err)
+	}
+	if len(sdb.Search("sun", nil)) != 0 {
+		t.Error("delete failed")
+	}
+}
+
+func TestSearchDB_DeleteByThreadId(t *testing.T) {
+	if err := sdb.DeleteByThreadId("t2"); err != nil {
+		t.Error(err)
+	}
+	if len(sdb.Search("", nil)) != 0 {
+		t.Error("delete by thread id failed")
+	}
+}
@@ -10,6 +10,9 @@ type ThreadDB struct {
 	modelStore
 }
 
+// threadColumns is the column order handleQuery scans, added columns go on the end
+const threadColumns = "id, name, sk, head, description, coverId, metaId"
+
 func NewThreadStore(db *sql.DB, lock *sync.Mutex) repo.ThreadStore {
 	return &ThreadDB{modelStore{db, lock}}
 }
@@ -21,7 +24,7 @@ func (c *ThreadDB) Add(thread *repo.Thread) error {
 	if err != nil {
 		return err
 	}
-	stm := `insert into threads(id, name, sk, head) values(?,?,?,?)`
+	stm := `insert into threads(id, name, sk, head, description, coverId, metaId) values(?,?,?,?,?,?,?)`
 	stmt, err := tx.Prepare(stm)
 	if err != nil {
 		log.Errorf("error in tx prepare: %s", err)
@@ -33,6 +36,9 @@ func (c *ThreadDB) Add(thread *repo.Thread) error {
 		thread.Name,
 		thread.PrivKey,
 		thread.Head,
+		thread.Description,
+		thread.CoverId,
+		thread.MetaId,
 	)
 	if err != nil {
 		tx.Rollback()
@@ -45,7 +51,7 @@ func (c *ThreadDB) Add(thread *repo.Thread) error {
 func (c *ThreadDB) Get(id string) *repo.Thread {
 	c.lock.Lock()
 	defer c.lock.Unlock()
-	ret := c.handleQuery("select * from threads where id='" + id + "';")
+	ret := c.handleQuery("select " + threadColumns + " from threads where id='" + id + "';")
 	if len(ret) == 0 {
 		return nil
 	}
@@ -59,7 +65,7 @@ func (c *ThreadDB) List(query string) []repo.Thread {
 	if query != "" {
 		q = " where " + query
 	}
-	return c.handleQuery("select * from threads" + q + ";")
+	return c.handleQuery("select " + threadColumns + " from threads" + q + ";")
 }
 
 func (c *ThreadDB) UpdateHead(id string, head string) error {
@@ -69,6 +75,13 @@ func (c *ThreadDB) UpdateHead(id string, head string) error {
 	return err
 }
 
+func (c *ThreadDB) UpdateMeta(id string, name string, description string, coverId string, metaId string) error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	_, err := c.db.Exec("update threads set name=?, description=?, coverId=?, metaId=? where id=?", name, description, coverId, metaId, id)
+	return err
+}
+
 func (c *ThreadDB) Delete(id string) error {
 	c.lock.Lock()
 	defer c.lock.Unlock()
@@ -84,17 +97,20 @@ func (c *ThreadDB) handleQuery(stm string) []repo.Thread {
 		return nil
 	}
 	for rows.Next() {
-		var id, name, head string
+		var id, name, head, description, coverId, metaId string
 		var skb []byte
-		if err := rows.Scan(&id, &name, &skb, &head); err != nil {
+		if err := rows.Scan(&id, &name, &skb, &head, &description, &coverId, &metaId); err != nil {
 			log.Errorf("error in db scan: %s", err)
 			continue
 		}
 		thread := repo.Thread{
-			Id:      id,
-			Name:    name,
-			PrivKey: skb,
-			Head:    head,
+			Id:          id,
+			Name:        name,
+			PrivKey:     skb,
+			Head:        head,
+			Description: description,
+			CoverId:     coverId,
+			MetaId:      metaId,
 		}
 		ret = append(ret, thread)
 	}
@@ -109,6 +109,30 @@ func TestThreadDB_UpdateHead(t *testing.T) {
 	}
 }
 
+func TestThreadDB_UpdateMeta(t *testing.T) {
+	setupThreadDB()
+	err := tdb.Add(&repo.Thread{
+		Id:      "Qmabc",
+		Name:    "boom",
+		PrivKey: make([]byte, 8),
+	})
+	if err != nil {
+		t.Error(err)
+	}
+	err = tdb.UpdateMeta("Qmabc", "bang", "a thread", "Qmcover", "Qmmeta")
+	if err != nil {
+		t.Error(err)
+	}
+	th := tdb.Get("Qmabc")
+	if th == nil {
+		t.Error("could not get thread")
+		return
+	}
+	if th.Name != "bang" || th.Description != "a thread" || th.CoverId != "Qmcover" || th.MetaId != "Qmmeta" {
+		t.Error("update meta failed")
+	}
+}
+
 func TestThreadDB_Delete(t *testing.T) {
 	setupThreadDB()
 	err := tdb.Add(&repo.Thread{
@@ -0,0 +1,127 @@
+package repo
+
+import (
+	"encoding/base64"
+	"errors"
+	"github.com/textileio/textile-go/crypto"
+	ipfsrepo "gx/ipfs/Qmb8jW1F6ZVyYPW1epc2GFRipmd3S8tJ48pZKBVPzVqj9T/go-ipfs/repo"
+	native "gx/ipfs/Qmb8jW1F6ZVyYPW1epc2GFRipmd3S8tJ48pZKBVPzVqj9T/go-ipfs/repo/config"
+	"gx/ipfs/Qmb8jW1F6ZVyYPW1epc2GFRipmd3S8tJ48pZKBVPzVqj9T/go-ipfs/repo/fsrepo"
+	"strings"
+)
+
+var ErrPasswordRequired = errors.New("repo is encrypted, password required")
+var ErrInvalidPassword = errors.New("invalid repo password")
+
+// encrypted identity keys are stored as a prefixed base64 string,
+// the prefix can not collide with a plain base64 key
+const encryptedKeyPrefix = "encrypted:"
+
+// IsIdentityEncrypted returns whether or not the identity private key is encrypted
+func IsIdentityEncrypted(ident native.Identity) bool {
+	return strings.HasPrefix(ident.PrivKey, encryptedKeyPrefix)
+}
+
+// EncryptIdentity encrypts the identity private key with password
+func EncryptIdentity(ident *native.Identity, password string) error {
+	if IsIdentityEncrypted(*ident) {
+		return errors.New("identity is already encrypted")
+	}
+	ciph, err := crypto.EncryptWithPassword([]byte(ident.PrivKey), password)
+	if err != nil {
+		return err
+	}
+	ident.PrivKey = encryptedKeyPrefix + base64.StdEncoding.EncodeToString(ciph)
+	return nil
+}
+
+// DecryptIdentity decrypts the identity private key with password
+func DecryptIdentity(ident *native.Identity, password string) error {
+	if !IsIdentityEncrypted(*ident) {
+		return nil
+	}
+	if password == "" {
+		return ErrPasswordRequired
+	}
+	ciph, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(ident.PrivKey, encryptedKeyPrefix))
+	if err != nil {
+		return err
+	}
+	plain, err := crypto.DecryptWithPassword(ciph, password)
+	if err != nil {
+		return ErrInvalidPassword
+	}
+	ident.PrivKey = string(plain)
+	return nil
+}
+
+// OpenRepo opens the ipfs repo at root, decrypting the identity key in memory only
+func OpenRepo(root string, password string) (ipfsrepo.Repo, error) {
+	r, err := fsrepo.Open(root)
+	if err != nil {
+		return nil, err
+	}
+	cfg, err := r.Config()
+	if err != nil {
+		return nil, err
+	}
+	if !IsIdentityEncrypted(cfg.Identity) {
+		return r, nil
+	}
+	ident := cfg.Identity
+	if err := DecryptIdentity(&ident, password); err != nil {
+		r.Close()
+		return nil, err
+	}
+	return &identityRepo{Repo: r, privKey: ident.PrivKey}, nil
+}
+
+// ChangeIdentityPassword re-encrypts the identity key at root, an empty next password stores it in the clear
+func ChangeIdentityPassword(root string, current string, next string) error {
+	r, err := fsrepo.Open(root)
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+	cfg, err := r.Config()
+	if err != nil {
+		return err
+	}
+	ident := cfg.Identity
+	if err := DecryptIdentity(&ident, current); err != nil {
+		return err
+	}
+	if next != "" {
+		if err := EncryptIdentity(&ident, next); err != nil {
+			return err
+		}
+	}
+	return r.SetConfigKey("Identity.PrivKey", ident.PrivKey)
+}
+
+// identityRepo serves a config with the decrypted identity key,
+// while the on-disk config keeps the encrypted one
+type identityRepo struct {
+	ipfsrepo.Repo
+	privKey string
+}
+
+func (r *identityRepo) Config() (*native.Config, error) {
+	cfg, err := r.Repo.Config()
+	if err != nil {
+		return nil, err
+	}
+	plain := *cfg
+	plain.Identity.PrivKey = r.privKey
+	return &plain, nil
+}
+
+func (r *identityRepo) SetConfig(cfg *native.Config) error {
+	current, err := r.Repo.Config()
+	if err != nil {
+		return err
+	}
+	stored := *cfg
+	stored.Identity.PrivKey = current.Identity.PrivKey
+	return r.Repo.SetConfig(&stored)
+}
@@ -23,7 +23,7 @@ var ErrRepoExists = errors.New("repo not empty, reinitializing would overwrite y
 
 const versionFilename = "textile_version"
 
-func DoInit(repoRoot string, version string, mnemonic *string, initDB func(string) error, initConfig func(time.Time) error) (string, error) {
+func DoInit(repoRoot string, version string, mnemonic *string, passphrase string, password string, initDB func(string) error, initConfig func(time.Time) error) (string, error) {
 	if err := checkWriteable(repoRoot); err != nil {
 		return "", err
 	}
@@ -55,7 +55,7 @@ func DoInit(repoRoot string, version string, mnemonic *string, initDB func(strin
 		return "", err
 	}
 
-	sk, mnem, err := wutil.PrivKeyFromMnemonic(mnemonic)
+	sk, mnem, err := wutil.PrivKeyFromMnemonic(mnemonic, passphrase)
 	if err != nil {
 		return "", err
 	}
@@ -64,6 +64,11 @@ func DoInit(repoRoot string, version string, mnemonic *string, initDB func(strin
 	if err != nil {
 		return "", err
 	}
+	if password != "" {
+		if err := EncryptIdentity(&identity, password); err != nil {
+			return "", err
+		}
+	}
 
 	conf, err := config.Init(identity, version)
 	if err != nil {
@@ -74,6 +79,7 @@ func DoInit(repoRoot string, version string, mnemonic *string, initDB func(strin
 		return "", err
 	}
 
+	// the datastore connection is already keyed with password when opened
 	if err := initDB(""); err != nil {
 		return "", err
 	}
@@ -82,7 +88,7 @@ func DoInit(repoRoot string, version string, mnemonic *string, initDB func(strin
 		return "", err
 	}
 
-	return mnem, initializeIpnsKeyspace(repoRoot)
+	return mnem, initializeIpnsKeyspace(repoRoot, password)
 }
 
 func checkWriteable(dir string) error {
@@ -113,11 +119,11 @@ func checkWriteable(dir string) error {
 	return err
 }
 
-func initializeIpnsKeyspace(repoRoot string) error {
+func initializeIpnsKeyspace(repoRoot string, password string) error {
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
-	r, err := fsrepo.Open(repoRoot)
+	r, err := OpenRepo(repoRoot, password)
 	if err != nil { // NB: repo is owned by the node
 		return err
 	}
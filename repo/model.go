@@ -5,15 +5,19 @@ import (
 )
 
 type Thread struct {
-	Id      string `json:"id"`
-	Name    string `json:"name"`
-	PrivKey []byte `json:"sk"`
-	Head    string `json:"head"`
+	Id          string `json:"id"`
+	Name        string `json:"name"`
+	PrivKey     []byte `json:"sk"`
+	Head        string `json:"head"`
+	Description string `json:"description,omitempty"`
+	CoverId     string `json:"cover_id,omitempty"`
+	MetaId      string `json:"meta_id,omitempty"`
 }
 
 type Device struct {
-	Id   string `json:"id"`
-	Name string `json:"name"`
+	Id          string `json:"id"`
+	Name        string `json:"name"`
+	Attestation []byte `json:"attestation,omitempty"`
 }
 
 type Peer struct {
@@ -23,6 +27,21 @@ type Peer struct {
 	ThreadId string `json:"thread_id"`
 }
 
+type Contact struct {
+	Id       string    `json:"id"`
+	PubKey   []byte    `json:"pk"`
+	Username string    `json:"username,omitempty"`
+	AvatarId string    `json:"avatar_id,omitempty"`
+	Added    time.Time `json:"added"`
+	LastSeen time.Time `json:"last_seen"`
+}
+
+type CachedProfile struct {
+	Id      string    `json:"id"`
+	Profile []byte    `json:"profile"`
+	Cached  time.Time `json:"cached"`
+}
+
 type Block struct {
 	Id       string    `json:"id"`
 	Date     time.Time `json:"date"`
@@ -50,11 +69,84 @@ const (
 	JoinBlock
 	LeaveBlock
 	PhotoBlock
+	InviteRevokeBlock
+	MetadataBlock
+	AlbumBlock
+	AlbumAddBlock
+	AlbumRemoveBlock
 
-	IgnoreBlock = 200
-	MergeBlock  = 201
+	IgnoreBlock  = 200
+	MergeBlock   = 201
+	RestoreBlock = 202
+	CaptionBlock = 203
 )
 
+// SearchEntry is the decrypted, searchable content of a block
+type SearchEntry struct {
+	BlockId   string    `json:"block_id"`
+	ThreadId  string    `json:"thread_id"`
+	DataId    string    `json:"data_id"`
+	AuthorId  string    `json:"author_id"`
+	Caption   string    `json:"caption,omitempty"`
+	Name      string    `json:"name,omitempty"`
+	Date      time.Time `json:"date"`
+	Taken     time.Time `json:"taken,omitempty"`
+	Latitude  float64   `json:"latitude,omitempty"`
+	Longitude float64   `json:"longitude,omitempty"`
+}
+
+// SearchFilters narrow search results by attribute, zero values are ignored.
+// Dates apply to when a photo was taken, or when it was added if unknown.
+type SearchFilters struct {
+	ThreadId string        `json:"thread_id,omitempty"`
+	AuthorId string        `json:"author_id,omitempty"`
+	After    time.Time     `json:"after,omitempty"`
+	Before   time.Time     `json:"before,omitempty"`
+	Bounds   *SearchBounds `json:"bounds,omitempty"`
+	Limit    int           `json:"limit,omitempty"`
+}
+
+// SearchBounds is a lat/lon bounding box
+type SearchBounds struct {
+	MinLat float64 `json:"min_lat"`
+	MaxLat float64 `json:"max_lat"`
+	MinLon float64 `json:"min_lon"`
+	MaxLon float64 `json:"max_lon"`
+}
+
+type MissingBlock struct {
+	Id       string    `json:"id"`
+	ThreadId string    `json:"thread_id"`
+	Attempts int       `json:"attempts"`
+	Added    time.Time `json:"added"`
+	Tried    time.Time `json:"tried"`
+}
+
+// PhotoHash maps the plaintext hash of an added photo to its encrypted directory,
+// and to its key encrypted with the account key
+type PhotoHash struct {
+	Hash      string    `json:"hash"`
+	DataId    string    `json:"data_id"`
+	KeyCipher []byte    `json:"key_cipher"`
+	Added     time.Time `json:"added"`
+}
+
+// AlbumPhoto marks a photo as currently in an album, the latest membership block wins
+type AlbumPhoto struct {
+	AlbumId  string `json:"album_id"`
+	DataId   string `json:"data_id"`
+	ThreadId string `json:"thread_id"`
+}
+
+// PhotoImport records a file that was imported into a thread, so an import can resume
+type PhotoImport struct {
+	Path     string    `json:"path"`
+	ThreadId string    `json:"thread_id"`
+	DataId   string    `json:"data_id"`
+	BlockId  string    `json:"block_id"`
+	Added    time.Time `json:"added"`
+}
+
 type PinRequest struct {
 	Id   string    `json:"id"`
 	Date time.Time `json:"date"`
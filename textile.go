@@ -13,6 +13,7 @@ import (
 	"github.com/textileio/textile-go/core"
 	rconfig "github.com/textileio/textile-go/repo/config"
 	"github.com/textileio/textile-go/wallet"
+	"github.com/textileio/textile-go/wallet/model"
 	"github.com/textileio/textile-go/wallet/thread"
 	"gopkg.in/abiosoft/ishell.v2"
 	icore "gx/ipfs/Qmb8jW1F6ZVyYPW1epc2GFRipmd3S8tJ48pZKBVPzVqj9T/go-ipfs/core"
@@ -30,6 +31,13 @@ type Opts struct {
 	// repo location
 	DataDir string `short:"r" long:"data-dir" description:"specify the data directory to be used"`
 
+	// account seed, only used on init
+	Mnemonic   string `short:"m" long:"mnemonic" description:"restore the account from a mnemonic phrase on init"`
+	Passphrase string `long:"passphrase" description:"set the optional bip39 passphrase for the account mnemonic on init"`
+
+	// repo encryption
+	Password string `short:"p" long:"password" description:"set the password used to encrypt the repo on init, and to unlock it on start"`
+
 	// logging options
 	LogLevel   string `short:"l" long:"log-level" description:"set the logging level [debug, info, notice, warning, error, critical]" default:"debug"`
 	NoLogFiles bool   `short:"n" long:"no-log-files" description:"do not save logs on disk"`
@@ -38,6 +46,9 @@ type Opts struct {
 	DaemonMode bool `short:"d" long:"daemon" description:"start in a non-interactive daemon mode"`
 	ServerMode bool `short:"s" long:"server" description:"start in server mode"`
 
+	// photo settings
+	Renditions string `long:"renditions" description:"set the photo renditions as name:width[:square], comma separated (e.g. small:320,square:100:square)"`
+
 	// gateway settings
 	GatewayBindAddr string `short:"g" long:"gateway-bind-addr" description:"set the gateway address" default:"127.0.0.1:random"`
 
@@ -103,25 +114,51 @@ func main() {
 		return
 	}
 
+	// restore from mnemonic if provided
+	var mnemonic *string
+	if Options.Mnemonic != "" {
+		mnemonic = &Options.Mnemonic
+	}
+
+	// photo renditions, defaults are used if none are given
+	var renditions []model.Rendition
+	if Options.Renditions != "" {
+		renditions, err = wallet.ParseRenditions(Options.Renditions)
+		if err != nil {
+			fmt.Println(fmt.Errorf("parse renditions failed: %s", err))
+			return
+		}
+	}
+
 	// node setup
 	config := core.NodeConfig{
 		WalletConfig: wallet.Config{
 			RepoPath:   dataDir,
+			Mnemonic:   mnemonic,
+			Passphrase: Options.Passphrase,
+			Password:   Options.Password,
 			SwarmPorts: Options.SwarmPorts,
 			IsMobile:   false,
 			IsServer:   Options.ServerMode,
 			CafeAddr:   Options.CafeAddr,
+			Renditions: renditions,
 		},
 		LogLevel: level,
 		LogFiles: !Options.NoLogFiles,
 	}
 
 	// create a desktop node
-	node, _, err := core.NewNode(config)
+	node, mnem, err := core.NewNode(config)
 	if err != nil {
 		fmt.Println(fmt.Errorf("create desktop node failed: %s", err))
 		return
 	}
+	if mnem != "" && Options.Mnemonic == "" {
+		fmt.Println("new account created, write down your mnemonic phrase: " + mnem)
+	}
+	if !node.Wallet.IsIdentityDerived() {
+		fmt.Println("this account's identity was not derived from its mnemonic, the mnemonic can't restore it")
+	}
 	core.Node = node
 
 	// check cafe mode
@@ -245,6 +282,29 @@ func main() {
 				c.Println(status)
 			},
 		})
+		shell.AddCmd(&ishell.Cmd{
+			Name: "recover",
+			Help: "restore threads and devices from the account backup",
+			Func: cmd.RecoverAccount,
+		})
+		shell.AddCmd(&ishell.Cmd{
+			Name: "search",
+			Help: "search photos by caption or name, filter with thread:, author:, after:, before: or limit:",
+			Func: cmd.Search,
+		})
+		{
+			repoCmd := &ishell.Cmd{
+				Name:     "repo",
+				Help:     "manage the local repo",
+				LongHelp: "Manage local repo encryption.",
+			}
+			repoCmd.AddCmd(&ishell.Cmd{
+				Name: "change-password",
+				Help: "re-encrypt the repo with a new password (node must be stopped)",
+				Func: cmd.ChangeRepoPassword,
+			})
+			shell.AddCmd(repoCmd)
+		}
 		{
 			cafeCmd := &ishell.Cmd{
 				Name:     "cafe",
@@ -309,6 +369,21 @@ func main() {
 				Help: "set local profile avatar",
 				Func: cmd.SetAvatarId,
 			})
+			profileCmd.AddCmd(&ishell.Cmd{
+				Name: "avatar",
+				Help: "set a public avatar from an image, with square renditions",
+				Func: cmd.SetAvatar,
+			})
+			profileCmd.AddCmd(&ishell.Cmd{
+				Name: "directory",
+				Help: "set cafe directory visibility (hidden, username, identities, or leave)",
+				Func: cmd.SetDirectoryVisibility,
+			})
+			profileCmd.AddCmd(&ishell.Cmd{
+				Name: "set",
+				Help: "set a public profile field (display_name, bio, threads, or custom), empty value clears it",
+				Func: cmd.SetProfileField,
+			})
 			shell.AddCmd(profileCmd)
 		}
 		{
@@ -345,6 +420,11 @@ func main() {
 				Help: "add a new photo",
 				Func: cmd.AddPhoto,
 			})
+			photoCmd.AddCmd(&ishell.Cmd{
+				Name: "import",
+				Help: "add every photo in a directory to a thread (photo import <dir> --thread <id>)",
+				Func: cmd.ImportPhotos,
+			})
 			photoCmd.AddCmd(&ishell.Cmd{
 				Name: "share",
 				Help: "share a photo to a different thread",
@@ -375,8 +455,51 @@ func main() {
 				Help: "ignore a photo in a thread (requires block id, not photo id)",
 				Func: cmd.IgnorePhoto,
 			})
+			photoCmd.AddCmd(&ishell.Cmd{
+				Name: "unignore",
+				Help: "restore an ignored photo in a thread (requires block id, not photo id)",
+				Func: cmd.UnignorePhoto,
+			})
+			photoCmd.AddCmd(&ishell.Cmd{
+				Name: "caption",
+				Help: "edit the caption of a photo in a thread (requires block id, not photo id)",
+				Func: cmd.EditPhotoCaption,
+			})
 			shell.AddCmd(photoCmd)
 		}
+		{
+			albumCmd := &ishell.Cmd{
+				Name:     "album",
+				Help:     "manage albums",
+				LongHelp: "Add and list albums, and add or remove their photos.",
+			}
+			albumCmd.AddCmd(&ishell.Cmd{
+				Name: "add",
+				Help: "add a new album to a thread",
+				Func: cmd.AddAlbum,
+			})
+			albumCmd.AddCmd(&ishell.Cmd{
+				Name: "ls",
+				Help: "list albums in a thread",
+				Func: cmd.ListAlbums,
+			})
+			albumCmd.AddCmd(&ishell.Cmd{
+				Name: "photos",
+				Help: "list photos in an album",
+				Func: cmd.ListAlbumPhotos,
+			})
+			albumCmd.AddCmd(&ishell.Cmd{
+				Name: "put",
+				Help: "add a thread photo to an album",
+				Func: cmd.AddAlbumPhoto,
+			})
+			albumCmd.AddCmd(&ishell.Cmd{
+				Name: "rm",
+				Help: "remove a photo from an album",
+				Func: cmd.RemoveAlbumPhoto,
+			})
+			shell.AddCmd(albumCmd)
+		}
 		{
 			threadCmd := &ishell.Cmd{
 				Name:     "thread",
@@ -405,7 +528,7 @@ func main() {
 			})
 			threadCmd.AddCmd(&ishell.Cmd{
 				Name: "invite",
-				Help: "invite a peer to a thread",
+				Help: "invite a peer (by pub key, contact id, or username) to a thread",
 				Func: cmd.AddThreadInvite,
 			})
 			threadCmd.AddCmd(&ishell.Cmd{
@@ -415,30 +538,103 @@ func main() {
 			})
 			threadCmd.AddCmd(&ishell.Cmd{
 				Name: "invite-external",
-				Help: "create an external invite link",
+				Help: "create an external invite link, with an optional expiry (e.g., 24h) and max uses",
 				Func: cmd.AddExternalThreadInvite,
 			})
 			threadCmd.AddCmd(&ishell.Cmd{
 				Name: "accept-external",
-				Help: "accept an external thread invite",
+				Help: "accept an external thread invite link (or id and key)",
 				Func: cmd.AcceptExternalThreadInvite,
 			})
+			threadCmd.AddCmd(&ishell.Cmd{
+				Name: "revoke-external",
+				Help: "revoke an external thread invite",
+				Func: cmd.RevokeExternalThreadInvite,
+			})
+			threadCmd.AddCmd(&ishell.Cmd{
+				Name: "sync",
+				Help: "pull missed blocks from thread peers",
+				Func: cmd.SyncThread,
+			})
+			threadCmd.AddCmd(&ishell.Cmd{
+				Name: "export-photos",
+				Help: "save decrypted thread photos to a directory",
+				Func: cmd.ExportThreadPhotos,
+			})
+			threadCmd.AddCmd(&ishell.Cmd{
+				Name: "rename",
+				Help: "rename a thread",
+				Func: cmd.RenameThread,
+			})
+			threadCmd.AddCmd(&ishell.Cmd{
+				Name: "describe",
+				Help: "set a thread description",
+				Func: cmd.DescribeThread,
+			})
+			threadCmd.AddCmd(&ishell.Cmd{
+				Name: "cover",
+				Help: "set a thread cover photo",
+				Func: cmd.SetThreadCover,
+			})
 			shell.AddCmd(threadCmd)
 		}
+		{
+			contactCmd := &ishell.Cmd{
+				Name:     "contact",
+				Help:     "manage contacts",
+				LongHelp: "Add, remove, refresh, and list contacts seen across threads.",
+			}
+			contactCmd.AddCmd(&ishell.Cmd{
+				Name: "add",
+				Help: "add a contact by pub key",
+				Func: cmd.AddContact,
+			})
+			contactCmd.AddCmd(&ishell.Cmd{
+				Name: "rm",
+				Help: "remove a contact by id",
+				Func: cmd.RemoveContact,
+			})
+			contactCmd.AddCmd(&ishell.Cmd{
+				Name: "refresh",
+				Help: "resolve a contact's profile",
+				Func: cmd.RefreshContact,
+			})
+			contactCmd.AddCmd(&ishell.Cmd{
+				Name: "find",
+				Help: "find a contact by username in the cafe directory",
+				Func: cmd.FindContact,
+			})
+			contactCmd.AddCmd(&ishell.Cmd{
+				Name: "ls",
+				Help: "list contacts",
+				Func: cmd.ListContacts,
+			})
+			shell.AddCmd(contactCmd)
+		}
 		{
 			deviceCmd := &ishell.Cmd{
 				Name:     "device",
 				Help:     "manage connected devices",
-				LongHelp: "Add, remove, and list connected devices.",
+				LongHelp: "Pair, add, remove, and list connected devices.",
 			}
 			deviceCmd.AddCmd(&ishell.Cmd{
 				Name: "add",
 				Help: "add a new device",
 				Func: cmd.AddDevice,
 			})
+			deviceCmd.AddCmd(&ishell.Cmd{
+				Name: "code",
+				Help: "show a pairing link for this device",
+				Func: cmd.NewPairingRequest,
+			})
+			deviceCmd.AddCmd(&ishell.Cmd{
+				Name: "pair",
+				Help: "pair a new device from its pairing link",
+				Func: cmd.PairDevice,
+			})
 			deviceCmd.AddCmd(&ishell.Cmd{
 				Name: "rm",
-				Help: "remove a device by name",
+				Help: "remove a device by id, re-keying all threads",
 				Func: cmd.RemoveDevice,
 			})
 			deviceCmd.AddCmd(&ishell.Cmd{
@@ -489,13 +685,17 @@ func start() error {
 					break
 				case wallet.DeviceRemoved:
 					break
+				case wallet.ProfileUpdated:
+					break
 				}
 			}
 		}
 	}()
 
 	// start the gateway
-	core.Node.StartGateway(resolveAddress(Options.GatewayBindAddr))
+	if err := core.Node.StartGateway(resolveAddress(Options.GatewayBindAddr)); err != nil {
+		return err
+	}
 
 	// start cafe server
 	if Options.CafeBindAddr != "" {
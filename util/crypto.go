@@ -3,7 +3,6 @@ package util
 import (
 	"bytes"
 	"crypto/hmac"
-	"crypto/rand"
 	"crypto/sha256"
 	"encoding/base64"
 	"github.com/textileio/textile-go/crypto"
@@ -12,11 +11,10 @@ import (
 	libp2pc "gx/ipfs/QmaPbCnUMBohSGo3KnxEa2bHqyJVVeEEcwtqJAYxerieBo/go-libp2p-crypto"
 	"gx/ipfs/Qmb8jW1F6ZVyYPW1epc2GFRipmd3S8tJ48pZKBVPzVqj9T/go-ipfs/repo/config"
 	"io"
-	"io/ioutil"
 )
 
-// PrivKeyFromMnemonic creates a private key form a mnemonic phrase
-func PrivKeyFromMnemonic(mnemonic *string) (libp2pc.PrivKey, string, error) {
+// PrivKeyFromMnemonic creates a private key form a mnemonic phrase and optional bip39 passphrase
+func PrivKeyFromMnemonic(mnemonic *string, passphrase string) (libp2pc.PrivKey, string, error) {
 	if mnemonic == nil {
 		mnemonics, err := createMnemonic(bip39.NewEntropy, bip39.NewMnemonic)
 		if err != nil {
@@ -26,8 +24,7 @@ func PrivKeyFromMnemonic(mnemonic *string) (libp2pc.PrivKey, string, error) {
 	}
 
 	// create the bip39 seed from the phrase
-	// TODO: allow password?
-	seed := bip39.NewSeed(*mnemonic, "")
+	seed := bip39.NewSeed(*mnemonic, passphrase)
 	key, err := identityKeyFromSeed(seed)
 	if err != nil {
 		return nil, "", err
@@ -39,18 +36,12 @@ func PrivKeyFromMnemonic(mnemonic *string) (libp2pc.PrivKey, string, error) {
 	return sk, *mnemonic, nil
 }
 
-// IdentityConfig initializes a new identity.
+// IdentityConfig initializes a new identity from the account key.
 func IdentityConfig(sk libp2pc.PrivKey) (config.Identity, error) {
-	log.Infof("generating Ed25519 keypair for peer identity...")
-
 	ident := config.Identity{}
-	sk, pk, err := libp2pc.GenerateEd25519Key(rand.Reader)
-	if err != nil {
-		return ident, err
-	}
+	pk := sk.GetPublic()
 
-	// currently storing key unencrypted. in the future we need to encrypt it.
-	// TODO(security)
+	// the key is stored unencrypted here, repo.DoInit encrypts it when a password is given
 	skbytes, err := sk.Bytes()
 	if err != nil {
 		return ident, err
@@ -71,13 +62,23 @@ func IdentityConfig(sk libp2pc.PrivKey) (config.Identity, error) {
 	return ident, nil
 }
 
-// GetEncryptedReaderBytes reads reader bytes and returns the encrypted result
-func GetEncryptedReaderBytes(reader io.Reader, key []byte) ([]byte, error) {
-	bts, err := ioutil.ReadAll(reader)
-	if err != nil {
-		return nil, err
-	}
-	return crypto.EncryptAES(bts, key)
+// NewEncryptedReader returns a reader of reader's data encrypted in chunks under key's subkey for name.
+// Data is encrypted as it is read, closing the reader early stops the encryption.
+func NewEncryptedReader(reader io.Reader, key []byte, name string) io.ReadCloser {
+	pr, pw := io.Pipe()
+	go func() {
+		writer, err := crypto.NewEncryptWriter(pw, key, name)
+		if err != nil {
+			pw.CloseWithError(err)
+			return
+		}
+		if _, err := io.Copy(writer, reader); err != nil {
+			pw.CloseWithError(err)
+			return
+		}
+		pw.CloseWithError(writer.Close())
+	}()
+	return pr
 }
 
 // UnmarshalPrivateKeyFromString attempts to create a private key from a base64 encoded string
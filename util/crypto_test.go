@@ -1,19 +1,56 @@
 package util
 
 import (
+	"bytes"
+	"github.com/textileio/textile-go/crypto"
+	"io/ioutil"
 	"testing"
 )
 
 func Test_PrivKeyFromMnemonic(t *testing.T) {
-	// TODO
+	sk, mnemonic, err := PrivKeyFromMnemonic(nil, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	again, _, err := PrivKeyFromMnemonic(&mnemonic, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !sk.Equals(again) {
+		t.Error("key from mnemonic is not deterministic")
+	}
+	protected, _, err := PrivKeyFromMnemonic(&mnemonic, "passphrase")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sk.Equals(protected) {
+		t.Error("passphrase did not change key")
+	}
 }
 
 func Test_IDAndSecretFromMnemonic(t *testing.T) {
 	// TODO
 }
 
-func Test_GetEncryptedReaderBytes(t *testing.T) {
-	// TODO
+func Test_NewEncryptedReader(t *testing.T) {
+	key, err := crypto.GenerateAESKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	plain := bytes.Repeat([]byte("textile"), crypto.StreamChunkSize)
+	reader := NewEncryptedReader(bytes.NewReader(plain), key, "photo")
+	defer reader.Close()
+	decrypted, err := crypto.NewDecryptReader(reader, key, "photo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := ioutil.ReadAll(decrypted)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(data, plain) {
+		t.Error("decrypted data does not match")
+	}
 }
 
 func Test_UnmarshalPrivateKeyFromString(t *testing.T) {
@@ -0,0 +1,63 @@
+package util
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha512"
+	"encoding/binary"
+	libp2pc "gx/ipfs/QmaPbCnUMBohSGo3KnxEa2bHqyJVVeEEcwtqJAYxerieBo/go-libp2p-crypto"
+)
+
+// KeyPurpose namespaces keys derived from the account key
+type KeyPurpose uint32
+
+const (
+	ThreadKey KeyPurpose = iota
+	DeviceKey
+)
+
+// String returns the purpose name, used to track derivation indexes
+func (p KeyPurpose) String() string {
+	switch p {
+	case ThreadKey:
+		return "thread"
+	case DeviceKey:
+		return "device"
+	default:
+		return "unknown"
+	}
+}
+
+// hardened marks an index as hardened, ed25519 only supports hardened derivation
+const hardened uint32 = 0x80000000
+
+// DeriveKey derives the ed25519 key at m/purpose'/index' using SLIP-0010 style
+// hardened derivation. The account key, which is itself derived from the mnemonic
+// seed, is the root of the hierarchy, so every derived key is recoverable from the mnemonic.
+func DeriveKey(account libp2pc.PrivKey, purpose KeyPurpose, index uint32) (libp2pc.PrivKey, error) {
+	seed, err := account.Bytes()
+	if err != nil {
+		return nil, err
+	}
+	key, chain := hdNode([]byte("textile account seed"), seed)
+	for _, i := range []uint32{uint32(purpose), index} {
+		data := make([]byte, 37)
+		copy(data[1:33], key)
+		binary.BigEndian.PutUint32(data[33:], i|hardened)
+		key, chain = hdNode(chain, data)
+	}
+	// bits are not meaningful w/ this method in ed25519, so specify whatever
+	sk, _, err := libp2pc.GenerateKeyPairWithReader(libp2pc.Ed25519, 2048, bytes.NewReader(key))
+	if err != nil {
+		return nil, err
+	}
+	return sk, nil
+}
+
+// hdNode returns the key and chain code halves of hmac-sha512(secret, data)
+func hdNode(secret []byte, data []byte) ([]byte, []byte) {
+	hm := hmac.New(sha512.New, secret)
+	hm.Write(data)
+	sum := hm.Sum(nil)
+	return sum[:32], sum[32:]
+}
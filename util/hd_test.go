@@ -0,0 +1,39 @@
+package util
+
+import (
+	"testing"
+)
+
+var hdTestMnemonic = "tail ribbon actor jazz swing skirt assume rapid nurse spike tag soft"
+
+func Test_DeriveKey(t *testing.T) {
+	account, _, err := PrivKeyFromMnemonic(&hdTestMnemonic, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	first, err := DeriveKey(account, ThreadKey, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	again, err := DeriveKey(account, ThreadKey, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !first.Equals(again) {
+		t.Error("derived keys are not deterministic")
+	}
+	second, err := DeriveKey(account, ThreadKey, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if first.Equals(second) {
+		t.Error("derived keys at different indexes are equal")
+	}
+	device, err := DeriveKey(account, DeviceKey, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if first.Equals(device) {
+		t.Error("derived keys for different purposes are equal")
+	}
+}
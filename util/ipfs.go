@@ -42,6 +42,11 @@ type IpnsEntry struct {
 
 // GetDataAtPath return bytes under an ipfs path
 func GetDataAtPath(ipfs *core.IpfsNode, path string) ([]byte, error) {
+	return GetDataAtPathWithTimeout(ipfs, path, catTimeout)
+}
+
+// GetDataAtPathWithTimeout return bytes under an ipfs path, giving up after timeout
+func GetDataAtPathWithTimeout(ipfs *core.IpfsNode, path string, timeout time.Duration) ([]byte, error) {
 	// convert string to an ipfs path
 	ip, err := coreapi.ParsePath(path)
 	if err != nil {
@@ -49,7 +54,7 @@ func GetDataAtPath(ipfs *core.IpfsNode, path string) ([]byte, error) {
 	}
 
 	api := coreapi.NewCoreAPI(ipfs)
-	ctx, cancel := context.WithTimeout(ipfs.Context(), catTimeout)
+	ctx, cancel := context.WithTimeout(ipfs.Context(), timeout)
 	defer cancel()
 	reader, err := api.Unixfs().Cat(ctx, ip)
 	if err != nil {
@@ -65,6 +70,35 @@ func GetDataAtPath(ipfs *core.IpfsNode, path string) ([]byte, error) {
 	return ioutil.ReadAll(reader)
 }
 
+// GetReaderAtPath returns a reader over data under an ipfs path, which must be closed when done
+func GetReaderAtPath(ipfs *core.IpfsNode, path string) (io.ReadCloser, error) {
+	ip, err := coreapi.ParsePath(path)
+	if err != nil {
+		return nil, err
+	}
+
+	// the context lives as long as the reader
+	api := coreapi.NewCoreAPI(ipfs)
+	ctx, cancel := context.WithCancel(ipfs.Context())
+	reader, err := api.Unixfs().Cat(ctx, ip)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	return &catReader{ReadCloser: reader, cancel: cancel}, nil
+}
+
+// catReader cancels its cat context on close
+type catReader struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (r *catReader) Close() error {
+	defer r.cancel()
+	return r.ReadCloser.Close()
+}
+
 // GetArchiveAtPath builds an archive from directory links under an ipfs path
 // NOTE: currently will bork if dir path contains other dirs (depth > 1)
 func GetArchiveAtPath(ipfs *core.IpfsNode, path string) (io.Reader, error) {
@@ -92,12 +126,11 @@ func GetArchiveAtPath(ipfs *core.IpfsNode, path string) (io.Reader, error) {
 
 	// virtual archive
 	archive, err := client.NewArchive(nil)
-	for _, link := range links {
-		data, err := GetDataAtPath(ipfs, link.Cid.Hash().B58String())
-		if err != nil {
-			return nil, err
-		}
-		archive.AddFile(data, link.Name)
+	if err != nil {
+		return nil, err
+	}
+	if err := addLinksToArchive(ipfs, links, archive); err != nil {
+		return nil, err
 	}
 	if err := archive.Close(); err != nil {
 		return nil, err
@@ -106,6 +139,38 @@ func GetArchiveAtPath(ipfs *core.IpfsNode, path string) (io.Reader, error) {
 	return archive.VirtualReader(), nil
 }
 
+// AddPathToArchive adds each directory link under an ipfs path to an archive
+// NOTE: same depth limit as GetArchiveAtPath
+func AddPathToArchive(ipfs *core.IpfsNode, path string, archive *client.Archive) error {
+	ip, err := coreapi.ParsePath(path)
+	if err != nil {
+		return err
+	}
+
+	api := coreapi.NewCoreAPI(ipfs)
+	ctx, cancel := context.WithTimeout(ipfs.Context(), catTimeout)
+	defer cancel()
+	links, err := api.Unixfs().Ls(ctx, ip)
+	if err != nil {
+		return err
+	}
+	return addLinksToArchive(ipfs, links, archive)
+}
+
+// addLinksToArchive adds the data behind each link to an archive
+func addLinksToArchive(ipfs *core.IpfsNode, links []*ipld.Link, archive *client.Archive) error {
+	for _, link := range links {
+		data, err := GetDataAtPath(ipfs, link.Cid.Hash().B58String())
+		if err != nil {
+			return err
+		}
+		if err := archive.AddFile(data, link.Name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // PrintSwarmAddrs prints the addresses of the host
 func PrintSwarmAddrs(node *core.IpfsNode) error {
 	var lisAddrs []string
@@ -208,6 +273,19 @@ func AddFileToDirectory(ipfs *core.IpfsNode, dirb *uio.Directory, reader io.Read
 	return nil
 }
 
+// AddLinkToDirectory adds an existing node by id as a link in a virtual directory (dag) structure
+func AddLinkToDirectory(ipfs *core.IpfsNode, dirb *uio.Directory, id string, lname string) error {
+	lid, err := cid.Decode(id)
+	if err != nil {
+		return err
+	}
+	node, err := ipfs.DAG.Get(ipfs.Context(), lid)
+	if err != nil {
+		return err
+	}
+	return dirb.AddChild(ipfs.Context(), lname, node)
+}
+
 // Data pins
 func PinData(ipfs *core.IpfsNode, data io.Reader) (*cid.Cid, error) {
 	ctx, cancel := context.WithTimeout(ipfs.Context(), pinTimeout)
@@ -104,6 +104,22 @@ func MakeMetadata(reader io.Reader, path string, ext string, format Format, tnFo
 
 // MakeThumbnail creates a jpeg|gif thumbnail from an image
 func MakeThumbnail(reader io.Reader, format Format, width int) ([]byte, error) {
+	return makeThumbnail(reader, format, width, false)
+}
+
+// MakeSquareThumbnail creates a center cropped jpeg|gif square thumbnail from an image
+func MakeSquareThumbnail(reader io.Reader, format Format, size int) ([]byte, error) {
+	return makeThumbnail(reader, format, size, true)
+}
+
+// makeThumbnail resizes an image to width, optionally cropping to a square
+func makeThumbnail(reader io.Reader, format Format, width int, square bool) ([]byte, error) {
+	resize := func(img image.Image, filter imaging.ResampleFilter) *image.NRGBA {
+		if square {
+			return imaging.Fill(img, width, width, imaging.Center, filter)
+		}
+		return imaging.Resize(img, width, 0, filter)
+	}
 	var result []byte
 	switch format {
 	case JPEG:
@@ -111,7 +127,7 @@ func MakeThumbnail(reader io.Reader, format Format, width int) ([]byte, error) {
 		if err != nil {
 			return nil, err
 		}
-		thumb := imaging.Resize(img, width, 0, imaging.Lanczos)
+		thumb := resize(img, imaging.Lanczos)
 		buff := new(bytes.Buffer)
 		if err = jpeg.Encode(buff, thumb, nil); err != nil {
 			return nil, err
@@ -128,11 +144,15 @@ func MakeThumbnail(reader io.Reader, format Format, width int) ([]byte, error) {
 		for index, frame := range img.Image {
 			bounds := frame.Bounds()
 			draw.Draw(rgba, bounds, frame, bounds.Min, draw.Over)
-			img.Image[index] = imageToPaletted(imaging.Resize(rgba, width, 0, imaging.Box))
+			img.Image[index] = imageToPaletted(resize(rgba, imaging.Box))
 		}
 		aspect := float64(img.Config.Width) / float64(img.Config.Height)
 		img.Config.Width = width
-		img.Config.Height = int(float64(width) / aspect)
+		if square {
+			img.Config.Height = width
+		} else {
+			img.Config.Height = int(float64(width) / aspect)
+		}
 		buff := new(bytes.Buffer)
 		if err = gif.EncodeAll(buff, img); err != nil {
 			return nil, err
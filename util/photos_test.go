@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"fmt"
 	"github.com/textileio/textile-go/wallet/model"
+	"image"
 	"io/ioutil"
 	"os"
 	"path/filepath"
@@ -155,3 +156,34 @@ func Test_MakeThumbnail(t *testing.T) {
 		}
 	}
 }
+
+func Test_MakeSquareThumbnail(t *testing.T) {
+	for _, i := range images {
+		file, err := os.Open(i.path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		thumbFormat := JPEG
+		if i.format == "gif" {
+			thumbFormat = GIF
+		}
+		fileb, err := ioutil.ReadAll(file)
+		file.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		thumb, err := MakeSquareThumbnail(bytes.NewReader(fileb), thumbFormat, 100)
+		if err != nil {
+			t.Fatal(err)
+		}
+		img, _, err := image.Decode(bytes.NewReader(thumb))
+		if err != nil {
+			t.Fatal(err)
+		}
+		size := img.Bounds().Size()
+		if size.X != 100 || size.Y != 100 {
+			t.Errorf("bad square thumbnail size: %dx%d", size.X, size.Y)
+		}
+	}
+}
@@ -97,3 +97,28 @@ func Pin(reader io.Reader, token string, cType string) (int, *models.Response, e
 	}
 	return res.StatusCode, resp, nil
 }
+
+func AddPushToken(token interface{}, accessToken string) (int, error) {
+	return postJSON("push", token, accessToken)
+}
+
+func StoreMessage(msg interface{}, accessToken string) (int, error) {
+	return postJSON("messages", msg, accessToken)
+}
+
+func postJSON(path string, body interface{}, token string) (int, error) {
+	url := fmt.Sprintf("%s/api/v0/%s", CafeAddr, path)
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return 0, err
+	}
+	req, err := http.NewRequest("POST", url, bytes.NewBuffer(payload))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", token))
+	res, err := client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer res.Body.Close()
+	return res.StatusCode, nil
+}
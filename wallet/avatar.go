@@ -0,0 +1,99 @@
+package wallet
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/textileio/textile-go/util"
+	"github.com/textileio/textile-go/wallet/model"
+	uio "gx/ipfs/Qmb8jW1F6ZVyYPW1epc2GFRipmd3S8tJ48pZKBVPzVqj9T/go-ipfs/unixfs/io"
+	"os"
+	"strconv"
+)
+
+// SetAvatar creates square avatar renditions from an image, adds them unencrypted
+// as a public directory, and links it from our profile. Returns the directory id.
+func (w *Wallet) SetAvatar(path string) (string, error) {
+	if err := w.touchDatastore(); err != nil {
+		return "", err
+	}
+
+	// read file from disk
+	file, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	// decode image
+	reader, format, _, err := util.DecodeImage(file)
+	if err != nil {
+		return "", err
+	}
+	var avatarFormat util.Format
+	if *format == util.GIF {
+		avatarFormat = util.GIF
+	} else {
+		avatarFormat = util.JPEG
+	}
+
+	// create a virtual directory with a file per size
+	dirb := uio.NewDirectory(w.ipfs.DAG)
+	for _, size := range model.AvatarSizes {
+		if _, err := reader.Seek(0, 0); err != nil {
+			return "", err
+		}
+		data, err := util.MakeSquareThumbnail(reader, avatarFormat, size)
+		if err != nil {
+			return "", err
+		}
+		if err := util.AddFileToDirectory(w.ipfs, dirb, bytes.NewReader(data), strconv.Itoa(size)); err != nil {
+			return "", err
+		}
+	}
+
+	// pin the directory locally
+	dir, err := dirb.GetNode()
+	if err != nil {
+		return "", err
+	}
+	if err := util.PinDirectory(w.ipfs, dir, []string{}); err != nil {
+		return "", err
+	}
+	id := dir.Cid().Hash().B58String()
+
+	// request cafe pin
+	go func() {
+		if err := w.putPinRequest(id); err != nil {
+			// TODO: #202 (Properly handle database/sql errors)
+			log.Warningf("pin request exists: %s", id)
+		}
+	}()
+
+	// update
+	if err := w.datastore.Profile().SetAvatar(id); err != nil {
+		return "", err
+	}
+	if w.cafeAddr != "" {
+		// older peers only understand a link
+		link := fmt.Sprintf("%s/ipfs/%s/%d", w.cafeAddr, id, model.DefaultAvatarSize)
+		if err := w.datastore.Profile().SetAvatarId(link); err != nil {
+			return "", err
+		}
+	}
+
+	go func() {
+		<-w.Online()
+		if _, err := w.PublishProfile(nil); err != nil {
+			log.Errorf("error publishing profile (set avatar): %s", err)
+		}
+	}()
+	return id, nil
+}
+
+// GetAvatar returns the id of our public avatar directory
+func (w *Wallet) GetAvatar() (string, error) {
+	if err := w.touchDatastore(); err != nil {
+		return "", err
+	}
+	return w.datastore.Profile().GetAvatar()
+}
@@ -0,0 +1,220 @@
+package wallet
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/textileio/textile-go/crypto"
+	trepo "github.com/textileio/textile-go/repo"
+	"github.com/textileio/textile-go/util"
+	"github.com/textileio/textile-go/wallet/model"
+	"github.com/textileio/textile-go/wallet/thread"
+	libp2pc "gx/ipfs/QmaPbCnUMBohSGo3KnxEa2bHqyJVVeEEcwtqJAYxerieBo/go-libp2p-crypto"
+	"strings"
+	"time"
+)
+
+const backupVersion = 1
+
+// backupFrequency is how often desktop nodes republish the profile (and backup)
+var backupFrequency = time.Hour * 6
+
+var ErrNoBackup = errors.New("no account backup found")
+
+// ErrLegacyIdentity is returned for accounts created before identities were derived from the mnemonic.
+// Their peer identity is random, so the mnemonic can't restore it and a backup could never be found.
+var ErrLegacyIdentity = errors.New("account identity is not derived from its mnemonic")
+
+// IsIdentityDerived returns whether or not the account identity can be restored from its mnemonic
+func (w *Wallet) IsIdentityDerived() bool {
+	return w.datastore.Config().IsIdentityDerived()
+}
+
+// RecoverResult summarizes what was restored from an account backup
+type RecoverResult struct {
+	Threads  int       `json:"threads"`
+	Devices  int       `json:"devices"`
+	Username string    `json:"username,omitempty"`
+	Created  time.Time `json:"created"`
+}
+
+// Backup returns a snapshot of threads, devices and profile, encrypted with the account key
+func (w *Wallet) Backup() ([]byte, error) {
+	if !w.IsIdentityDerived() {
+		return nil, ErrLegacyIdentity
+	}
+	backup := &model.AccountBackup{
+		Version:    backupVersion,
+		KeyIndexes: make(map[string]int),
+		Created:    time.Now(),
+	}
+	for _, mod := range w.datastore.Threads().List("") {
+		backup.Threads = append(backup.Threads, model.ThreadBackup{
+			Id:      mod.Id,
+			Name:    mod.Name,
+			PrivKey: mod.PrivKey,
+			Head:    mod.Head,
+		})
+	}
+	for _, dev := range w.datastore.Devices().List("") {
+		backup.Devices = append(backup.Devices, model.DeviceBackup{Id: dev.Id, Name: dev.Name, Attestation: dev.Attestation})
+	}
+	// derivation indexes are account-wide, so a recovered install doesn't reuse them
+	purpose := util.ThreadKey.String()
+	backup.KeyIndexes[purpose] = w.datastore.Config().GetKeyIndex(purpose)
+	backup.Username, _ = w.datastore.Profile().GetUsername()
+	backup.AvatarId, _ = w.datastore.Profile().GetAvatarId()
+	backup.Avatar, _ = w.datastore.Profile().GetAvatar()
+
+	plain, err := json.Marshal(backup)
+	if err != nil {
+		return nil, err
+	}
+	pk, err := w.GetPubKey()
+	if err != nil {
+		return nil, err
+	}
+	return crypto.Encrypt(pk, plain)
+}
+
+// Recover restores threads, devices and avatar from the account backup published with
+// our profile, then re-syncs each restored thread's history from its head in the background.
+// The account must have been created (or restored) from the same mnemonic, legacy accounts
+// with a random identity are refused with ErrLegacyIdentity.
+func (w *Wallet) Recover() (*RecoverResult, error) {
+	if !w.IsIdentityDerived() {
+		return nil, ErrLegacyIdentity
+	}
+	if !w.IsOnline() {
+		return nil, ErrOffline
+	}
+	backup, err := w.fetchBackup()
+	if err != nil {
+		return nil, err
+	}
+	result := &RecoverResult{Username: backup.Username, Created: backup.Created}
+
+	// restore avatar, username requires a cafe login
+	if backup.AvatarId != "" {
+		if avatarId, _ := w.datastore.Profile().GetAvatarId(); avatarId == "" {
+			if err := w.datastore.Profile().SetAvatarId(backup.AvatarId); err != nil {
+				log.Errorf("error restoring avatar: %s", err)
+			}
+		}
+	}
+
+	if backup.Avatar != "" {
+		if avatar, _ := w.datastore.Profile().GetAvatar(); avatar == "" {
+			if err := w.datastore.Profile().SetAvatar(backup.Avatar); err != nil {
+				log.Errorf("error restoring avatar: %s", err)
+			}
+		}
+	}
+
+	// skip derivation indexes already used by the account
+	for purpose, next := range backup.KeyIndexes {
+		if err := w.datastore.Config().ReserveKeyIndexes(purpose, next); err != nil {
+			return result, err
+		}
+	}
+
+	// restore threads before devices so the devices are not re-invited
+	for _, tb := range backup.Threads {
+		if _, loaded := w.GetThread(tb.Id); loaded != nil {
+			continue
+		}
+		sk, err := libp2pc.UnmarshalPrivateKey(tb.PrivKey)
+		if err != nil {
+			return result, err
+		}
+		thrd, err := w.AddThread(tb.Name, sk)
+		if err != nil {
+			return result, err
+		}
+		result.Threads++
+		if tb.Head == "" {
+			continue
+		}
+
+		// set head first so an interrupted restore is picked up by the next sync
+		if err := w.datastore.Threads().UpdateHead(thrd.Id, tb.Head); err != nil {
+			return result, err
+		}
+		go w.restoreHistory(thrd, tb.Head)
+	}
+
+	// restore devices
+	for _, dev := range backup.Devices {
+		if w.datastore.Devices().Get(dev.Id) != nil {
+			continue
+		}
+		if err := w.datastore.Devices().Add(&trepo.Device{Id: dev.Id, Name: dev.Name, Attestation: dev.Attestation}); err != nil {
+			return result, err
+		}
+		w.sendUpdate(Update{Id: dev.Id, Name: dev.Name, Type: DeviceAdded})
+		result.Devices++
+	}
+
+	log.Infof("recovered %d threads and %d devices from backup", result.Threads, result.Devices)
+
+	return result, nil
+}
+
+// fetchBackup resolves our own profile and decrypts the backup within it
+func (w *Wallet) fetchBackup() (*model.AccountBackup, error) {
+	pid, err := w.GetId()
+	if err != nil {
+		return nil, err
+	}
+	entry, err := w.ResolveProfile(pid)
+	if err != nil {
+		log.Errorf("error resolving profile for backup: %s", err)
+		return nil, ErrNoBackup
+	}
+	ciphertext, err := util.GetDataAtPath(w.ipfs, fmt.Sprintf("%s/%s", entry.String(), "backup"))
+	if err != nil {
+		return nil, ErrNoBackup
+	}
+	sk, err := w.GetPrivKey()
+	if err != nil {
+		return nil, err
+	}
+	plain, err := crypto.Decrypt(sk, ciphertext)
+	if err != nil {
+		return nil, err
+	}
+	backup := new(model.AccountBackup)
+	if err := json.Unmarshal(plain, backup); err != nil {
+		return nil, err
+	}
+	if backup.Version > backupVersion {
+		return nil, errors.New(fmt.Sprintf("unsupported backup version: %d", backup.Version))
+	}
+	return backup, nil
+}
+
+// restoreHistory downloads and indexes a thread's blocks behind head
+func (w *Wallet) restoreHistory(thrd *thread.Thread, head string) {
+	if err := thrd.FollowParents(strings.Split(head, ",")); err != nil {
+		log.Errorf("error restoring history for thread %s: %s", thrd.Id, err)
+		return
+	}
+	log.Debugf("restored history for thread %s", thrd.Id)
+}
+
+// runBackups periodically republishes the profile, which carries the account backup
+func (w *Wallet) runBackups() {
+	tick := time.NewTicker(backupFrequency)
+	defer tick.Stop()
+	done := w.Done()
+	for {
+		select {
+		case <-tick.C:
+			if _, err := w.PublishProfile(nil); err != nil {
+				log.Errorf("error publishing backup: %s", err)
+			}
+		case <-done:
+			return
+		}
+	}
+}
@@ -0,0 +1,107 @@
+package wallet
+
+import (
+	"errors"
+	"fmt"
+	trepo "github.com/textileio/textile-go/repo"
+	"gx/ipfs/QmZoWKhxUmZ2seW4BzX6fJkNR8hh9PsGModr7q171yq2SS/go-libp2p-peer"
+	mh "gx/ipfs/QmZyZDi491cCNTLfAhwcaDii2Kg4pwKRkhqQzURGDvY6ua/go-multihash"
+	libp2pc "gx/ipfs/QmaPbCnUMBohSGo3KnxEa2bHqyJVVeEEcwtqJAYxerieBo/go-libp2p-crypto"
+	"time"
+)
+
+var ErrContactNotFound = errors.New("contact not found")
+
+// Contacts lists known contacts, most recently seen first
+func (w *Wallet) Contacts() []trepo.Contact {
+	return w.datastore.Contacts().List("")
+}
+
+// GetContact returns a contact by peer id
+func (w *Wallet) GetContact(id string) *trepo.Contact {
+	return w.datastore.Contacts().Get(id)
+}
+
+// AddContact adds a contact by public key, its profile is resolved in the background
+func (w *Wallet) AddContact(pk libp2pc.PubKey) (*trepo.Contact, error) {
+	pkb, err := pk.Bytes()
+	if err != nil {
+		return nil, err
+	}
+	pid, err := peer.IDFromPublicKey(pk)
+	if err != nil {
+		return nil, err
+	}
+	if pid == w.ipfs.Identity {
+		return nil, errors.New("cannot add self as a contact")
+	}
+	if contact := w.datastore.Contacts().Get(pid.Pretty()); contact != nil {
+		return contact, nil
+	}
+	contact := &trepo.Contact{
+		Id:     pid.Pretty(),
+		PubKey: pkb,
+		Added:  time.Now(),
+	}
+	if err := w.datastore.Contacts().Add(contact); err != nil {
+		return nil, err
+	}
+	go func() {
+		if _, err := w.RefreshContact(contact.Id); err != nil {
+			log.Debugf("error refreshing contact %s: %s", contact.Id, err)
+		}
+	}()
+	return contact, nil
+}
+
+// RemoveContact removes a contact, it will be re-added if seen in a thread again
+func (w *Wallet) RemoveContact(id string) error {
+	if w.datastore.Contacts().Get(id) == nil {
+		return ErrContactNotFound
+	}
+	return w.datastore.Contacts().Delete(id)
+}
+
+// RefreshContact resolves a contact's profile, updating its username and avatar
+func (w *Wallet) RefreshContact(id string) (*trepo.Contact, error) {
+	if w.datastore.Contacts().Get(id) == nil {
+		return nil, ErrContactNotFound
+	}
+	if _, err := w.getProfile(id, true); err != nil {
+		return nil, err
+	}
+	return w.datastore.Contacts().Get(id), nil
+}
+
+// ResolveContact finds a contact by peer id or username
+func (w *Wallet) ResolveContact(query string) (*trepo.Contact, error) {
+	if contact := w.datastore.Contacts().Get(query); contact != nil {
+		return contact, nil
+	}
+	if contact := w.datastore.Contacts().GetByUsername(query); contact != nil {
+		return contact, nil
+	}
+	return nil, ErrContactNotFound
+}
+
+// InviteContact invites a contact, by peer id or username, to a thread.
+// Unknown usernames are looked up in the cafe directory.
+func (w *Wallet) InviteContact(threadId string, query string) (mh.Multihash, error) {
+	_, thrd := w.GetThread(threadId)
+	if thrd == nil {
+		return nil, errors.New(fmt.Sprintf("could not find thread: %s", threadId))
+	}
+	contact, err := w.ResolveContact(query)
+	if err == ErrContactNotFound && w.cafeAddr != "" {
+		// try the cafe directory
+		contact, err = w.FindContact(query)
+	}
+	if err != nil {
+		return nil, err
+	}
+	pk, err := libp2pc.UnmarshalPublicKey(contact.PubKey)
+	if err != nil {
+		return nil, err
+	}
+	return thrd.AddInvite(pk)
+}
@@ -1,8 +1,12 @@
 package wallet
 
 import (
+	"context"
 	"errors"
+	"github.com/textileio/textile-go/pb"
 	trepo "github.com/textileio/textile-go/repo"
+	"github.com/textileio/textile-go/wallet/thread"
+	"gx/ipfs/QmZoWKhxUmZ2seW4BzX6fJkNR8hh9PsGModr7q171yq2SS/go-libp2p-peer"
 	libp2pc "gx/ipfs/QmaPbCnUMBohSGo3KnxEa2bHqyJVVeEEcwtqJAYxerieBo/go-libp2p-crypto"
 )
 
@@ -11,57 +15,114 @@ func (w *Wallet) Devices() []trepo.Device {
 	return w.datastore.Devices().List("")
 }
 
-// AddDevice creates an invite for every current and future thread
+// AddDevice adds a device by public key without a pairing handshake and
+// creates an invite for every current and future thread
 func (w *Wallet) AddDevice(name string, pk libp2pc.PubKey) error {
 	if !w.IsOnline() {
 		return ErrOffline
 	}
+	_, err := w.addDevice(name, pk, nil)
+	return err
+}
 
-	// index a new device
-	pkb, err := pk.Bytes()
+// RemoveDevice removes a device, notifies it of the revocation, and re-keys
+// every thread, since the device was invited to all of them
+func (w *Wallet) RemoveDevice(id string) error {
+	if !w.IsOnline() {
+		return ErrOffline
+	}
+
+	device := w.datastore.Devices().Get(id)
+	if device == nil {
+		return errors.New("device not found")
+	}
+	pkb, err := libp2pc.ConfigDecodeKey(device.Id)
+	if err != nil {
+		return err
+	}
+	pk, err := libp2pc.UnmarshalPublicKey(pkb)
 	if err != nil {
 		return err
 	}
+	pid, err := peer.IDFromPublicKey(pk)
+	if err != nil {
+		return err
+	}
+	if err := w.datastore.Devices().Delete(id); err != nil {
+		return err
+	}
+	log.Infof("removed device '%s'", id)
+
+	// let the device know so it stops inviting us, a lost device won't care
+	env, err := w.NewEnvelope(&pb.Message{Type: pb.Message_DEVICE_REVOKE})
+	if err != nil {
+		return err
+	}
+	go func() {
+		if err := w.service.SendMessage(context.Background(), pid, env); err != nil {
+			log.Debugf("error sending device revoke to %s: %s", pid.Pretty(), err)
+		}
+	}()
+
+	// move each thread to a new key the device does not have
+	threads := make([]*thread.Thread, len(w.threads))
+	copy(threads, w.threads)
+	for _, thrd := range threads {
+		if _, err := w.rekeyThread(thrd, pid); err != nil {
+			log.Errorf("error re-keying thread %s: %s", thrd.Id, err)
+		}
+	}
+
+	// notify listeners
+	w.sendUpdate(Update{Id: device.Id, Name: device.Name, Type: DeviceRemoved})
+
+	return nil
+}
+
+// addDevice indexes a device along with its attestation of us and invites it to existing threads
+func (w *Wallet) addDevice(name string, pk libp2pc.PubKey, attestation []byte) (*trepo.Device, error) {
+	pkb, err := pk.Bytes()
+	if err != nil {
+		return nil, err
+	}
 	deviceModel := &trepo.Device{
-		Id:   libp2pc.ConfigEncodeKey(pkb),
-		Name: name,
+		Id:          libp2pc.ConfigEncodeKey(pkb),
+		Name:        name,
+		Attestation: attestation,
 	}
 	if err := w.datastore.Devices().Add(deviceModel); err != nil {
-		return err
+		return nil, err
 	}
 	log.Infof("added device '%s'", name)
 
-	// invite device to existing threads
+	// invite device to existing threads, new threads invite all devices when added
 	for _, thrd := range w.threads {
 		if _, err := thrd.AddInvite(pk); err != nil {
-			return err
+			return nil, err
 		}
 	}
 
 	// notify listeners
 	w.sendUpdate(Update{Id: deviceModel.Id, Name: deviceModel.Name, Type: DeviceAdded})
 
-	return nil
+	return deviceModel, nil
 }
 
-// RemoveDevice removes a device
-func (w *Wallet) RemoveDevice(id string) error {
-	if !w.IsOnline() {
-		return ErrOffline
+// handleDeviceRevoke removes a device that revoked us, its threads are re-keyed on its end
+func (w *Wallet) handleDeviceRevoke(from libp2pc.PubKey) error {
+	pkb, err := from.Bytes()
+	if err != nil {
+		return err
 	}
-
-	device := w.datastore.Devices().Get(id)
+	device := w.datastore.Devices().Get(libp2pc.ConfigEncodeKey(pkb))
 	if device == nil {
-		return errors.New("device not found")
+		return nil
 	}
-	if err := w.datastore.Devices().Delete(id); err != nil {
+	if err := w.datastore.Devices().Delete(device.Id); err != nil {
 		return err
 	}
-	log.Infof("removed device '%s'", id)
-
-	// TODO: uninvite?
+	log.Infof("device '%s' revoked this device", device.Name)
 
-	// notify listeners
 	w.sendUpdate(Update{Id: device.Id, Name: device.Name, Type: DeviceRemoved})
 
 	return nil
@@ -0,0 +1,123 @@
+package wallet
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"github.com/textileio/textile-go/cafe"
+	cmodels "github.com/textileio/textile-go/cafe/models"
+	"github.com/textileio/textile-go/core/cafe"
+	trepo "github.com/textileio/textile-go/repo"
+	libp2pc "gx/ipfs/QmaPbCnUMBohSGo3KnxEa2bHqyJVVeEEcwtqJAYxerieBo/go-libp2p-crypto"
+	neturl "net/url"
+)
+
+// SetDirectoryVisibility publishes this peer under our cafe username, controlling
+// whether others can find us by username, by verified identities as well, or not at all
+func (w *Wallet) SetDirectoryVisibility(visibility cmodels.DirectoryVisibility) error {
+	tokens, err := w.GetTokens()
+	if err != nil {
+		return err
+	}
+	if tokens == nil {
+		return ErrNotSignedIn
+	}
+	username, err := w.GetUsername()
+	if err != nil {
+		return ErrNotSignedIn
+	}
+	id, err := w.GetId()
+	if err != nil {
+		return err
+	}
+	pk, err := w.GetPubKeyString()
+	if err != nil {
+		return err
+	}
+	entry := &cmodels.DirectoryEntry{
+		Username:   username,
+		PeerId:     id,
+		PubKey:     pk,
+		Visibility: visibility,
+	}
+	sig, err := w.ipfs.PrivateKey.Sign(entry.SigningBytes())
+	if err != nil {
+		return err
+	}
+	entry.Sig = base64.StdEncoding.EncodeToString(sig)
+
+	res, err := client.PublishDirectoryEntry(tokens.Access, entry, fmt.Sprintf("%s/directory", w.GetCafeAddr()))
+	if err != nil {
+		log.Errorf("publish directory entry error: %s", err)
+		return err
+	}
+	if res.Error != nil {
+		log.Errorf("publish directory entry error from cafe: %s", *res.Error)
+		return errors.New(*res.Error)
+	}
+	return nil
+}
+
+// LeaveDirectory removes our entry from the cafe directory
+func (w *Wallet) LeaveDirectory() error {
+	tokens, err := w.GetTokens()
+	if err != nil {
+		return err
+	}
+	if tokens == nil {
+		return ErrNotSignedIn
+	}
+	res, err := client.RemoveDirectoryEntry(tokens.Access, fmt.Sprintf("%s/directory", w.GetCafeAddr()))
+	if err != nil {
+		log.Errorf("remove directory entry error: %s", err)
+		return err
+	}
+	if res.Error != nil {
+		log.Errorf("remove directory entry error from cafe: %s", *res.Error)
+		return errors.New(*res.Error)
+	}
+	return nil
+}
+
+// FindContact looks up a username in the cafe directory and adds the peer as a contact
+func (w *Wallet) FindContact(username string) (*trepo.Contact, error) {
+	tokens, err := w.GetTokens()
+	if err != nil {
+		return nil, err
+	}
+	if tokens == nil {
+		return nil, ErrNotSignedIn
+	}
+	query := neturl.Values{}
+	query.Set("username", username)
+	res, err := client.FindDirectoryEntry(tokens.Access, query, fmt.Sprintf("%s/directory", w.GetCafeAddr()))
+	if err != nil {
+		log.Errorf("find directory entry error: %s", err)
+		return nil, err
+	}
+	if res.Error != nil || res.Entry == nil {
+		return nil, ErrContactNotFound
+	}
+
+	// don't take the cafe's word for it
+	if res.Entry.Username != username || !cafe.VerifyDirectoryEntry(res.Entry) {
+		return nil, errors.New("invalid directory entry")
+	}
+	pkb, err := libp2pc.ConfigDecodeKey(res.Entry.PubKey)
+	if err != nil {
+		return nil, err
+	}
+	pk, err := libp2pc.UnmarshalPublicKey(pkb)
+	if err != nil {
+		return nil, err
+	}
+	contact, err := w.AddContact(pk)
+	if err != nil {
+		return nil, err
+	}
+	if err := w.datastore.Contacts().UpdateProfile(contact.Id, username, contact.AvatarId); err != nil {
+		return nil, err
+	}
+	contact.Username = username
+	return contact, nil
+}
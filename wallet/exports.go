@@ -0,0 +1,215 @@
+package wallet
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	trepo "github.com/textileio/textile-go/repo"
+	"github.com/textileio/textile-go/util"
+	"github.com/textileio/textile-go/wallet/thread"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// ExportProgress is reported as each photo in an export finishes
+type ExportProgress struct {
+	ThreadId string `json:"thread_id"`
+	DataId   string `json:"data_id"`
+	Path     string `json:"path,omitempty"`
+	Error    string `json:"error,omitempty"`
+	Done     int    `json:"done"`
+	Total    int    `json:"total"`
+}
+
+// ExportResult summarizes an export
+type ExportResult struct {
+	Total    int               `json:"total"`
+	Exported int               `json:"exported"`
+	Errors   map[string]string `json:"errors,omitempty"`
+}
+
+// ExportedPhoto is the json sidecar written next to each exported photo
+type ExportedPhoto struct {
+	Id             string     `json:"id"`
+	BlockId        string     `json:"block_id"`
+	ThreadId       string     `json:"thread_id"`
+	Caption        string     `json:"caption,omitempty"`
+	AuthorId       string     `json:"author_id"`
+	AuthorUsername string     `json:"author_username,omitempty"`
+	Date           time.Time  `json:"date"`
+	Taken          *time.Time `json:"taken,omitempty"`
+}
+
+// ExportPhotos decrypts every photo in a thread into dir, named as they were added.
+// Existing files are never replaced, clashing names get a counter instead.
+// File times are set to when the photo was taken, if known, and each photo gets a json sidecar.
+func (w *Wallet) ExportPhotos(threadId string, dir string, progress func(*ExportProgress)) (*ExportResult, error) {
+	_, thrd := w.GetThread(threadId)
+	if thrd == nil {
+		return nil, errors.New(fmt.Sprintf("could not find thread: %s", threadId))
+	}
+	if err := os.MkdirAll(dir, os.ModePerm); err != nil {
+		return nil, err
+	}
+
+	photos := thrd.Blocks("", -1, trepo.PhotoBlock)
+	result := &ExportResult{Total: len(photos), Errors: make(map[string]string)}
+	for i := len(photos) - 1; i >= 0; i-- {
+		block := photos[i]
+		prog := &ExportProgress{ThreadId: thrd.Id, DataId: block.DataId, Total: result.Total}
+		path, err := w.exportPhoto(thrd, &block, dir)
+		if err != nil {
+			prog.Error = err.Error()
+			result.Errors[block.DataId] = prog.Error
+		} else {
+			prog.Path = path
+			result.Exported++
+		}
+		prog.Done = result.Exported + len(result.Errors)
+		if progress != nil {
+			progress(prog)
+		}
+	}
+
+	log.Infof("exported %d photos from %s to %s, %d errors", result.Exported, thrd.Id, dir, len(result.Errors))
+
+	return result, nil
+}
+
+// exportExts are the extensions a photo may be exported with, by decoded format.
+// the first is used when the original extension doesn't match.
+var exportExts = map[string][]string{
+	string(util.JPEG): {".jpg", ".jpeg"},
+	string(util.PNG):  {".png"},
+	string(util.GIF):  {".gif"},
+}
+
+// exportPhoto writes a single decrypted photo and its sidecar, returning the photo path
+func (w *Wallet) exportPhoto(thrd *thread.Thread, block *trepo.Block, dir string) (string, error) {
+	meta, err := thrd.GetPhotoMetaData(block.DataId, block)
+	if err != nil {
+		return "", err
+	}
+	ext, err := exportExt(meta.Format, meta.Ext)
+	if err != nil {
+		return "", err
+	}
+	data, err := thrd.GetBlockData(fmt.Sprintf("%s/photo", block.DataId), block)
+	if err != nil {
+		return "", err
+	}
+
+	// use the original name if it's safe, never replacing an existing file
+	name := exportName(meta.Name)
+	if name == "" {
+		name = block.DataId
+	}
+	file, path, err := createExportFile(dir, name, ext)
+	if err != nil {
+		return "", err
+	}
+	_, err = file.Write(data)
+	file.Close()
+	if err != nil {
+		os.Remove(path)
+		return "", err
+	}
+
+	// sidecar
+	authorId, err := util.IdFromEncodedPublicKey(block.AuthorPk)
+	if err != nil {
+		return "", err
+	}
+	exported := &ExportedPhoto{
+		Id:             block.DataId,
+		BlockId:        block.Id,
+		ThreadId:       thrd.Id,
+		AuthorId:       authorId.Pretty(),
+		AuthorUsername: meta.Username,
+		Date:           block.Date,
+	}
+	if !meta.Created.IsZero() {
+		taken := meta.Created
+		exported.Taken = &taken
+	}
+	if block.DataCaptionCipher != nil {
+		caption, err := thrd.Decrypt(block.DataCaptionCipher)
+		if err != nil {
+			return "", err
+		}
+		exported.Caption = string(caption)
+	}
+	sidecar, err := json.MarshalIndent(exported, "", "    ")
+	if err != nil {
+		return "", err
+	}
+	if err := ioutil.WriteFile(path+".json", sidecar, 0644); err != nil {
+		return "", err
+	}
+
+	// restore the file times, falling back to when it was added to the thread
+	mtime := block.Date
+	if exported.Taken != nil {
+		mtime = *exported.Taken
+	}
+	if err := os.Chtimes(path, mtime, mtime); err != nil {
+		return "", err
+	}
+	return path, nil
+}
+
+// exportName returns a peer-supplied name if it's safe to use as a file name, or empty
+func exportName(name string) string {
+	if name == "" || name == "." || filepath.Base(name) != name {
+		return ""
+	}
+	if strings.ContainsAny(name, `/\`) || strings.Contains(name, "..") {
+		return ""
+	}
+	return name
+}
+
+// exportExt checks a peer-supplied extension against the decoded photo format
+func exportExt(format string, ext string) (string, error) {
+	allowed, ok := exportExts[format]
+	if !ok {
+		return "", errors.New(fmt.Sprintf("unsupported photo format: %s", format))
+	}
+	for _, a := range allowed {
+		if strings.ToLower(ext) == a {
+			return ext, nil
+		}
+	}
+	return allowed[0], nil
+}
+
+// createExportFile creates a new file for name and ext in dir, adding a counter to the
+// name until neither the file nor its sidecar exist
+func createExportFile(dir string, name string, ext string) (*os.File, string, error) {
+	root, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, "", err
+	}
+	filename := name + ext
+	for n := 1; ; n++ {
+		path := filepath.Join(root, filename)
+		if filepath.Dir(path) != root {
+			return nil, "", errors.New(fmt.Sprintf("invalid export path: %s", filename))
+		}
+		filename = fmt.Sprintf("%s-%d%s", name, n, ext)
+		if _, err := os.Stat(path + ".json"); err == nil {
+			continue
+		}
+		file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+		if os.IsExist(err) {
+			continue
+		}
+		if err != nil {
+			return nil, "", err
+		}
+		return file, path, nil
+	}
+}
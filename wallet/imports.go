@@ -0,0 +1,212 @@
+package wallet
+
+import (
+	"errors"
+	"fmt"
+	trepo "github.com/textileio/textile-go/repo"
+	"github.com/textileio/textile-go/wallet/thread"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// importWorkers is how many photos are added at once during an import
+const importWorkers = 4
+
+// importExts are the file extensions picked up by an import
+var importExts = map[string]bool{".jpg": true, ".jpeg": true, ".png": true, ".gif": true}
+
+// ImportProgress is reported as each file in an import finishes
+type ImportProgress struct {
+	ThreadId string `json:"thread_id"`
+	Path     string `json:"path"`
+	DataId   string `json:"data_id,omitempty"`
+	BlockId  string `json:"block_id,omitempty"`
+	Skipped  bool   `json:"skipped,omitempty"`
+	Error    string `json:"error,omitempty"`
+	Done     int    `json:"done"`
+	Total    int    `json:"total"`
+}
+
+// ImportResult summarizes an import
+type ImportResult struct {
+	Total   int               `json:"total"`
+	Added   int               `json:"added"`
+	Skipped int               `json:"skipped"`
+	Errors  map[string]string `json:"errors,omitempty"`
+}
+
+// ImportPhotos walks a directory and adds every supported photo to a thread.
+// Imported files are recorded, so running it again resumes where it left off.
+// Files that fail are reported and left for the next run.
+func (w *Wallet) ImportPhotos(dir string, threadId string, progress func(*ImportProgress)) (*ImportResult, error) {
+	_, thrd := w.GetThread(threadId)
+	if thrd == nil {
+		return nil, errors.New(fmt.Sprintf("could not find thread: %s", threadId))
+	}
+	paths, err := importPaths(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &ImportResult{Total: len(paths), Errors: make(map[string]string)}
+	var lock sync.Mutex
+	report := func(prog *ImportProgress) {
+		lock.Lock()
+		defer lock.Unlock()
+		switch {
+		case prog.Error != "":
+			result.Errors[prog.Path] = prog.Error
+		case prog.Skipped:
+			result.Skipped++
+		default:
+			result.Added++
+		}
+		prog.ThreadId = threadId
+		prog.Done = result.Added + result.Skipped + len(result.Errors)
+		prog.Total = result.Total
+		if progress != nil {
+			progress(prog)
+		}
+	}
+
+	queue := make(chan string)
+	locks := &hashLocks{locks: make(map[string]*sync.Mutex)}
+	var wg sync.WaitGroup
+	for i := 0; i < importWorkers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for path := range queue {
+				report(w.importPhoto(thrd, path, locks))
+			}
+		}()
+	}
+	for _, path := range paths {
+		queue <- path
+	}
+	close(queue)
+	wg.Wait()
+
+	log.Infof("imported %d photos into %s, skipped %d, %d errors",
+		result.Added, thrd.Id, result.Skipped, len(result.Errors))
+
+	return result, nil
+}
+
+// hashLocks serializes work on files with the same contents
+type hashLocks struct {
+	mux   sync.Mutex
+	locks map[string]*sync.Mutex
+}
+
+// lock locks a content hash, returning the unlock func
+func (h *hashLocks) lock(hash string) func() {
+	h.mux.Lock()
+	lk, ok := h.locks[hash]
+	if !ok {
+		lk = new(sync.Mutex)
+		h.locks[hash] = lk
+	}
+	h.mux.Unlock()
+	lk.Lock()
+	return lk.Unlock
+}
+
+// importPhoto adds a single file to a thread, skipping files that are already there
+func (w *Wallet) importPhoto(thrd *thread.Thread, path string, locks *hashLocks) *ImportProgress {
+	prog := &ImportProgress{Path: path}
+
+	// already imported by an earlier run
+	if done := w.datastore.PhotoImports().Get(thrd.Id, path); done != nil {
+		prog.DataId = done.DataId
+		prog.BlockId = done.BlockId
+		prog.Skipped = true
+		return prog
+	}
+
+	// identical files are added one at a time, so later ones find the first in the thread
+	file, err := os.Open(path)
+	if err != nil {
+		prog.Error = err.Error()
+		return prog
+	}
+	hash, err := hashFile(file)
+	file.Close()
+	if err != nil {
+		prog.Error = err.Error()
+		return prog
+	}
+	unlock := locks.lock(hash)
+	defer unlock()
+
+	added, err := w.AddPhoto(path)
+	if err != nil {
+		prog.Error = err.Error()
+		return prog
+	}
+	prog.DataId = added.Id
+
+	// the same content may already be in the thread under another path
+	var existing *trepo.Block
+	for _, block := range w.datastore.Blocks().ListByDataId(thrd.Id, added.Id) {
+		if block.Type == trepo.PhotoBlock {
+			b := block
+			existing = &b
+		}
+	}
+	if existing != nil {
+		prog.BlockId = existing.Id
+		prog.Skipped = true
+	} else {
+		addr, err := thrd.AddPhoto(added.Id, "", []byte(added.Key))
+		if err != nil {
+			prog.Error = err.Error()
+			return prog
+		}
+		prog.BlockId = addr.B58String()
+	}
+
+	// record it so a resumed import can skip it
+	if err := w.datastore.PhotoImports().Add(&trepo.PhotoImport{
+		Path:     path,
+		ThreadId: thrd.Id,
+		DataId:   prog.DataId,
+		BlockId:  prog.BlockId,
+		Added:    time.Now(),
+	}); err != nil {
+		log.Warningf("error recording import of %s: %s", path, err)
+	}
+	return prog
+}
+
+// importPaths returns the supported photo files under dir, sorted by path
+func importPaths(dir string) ([]string, error) {
+	root, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, err
+	}
+	var paths []string
+	err = filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			// skip hidden directories, but not the root
+			if path != root && strings.HasPrefix(info.Name(), ".") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if importExts[strings.ToLower(filepath.Ext(path))] {
+			paths = append(paths, path)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return paths, nil
+}
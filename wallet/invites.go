@@ -0,0 +1,154 @@
+package wallet
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/textileio/textile-go/pb"
+	"github.com/textileio/textile-go/wallet/thread"
+	"gx/ipfs/QmZoWKhxUmZ2seW4BzX6fJkNR8hh9PsGModr7q171yq2SS/go-libp2p-peer"
+	mh "gx/ipfs/QmZyZDi491cCNTLfAhwcaDii2Kg4pwKRkhqQzURGDvY6ua/go-multihash"
+	libp2pc "gx/ipfs/QmaPbCnUMBohSGo3KnxEa2bHqyJVVeEEcwtqJAYxerieBo/go-libp2p-crypto"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// inviteStatusTimeout bounds asking an inviter whether an invite is still valid
+const inviteStatusTimeout = time.Second * 10
+
+var ErrInviterUnreachable = errors.New("could not reach the inviter to check the invite")
+
+// ExternalInvite is a shareable invite to a thread
+type ExternalInvite struct {
+	Id      string    `json:"id"`
+	Key     string    `json:"key"`
+	Link    string    `json:"link"`
+	Expires time.Time `json:"expires,omitempty"`
+	MaxUses int       `json:"max_uses,omitempty"`
+}
+
+// CreateExternalInvite adds an external invite to a thread, which expires after ttl
+// and can be used maxUses times (zero for no limit)
+func (w *Wallet) CreateExternalInvite(threadId string, ttl time.Duration, maxUses int) (*ExternalInvite, error) {
+	_, thrd := w.GetThread(threadId)
+	if thrd == nil {
+		return nil, errors.New(fmt.Sprintf("could not find thread: %s", threadId))
+	}
+	if ttl < 0 || maxUses < 0 {
+		return nil, errors.New("invite ttl and max uses cannot be negative")
+	}
+	var expires time.Time
+	if ttl > 0 {
+		expires = time.Now().Add(ttl)
+	}
+	addr, key, err := thrd.AddExternalInvite(expires, maxUses)
+	if err != nil {
+		return nil, err
+	}
+	return &ExternalInvite{
+		Id:      addr.B58String(),
+		Key:     string(key),
+		Link:    ExternalInviteLink(addr.B58String(), key),
+		Expires: expires,
+		MaxUses: maxUses,
+	}, nil
+}
+
+// RevokeExternalInvite revokes an external invite by block id
+func (w *Wallet) RevokeExternalInvite(blockId string) (mh.Multihash, error) {
+	block := w.datastore.Blocks().Get(blockId)
+	if block == nil {
+		return nil, thread.ErrInviteNotFound
+	}
+	_, thrd := w.GetThread(block.ThreadId)
+	if thrd == nil {
+		return nil, errors.New(fmt.Sprintf("could not find thread: %s", block.ThreadId))
+	}
+	return thrd.RevokeExternalInvite(blockId)
+}
+
+// AcceptExternalThreadInviteLink accepts an external invite link
+func (w *Wallet) AcceptExternalThreadInviteLink(link string) (mh.Multihash, error) {
+	id, key, err := ParseExternalInviteLink(link)
+	if err != nil {
+		return nil, err
+	}
+	return w.AcceptExternalThreadInvite(id, key)
+}
+
+// ExternalInviteLink returns a shareable link for an external invite, the key
+// is kept in the fragment so it's not sent along if the link is opened in a browser
+func ExternalInviteLink(id string, key []byte) string {
+	return fmt.Sprintf("textile://invite/%s#%s", id, string(key))
+}
+
+// ParseExternalInviteLink returns the invite id and key in an external invite link
+func ParseExternalInviteLink(link string) (string, []byte, error) {
+	u, err := url.Parse(strings.TrimSpace(link))
+	if err != nil {
+		return "", nil, err
+	}
+	if u.Scheme != "textile" || u.Host != "invite" {
+		return "", nil, errors.New("not an invite link")
+	}
+	id := strings.Trim(u.Path, "/")
+	if _, err := mh.FromB58String(id); err != nil {
+		return "", nil, errors.New("invalid invite id")
+	}
+	if u.Fragment == "" {
+		return "", nil, errors.New("missing invite key")
+	}
+	return id, []byte(u.Fragment), nil
+}
+
+// checkExternalInvite asks the inviter whether an invite has been revoked or used up.
+// An invite can't be accepted while the inviter is unreachable, since it may no longer be valid.
+func (w *Wallet) checkExternalInvite(inviter libp2pc.PubKey, threadId string, blockId string) error {
+	pid, err := peer.IDFromPublicKey(inviter)
+	if err != nil {
+		return err
+	}
+	payload, err := ptypes.MarshalAny(&pb.InviteStatusRequest{ThreadId: threadId, BlockId: blockId})
+	if err != nil {
+		return err
+	}
+	env, err := w.NewEnvelope(&pb.Message{Type: pb.Message_INVITE_STATUS, Payload: payload})
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), inviteStatusTimeout)
+	defer cancel()
+	res, err := w.service.SendRequest(ctx, pid, env)
+	if err != nil {
+		log.Warningf("could not check invite %s with %s: %s", blockId, pid.Pretty(), err)
+		return ErrInviterUnreachable
+	}
+
+	// verify the response came from the inviter
+	if err := w.VerifyEnvelope(res); err != nil {
+		return err
+	}
+	inviterb, err := inviter.Bytes()
+	if err != nil {
+		return err
+	}
+	if !bytes.Equal(res.Pk, inviterb) {
+		return errors.New("invite status from wrong peer")
+	}
+	status := new(pb.InviteStatus)
+	if err := ptypes.UnmarshalAny(res.Message.Payload, status); err != nil {
+		return err
+	}
+	if !status.Valid {
+		for _, known := range []error{thread.ErrInviteNotFound, thread.ErrInviteRevoked, thread.ErrInviteExpired, thread.ErrInviteUsedUp} {
+			if status.Reason == known.Error() {
+				return known
+			}
+		}
+		return errors.New(fmt.Sprintf("invalid invite: %s", status.Reason))
+	}
+	return nil
+}
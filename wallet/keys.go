@@ -0,0 +1,28 @@
+package wallet
+
+import (
+	"github.com/textileio/textile-go/util"
+	libp2pc "gx/ipfs/QmaPbCnUMBohSGo3KnxEa2bHqyJVVeEEcwtqJAYxerieBo/go-libp2p-crypto"
+)
+
+// DeriveKey returns the key for purpose at index, derived from the account key
+func (w *Wallet) DeriveKey(purpose util.KeyPurpose, index int) (libp2pc.PrivKey, error) {
+	account, err := w.GetPrivKey()
+	if err != nil {
+		return nil, err
+	}
+	return util.DeriveKey(account, purpose, uint32(index))
+}
+
+// nextDerivedKey reserves the next derivation index for purpose and returns its key
+func (w *Wallet) nextDerivedKey(purpose util.KeyPurpose) (libp2pc.PrivKey, int, error) {
+	index, err := w.datastore.Config().NextKeyIndex(purpose.String())
+	if err != nil {
+		return nil, 0, err
+	}
+	sk, err := w.DeriveKey(purpose, index)
+	if err != nil {
+		return nil, 0, err
+	}
+	return sk, index, nil
+}
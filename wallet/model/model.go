@@ -3,13 +3,52 @@ package model
 import "time"
 
 type Profile struct {
-	Id       string `json:"id"`
-	Username string `json:"username,omitempty"`
-	AvatarId string `json:"avatar_id,omitempty"`
+	Id       string            `json:"id"`
+	Username string            `json:"username,omitempty"`
+	AvatarId string            `json:"avatar_id,omitempty"`
+	Avatar   string            `json:"avatar,omitempty"`
+	Fields   map[string]string `json:"fields,omitempty"`
+	Updated  time.Time         `json:"updated,omitempty"`
+}
+
+// well-known profile fields, any other lowercase key is allowed
+const (
+	ProfileDisplayName = "display_name"
+	ProfileBio         = "bio"
+	ProfileThreads     = "threads" // comma-separated public thread ids
+	ProfileCafeAddr    = "cafe_addr"
+)
+
+// AvatarSizes are the square renditions published in a profile's avatar directory
+var AvatarSizes = []int{64, 128, 256}
+
+const DefaultAvatarSize = 128
+
+// SignedProfile is published alongside the legacy profile files so peers can verify it
+type SignedProfile struct {
+	Profile []byte `json:"profile"`
+	PubKey  []byte `json:"pk"`
+	Sig     []byte `json:"sig"`
 }
 
 const ThumbnailWidth = 300
 
+// Rendition describes a resized version of a photo stored alongside the original
+type Rendition struct {
+	Name   string `json:"name"`
+	Width  int    `json:"width"`
+	Height int    `json:"height,omitempty"`
+	Square bool   `json:"square,omitempty"`
+}
+
+// DefaultRenditions are generated for each added photo unless configured otherwise
+var DefaultRenditions = []Rendition{
+	{Name: "square", Width: 100, Square: true},
+	{Name: "small", Width: 320},
+	{Name: "medium", Width: 800},
+	{Name: "large", Width: 1600},
+}
+
 type Metadata struct {
 	Version  string    `json:"version"`
 	PeerId   string    `json:"peer_id"`
@@ -26,10 +65,45 @@ type FileMetadata struct {
 
 type PhotoMetadata struct {
 	FileMetadata
-	Format          string  `json:"format"`
-	ThumbnailFormat string  `json:"format_thumb"`
-	Width           int     `json:"width"`
-	Height          int     `json:"height"`
-	Latitude        float64 `json:"latitude,omitempty"`
-	Longitude       float64 `json:"longitude,omitempty"`
+	Format          string      `json:"format"`
+	ThumbnailFormat string      `json:"format_thumb"`
+	Width           int         `json:"width"`
+	Height          int         `json:"height"`
+	Latitude        float64     `json:"latitude,omitempty"`
+	Longitude       float64     `json:"longitude,omitempty"`
+	Renditions      []Rendition `json:"renditions,omitempty"`
+}
+
+type Notification struct {
+	Type           string `json:"type"`
+	MessageId      string `json:"message_id"`
+	ThreadId       string `json:"thread_id"`
+	ThreadName     string `json:"thread_name"`
+	AuthorId       string `json:"author_id"`
+	AuthorUsername string `json:"author_username,omitempty"`
+}
+
+// AccountBackup is a snapshot of account state, published encrypted with the profile
+type AccountBackup struct {
+	Version    int            `json:"version"`
+	Threads    []ThreadBackup `json:"threads"`
+	Devices    []DeviceBackup `json:"devices"`
+	Username   string         `json:"username,omitempty"`
+	AvatarId   string         `json:"avatar_id,omitempty"`
+	Avatar     string         `json:"avatar,omitempty"`
+	KeyIndexes map[string]int `json:"key_indexes,omitempty"`
+	Created    time.Time      `json:"created"`
+}
+
+type ThreadBackup struct {
+	Id      string `json:"id"`
+	Name    string `json:"name"`
+	PrivKey []byte `json:"sk"`
+	Head    string `json:"head"`
+}
+
+type DeviceBackup struct {
+	Id          string `json:"id"`
+	Name        string `json:"name"`
+	Attestation []byte `json:"attestation,omitempty"`
 }
@@ -121,6 +121,17 @@ func (w *Wallet) sendOfflineMessage(env *pb.Envelope, pid peer.ID, hash *string)
 		return err
 	}
 
+	// ask the cafe to wake the recipient
+	go func() {
+		messageId, err := addr.ValueForProtocol(ma.P_IPFS)
+		if err != nil {
+			return
+		}
+		if err := w.notifyOfflinePeer(pid, env, messageId); err != nil {
+			log.Debugf("notify offline peer %s failed: %s", pid.Pretty(), err)
+		}
+	}()
+
 	// create a pointer for this peer
 	mh, err := multihash.FromB58String(pid.Pretty())
 	if err != nil {
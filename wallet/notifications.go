@@ -0,0 +1,140 @@
+package wallet
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/golang/protobuf/ptypes"
+	cmodels "github.com/textileio/textile-go/cafe/models"
+	"github.com/textileio/textile-go/core/cafe"
+	"github.com/textileio/textile-go/crypto"
+	"github.com/textileio/textile-go/pb"
+	"github.com/textileio/textile-go/wallet/model"
+	"gx/ipfs/QmZoWKhxUmZ2seW4BzX6fJkNR8hh9PsGModr7q171yq2SS/go-libp2p-peer"
+)
+
+// notifiable lists the thread message types worth waking a device for
+var notifiable = map[pb.Message_Type]bool{
+	pb.Message_THREAD_INVITE:     true,
+	pb.Message_THREAD_JOIN:       true,
+	pb.Message_THREAD_DATA:       true,
+	pb.Message_THREAD_ANNOTATION: true,
+}
+
+// SetPushToken registers this device's push token with the cafe
+func (w *Wallet) SetPushToken(token string, platform string) error {
+	tokens, err := w.GetTokens()
+	if err != nil {
+		return err
+	}
+	if tokens == nil {
+		return ErrNotSignedIn
+	}
+	id, err := w.GetId()
+	if err != nil {
+		return err
+	}
+	pk, err := w.GetPubKeyString()
+	if err != nil {
+		return err
+	}
+	push := &cmodels.PushToken{
+		PeerId:   id,
+		PubKey:   pk,
+		Token:    token,
+		Platform: cmodels.PushPlatform(platform),
+	}
+	sig, err := w.ipfs.PrivateKey.Sign(push.SigningBytes())
+	if err != nil {
+		return err
+	}
+	push.Sig = base64.StdEncoding.EncodeToString(sig)
+	res, err := client.AddPushToken(tokens.Access, push, fmt.Sprintf("%s/push", w.GetCafeAddr()))
+	if err != nil {
+		log.Errorf("add push token error: %s", err)
+		return err
+	}
+	if res.Error != nil {
+		log.Errorf("add push token error from cafe: %s", *res.Error)
+		return errors.New(*res.Error)
+	}
+	return nil
+}
+
+// DecryptNotification decrypts a push notification payload delivered by the cafe
+func (w *Wallet) DecryptNotification(payload string) (*model.Notification, error) {
+	ciphertext, err := base64.StdEncoding.DecodeString(payload)
+	if err != nil {
+		return nil, err
+	}
+	plaintext, err := crypto.Decrypt(w.ipfs.PrivateKey, ciphertext)
+	if err != nil {
+		return nil, err
+	}
+	note := new(model.Notification)
+	if err := json.Unmarshal(plaintext, note); err != nil {
+		return nil, err
+	}
+	return note, nil
+}
+
+// notifyOfflinePeer tells the cafe about an offline thread message so it can wake the recipient
+func (w *Wallet) notifyOfflinePeer(pid peer.ID, env *pb.Envelope, messageId string) error {
+	if !notifiable[env.Message.Type] {
+		return nil
+	}
+	if w.pinner == nil || w.pinner.Tokens == nil {
+		return nil
+	}
+
+	// find the thread this block belongs to
+	signed := new(pb.SignedThreadBlock)
+	if err := ptypes.UnmarshalAny(env.Message.Payload, signed); err != nil {
+		return err
+	}
+	var note *model.Notification
+	for _, thrd := range w.Threads() {
+		if err := thrd.Verify(signed); err == nil {
+			note = &model.Notification{
+				Type:       env.Message.Type.String(),
+				MessageId:  messageId,
+				ThreadId:   thrd.Id,
+				ThreadName: thrd.Name,
+			}
+			break
+		}
+	}
+	if note == nil {
+		return errors.New(fmt.Sprintf("could not find thread for message %s", messageId))
+	}
+	id, err := w.GetId()
+	if err != nil {
+		return err
+	}
+	note.AuthorId = id
+	note.AuthorUsername, _ = w.GetUsername()
+
+	// only the recipient can read the payload
+	plaintext, err := json.Marshal(note)
+	if err != nil {
+		return err
+	}
+	ciphertext, err := w.encryptMessage(pid, plaintext)
+	if err != nil {
+		return err
+	}
+	msg := &cmodels.OfflineMessage{
+		PeerId:    pid.Pretty(),
+		MessageId: messageId,
+		Payload:   base64.StdEncoding.EncodeToString(ciphertext),
+	}
+	res, err := client.StoreMessage(w.pinner.Tokens.Access, msg, fmt.Sprintf("%s/messages", w.GetCafeAddr()))
+	if err != nil {
+		return err
+	}
+	if res.Error != nil {
+		return errors.New(*res.Error)
+	}
+	return nil
+}
@@ -0,0 +1,299 @@
+package wallet
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base32"
+	"errors"
+	"fmt"
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/textileio/textile-go/crypto"
+	"github.com/textileio/textile-go/pb"
+	trepo "github.com/textileio/textile-go/repo"
+	"gx/ipfs/QmZoWKhxUmZ2seW4BzX6fJkNR8hh9PsGModr7q171yq2SS/go-libp2p-peer"
+	libp2pc "gx/ipfs/QmaPbCnUMBohSGo3KnxEa2bHqyJVVeEEcwtqJAYxerieBo/go-libp2p-crypto"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+)
+
+// pairingTimeout is how long a pairing request stays valid
+var pairingTimeout = time.Minute * 10
+
+// pairTimeout bounds the pairing handshake with the new device
+const pairTimeout = time.Second * 30
+
+var ErrInvalidPairing = errors.New("invalid or expired pairing request")
+
+// PairingRequest is shown by a new device (e.g., as a QR code)
+// and scanned or typed in on a device already holding the account's threads
+type PairingRequest struct {
+	Code    string    `json:"code"`
+	Link    string    `json:"link"`
+	Expires time.Time `json:"expires"`
+}
+
+type pendingPairing struct {
+	code    string
+	expires time.Time
+}
+
+// NewPairingRequest creates a one-time pairing code, replacing any pending one
+func (w *Wallet) NewPairingRequest() (*PairingRequest, error) {
+	id, err := w.GetId()
+	if err != nil {
+		return nil, err
+	}
+	pk, err := w.GetPubKeyString()
+	if err != nil {
+		return nil, err
+	}
+	codeb := make([]byte, 10)
+	if _, err := rand.Read(codeb); err != nil {
+		return nil, err
+	}
+	code := base32.StdEncoding.EncodeToString(codeb)
+
+	pending := &pendingPairing{code: code, expires: time.Now().Add(pairingTimeout)}
+	w.pairinglk.Lock()
+	w.pairing = pending
+	w.pairinglk.Unlock()
+
+	query := url.Values{}
+	query.Set("peer", id)
+	query.Set("pk", pk)
+	query.Set("code", code)
+	return &PairingRequest{
+		Code:    code,
+		Link:    fmt.Sprintf("textile://pair?%s", query.Encode()),
+		Expires: pending.expires,
+	}, nil
+}
+
+// PairDevice completes a pairing request link from a new device, names it, and invites
+// it to every thread. Both devices sign an attestation of the other.
+func (w *Wallet) PairDevice(link string, name string) (*trepo.Device, error) {
+	if !w.IsOnline() {
+		return nil, ErrOffline
+	}
+	pid, pk, code, err := parsePairingLink(link)
+	if err != nil {
+		return nil, err
+	}
+	if pid == w.ipfs.Identity {
+		return nil, errors.New("cannot pair with self")
+	}
+	pkb, err := pk.Bytes()
+	if err != nil {
+		return nil, err
+	}
+	if w.datastore.Devices().Get(libp2pc.ConfigEncodeKey(pkb)) != nil {
+		return nil, errors.New("device already paired")
+	}
+
+	// attest to the new device, proving knowledge of the code
+	attestation, err := w.signAttestation(pk, name)
+	if err != nil {
+		return nil, err
+	}
+	payload, err := ptypes.MarshalAny(&pb.DevicePair{
+		Attestation: attestation,
+		Proof:       pairingProof(code, attestation.Attestation),
+		Name:        deviceName(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	env, err := w.NewEnvelope(&pb.Message{Type: pb.Message_DEVICE_PAIR, Payload: payload})
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), pairTimeout)
+	defer cancel()
+	res, err := w.service.SendRequest(ctx, pid, env)
+	if err != nil {
+		return nil, err
+	}
+
+	// verify the response is the new device's attestation of us
+	if err := w.VerifyEnvelope(res); err != nil {
+		return nil, err
+	}
+	if !bytes.Equal(res.Pk, pkb) {
+		return nil, errors.New("pairing response from wrong device")
+	}
+	if res.Message.Type == pb.Message_ERROR {
+		perr := new(pb.Error)
+		if err := ptypes.UnmarshalAny(res.Message.Payload, perr); err != nil {
+			return nil, err
+		}
+		return nil, errors.New(fmt.Sprintf("pairing rejected: %s", perr.Message))
+	}
+	signed := new(pb.SignedDeviceAttestation)
+	if err := ptypes.UnmarshalAny(res.Message.Payload, signed); err != nil {
+		return nil, err
+	}
+	if _, err := w.verifyAttestation(signed, pk); err != nil {
+		return nil, err
+	}
+	signedb, err := proto.Marshal(signed)
+	if err != nil {
+		return nil, err
+	}
+	return w.addDevice(name, pk, signedb)
+}
+
+// handlePairRequest checks a pairing attempt against the pending request, adds the
+// pairing device, and returns our attestation of it
+func (w *Wallet) handlePairRequest(from libp2pc.PubKey, pair *pb.DevicePair) (*pb.SignedDeviceAttestation, error) {
+	// a pending code is good for one pairing only, it's cleared once a request
+	// checks out so a bad attempt can't use it up
+	w.pairinglk.Lock()
+	pending := w.pairing
+	if pending == nil || time.Now().After(pending.expires) || pair.Attestation == nil {
+		w.pairinglk.Unlock()
+		return nil, ErrInvalidPairing
+	}
+	if !hmac.Equal(pair.Proof, pairingProof(pending.code, pair.Attestation.Attestation)) {
+		w.pairinglk.Unlock()
+		return nil, ErrInvalidPairing
+	}
+	if _, err := w.verifyAttestation(pair.Attestation, from); err != nil {
+		w.pairinglk.Unlock()
+		return nil, err
+	}
+	w.pairing = nil
+	w.pairinglk.Unlock()
+
+	// attest back
+	name := pair.Name
+	if name == "" {
+		name = "primary"
+	}
+	attestation, err := w.signAttestation(from, name)
+	if err != nil {
+		return nil, err
+	}
+	signedb, err := proto.Marshal(pair.Attestation)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := w.addDevice(name, from, signedb); err != nil {
+		return nil, err
+	}
+	return attestation, nil
+}
+
+// signAttestation signs a statement that the device pk belongs to this account
+func (w *Wallet) signAttestation(device libp2pc.PubKey, name string) (*pb.SignedDeviceAttestation, error) {
+	sk, err := w.GetPrivKey()
+	if err != nil {
+		return nil, err
+	}
+	accountPk, err := sk.GetPublic().Bytes()
+	if err != nil {
+		return nil, err
+	}
+	devicePk, err := device.Bytes()
+	if err != nil {
+		return nil, err
+	}
+	date, err := ptypes.TimestampProto(time.Now())
+	if err != nil {
+		return nil, err
+	}
+	attestation, err := proto.Marshal(&pb.DeviceAttestation{
+		AccountPk: accountPk,
+		DevicePk:  devicePk,
+		Name:      name,
+		Date:      date,
+	})
+	if err != nil {
+		return nil, err
+	}
+	sig, err := sk.Sign(attestation)
+	if err != nil {
+		return nil, err
+	}
+	return &pb.SignedDeviceAttestation{Attestation: attestation, Sig: sig}, nil
+}
+
+// verifyAttestation checks that signer attested to our key
+func (w *Wallet) verifyAttestation(signed *pb.SignedDeviceAttestation, signer libp2pc.PubKey) (*pb.DeviceAttestation, error) {
+	if err := crypto.Verify(signer, signed.Attestation, signed.Sig); err != nil {
+		return nil, err
+	}
+	attestation := new(pb.DeviceAttestation)
+	if err := proto.Unmarshal(signed.Attestation, attestation); err != nil {
+		return nil, err
+	}
+	signerb, err := signer.Bytes()
+	if err != nil {
+		return nil, err
+	}
+	pkb, err := w.ipfs.PrivateKey.GetPublic().Bytes()
+	if err != nil {
+		return nil, err
+	}
+	if !bytes.Equal(attestation.AccountPk, signerb) || !bytes.Equal(attestation.DevicePk, pkb) {
+		return nil, errors.New("attestation is not for this device")
+	}
+	return attestation, nil
+}
+
+// parsePairingLink returns the peer, key, and code in a pairing link
+func parsePairingLink(link string) (peer.ID, libp2pc.PubKey, string, error) {
+	u, err := url.Parse(link)
+	if err != nil {
+		return "", nil, "", err
+	}
+	if u.Scheme != "textile" || u.Host != "pair" {
+		return "", nil, "", errors.New("not a pairing link")
+	}
+	query := u.Query()
+	pid, err := peer.IDB58Decode(query.Get("peer"))
+	if err != nil {
+		return "", nil, "", err
+	}
+	pkb, err := libp2pc.ConfigDecodeKey(query.Get("pk"))
+	if err != nil {
+		return "", nil, "", err
+	}
+	pk, err := libp2pc.UnmarshalPublicKey(pkb)
+	if err != nil {
+		return "", nil, "", err
+	}
+	id, err := peer.IDFromPublicKey(pk)
+	if err != nil {
+		return "", nil, "", err
+	}
+	if id != pid {
+		return "", nil, "", errors.New("pairing key does not match peer")
+	}
+	code := strings.ToUpper(query.Get("code"))
+	if code == "" {
+		return "", nil, "", errors.New("missing pairing code")
+	}
+	return pid, pk, code, nil
+}
+
+// pairingProof binds an attestation to the pairing code
+func pairingProof(code string, attestation []byte) []byte {
+	mac := hmac.New(sha256.New, []byte(code))
+	mac.Write(attestation)
+	return mac.Sum(nil)
+}
+
+// deviceName is how we suggest the other side names this device
+func deviceName() string {
+	name, err := os.Hostname()
+	if err != nil || name == "" {
+		return "primary"
+	}
+	return name
+}
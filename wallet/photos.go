@@ -2,34 +2,64 @@ package wallet
 
 import (
 	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"errors"
 	"fmt"
 	cafe "github.com/textileio/textile-go/core/cafe"
 	"github.com/textileio/textile-go/crypto"
+	trepo "github.com/textileio/textile-go/repo"
 	"github.com/textileio/textile-go/util"
 	"github.com/textileio/textile-go/wallet/model"
 	"github.com/textileio/textile-go/wallet/thread"
 	uio "gx/ipfs/Qmb8jW1F6ZVyYPW1epc2GFRipmd3S8tJ48pZKBVPzVqj9T/go-ipfs/unixfs/io"
+	"io"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
+	"time"
 )
 
-// AddPhoto add a photo to the local ipfs node
+// duplicateCheckTimeout bounds loading an earlier copy of a photo before it's added again
+const duplicateCheckTimeout = time.Second * 5
+
+// AddPhoto add a photo to the local ipfs node.
+// A file that was already added is not added again, the existing id and key are returned.
 func (w *Wallet) AddPhoto(path string) (*AddDataResult, error) {
-	// get a key to encrypt with
-	key, err := crypto.GenerateAESKey()
+	// read file from disk
+	file, err := os.Open(path)
 	if err != nil {
 		return nil, err
 	}
+	defer file.Close()
 
-	// read file from disk
-	file, err := os.Open(path)
+	// check if we've added this file before
+	hash, err := hashFile(file)
+	if err != nil {
+		return nil, err
+	}
+	if dup := w.duplicatePhoto(hash); dup != nil {
+		log.Debugf("%s is a duplicate of %s", path, dup.Id)
+
+		// on mobile, archive again in case the first upload never made it
+		if w.isMobile {
+			if err := w.archivePhoto(dup); err != nil {
+				return nil, err
+			}
+		}
+		return dup, nil
+	}
+	if _, err := file.Seek(0, 0); err != nil {
+		return nil, err
+	}
+
+	// get a key to encrypt with
+	key, err := crypto.GenerateAESKey()
 	if err != nil {
 		return nil, err
 	}
-	defer file.Close()
 
 	// decode image
 	reader, format, size, err := util.DecodeImage(file)
@@ -50,6 +80,29 @@ func (w *Wallet) AddPhoto(path string) (*AddDataResult, error) {
 		return nil, err
 	}
 
+	// make renditions, skipping any that would upscale the original
+	var renditions []model.Rendition
+	var renditionData [][]byte
+	for _, r := range w.renditions {
+		if r.Width > size.X || (r.Square && r.Width > size.Y) {
+			continue
+		}
+		reader.Seek(0, 0)
+		var data []byte
+		if r.Square {
+			data, err = util.MakeSquareThumbnail(reader, thumbFormat, r.Width)
+			r.Height = r.Width
+		} else {
+			data, err = util.MakeThumbnail(reader, thumbFormat, r.Width)
+			r.Height = r.Width * size.Y / size.X
+		}
+		if err != nil {
+			return nil, err
+		}
+		renditions = append(renditions, r)
+		renditionData = append(renditionData, data)
+	}
+
 	// get some meta data
 	id, err := w.GetId()
 	if err != nil {
@@ -78,43 +131,41 @@ func (w *Wallet) AddPhoto(path string) (*AddDataResult, error) {
 	if err != nil {
 		return nil, err
 	}
+	meta.Renditions = renditions
 	metab, err := json.Marshal(meta)
 	if err != nil {
 		return nil, err
 	}
 
 	// encrypt files
-	reader.Seek(0, 0)
-	photocipher, err := util.GetEncryptedReaderBytes(reader, key)
-	if err != nil {
-		return nil, err
-	}
-	thumbcipher, err := crypto.EncryptAES(thumb, key)
-	if err != nil {
-		return nil, err
-	}
-	metacipher, err := crypto.EncryptAES(metab, key)
-	if err != nil {
-		return nil, err
-	}
-	mpkcipher, err := crypto.EncryptAES(mpkb, key)
-	if err != nil {
-		return nil, err
+	plains := []photoFile{{name: "thumb", data: thumb}, {name: "meta", data: metab}, {name: "pk", data: mpkb}}
+	for i, r := range renditions {
+		plains = append(plains, photoFile{name: r.Name, data: renditionData[i]})
+	}
+	var files []photoFile
+	for _, f := range plains {
+		cipher, err := crypto.EncryptEnvelope(f.data, key, f.name)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, photoFile{name: f.name, data: cipher})
 	}
 
-	// create a virtual directory for the photo
+	// create a virtual directory for the photo, streaming the original through encryption
 	dirb := uio.NewDirectory(w.ipfs.DAG)
-	if err := util.AddFileToDirectory(w.ipfs, dirb, bytes.NewReader(photocipher), "photo"); err != nil {
+	if _, err := reader.Seek(0, 0); err != nil {
 		return nil, err
 	}
-	if err := util.AddFileToDirectory(w.ipfs, dirb, bytes.NewReader(thumbcipher), "thumb"); err != nil {
-		return nil, err
-	}
-	if err := util.AddFileToDirectory(w.ipfs, dirb, bytes.NewReader(metacipher), "meta"); err != nil {
+	photocipher := util.NewEncryptedReader(reader, key, "photo")
+	err = util.AddFileToDirectory(w.ipfs, dirb, photocipher, "photo")
+	photocipher.Close()
+	if err != nil {
 		return nil, err
 	}
-	if err := util.AddFileToDirectory(w.ipfs, dirb, bytes.NewReader(mpkcipher), "pk"); err != nil {
-		return nil, err
+	for _, f := range files {
+		if err := util.AddFileToDirectory(w.ipfs, dirb, bytes.NewReader(f.data), f.name); err != nil {
+			return nil, err
+		}
 	}
 
 	// pin the directory
@@ -127,6 +178,20 @@ func (w *Wallet) AddPhoto(path string) (*AddDataResult, error) {
 	}
 	result := &AddDataResult{Id: dir.Cid().Hash().B58String(), Key: string(key)}
 
+	// remember the content so it's not added again, the key is only readable with the account key
+	keyCipher, err := crypto.Encrypt(mpk, key)
+	if err != nil {
+		return nil, err
+	}
+	if err := w.datastore.PhotoHashes().Add(&trepo.PhotoHash{
+		Hash:      hash,
+		DataId:    result.Id,
+		KeyCipher: keyCipher,
+		Added:     time.Now(),
+	}); err != nil {
+		log.Warningf("error indexing photo hash for %s: %s", result.Id, err)
+	}
+
 	// if not mobile, create a pin request
 	// on mobile, we let the OS handle the archive directly
 	if !w.isMobile {
@@ -136,30 +201,154 @@ func (w *Wallet) AddPhoto(path string) (*AddDataResult, error) {
 		return result, nil
 	}
 
-	// make an archive for remote pinning by the OS
+	// make an archive of the whole directory for remote pinning by the OS
+	if err := w.archivePhoto(result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// duplicatePhoto returns the earlier result for a plaintext hash, if its data is still around
+func (w *Wallet) duplicatePhoto(hash string) *AddDataResult {
+	ph := w.datastore.PhotoHashes().Get(hash)
+	if ph == nil {
+		return nil
+	}
+	forget := func(reason error) {
+		log.Debugf("photo hash %s is unusable, adding again: %s", hash, reason)
+		if err := w.datastore.PhotoHashes().Delete(hash); err != nil {
+			log.Warningf("error removing photo hash %s: %s", hash, err)
+		}
+	}
+	sk, err := w.GetPrivKey()
+	if err != nil {
+		return nil
+	}
+	key, err := crypto.Decrypt(sk, ph.KeyCipher)
+	if err != nil {
+		forget(err)
+		return nil
+	}
+	if _, err := util.GetDataAtPathWithTimeout(w.ipfs, fmt.Sprintf("%s/meta", ph.DataId), duplicateCheckTimeout); err != nil {
+		forget(err)
+		return nil
+	}
+	return &AddDataResult{Id: ph.DataId, Key: string(key), Duplicate: true}
+}
+
+// archivePhoto writes an archive of an added photo's encrypted files for remote pinning by the OS
+func (w *Wallet) archivePhoto(result *AddDataResult) error {
 	apath := filepath.Join(w.repoPath, "tmp", result.Id)
-	result.Archive, err = cafe.NewArchive(&apath)
+
+	// archives are opened for append, start over from any earlier one
+	if err := os.Remove(apath + ".tar.gz"); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	archive, err := cafe.NewArchive(&apath)
 	if err != nil {
-		return nil, err
+		return err
+	}
+	defer archive.Close()
+	if err := util.AddPathToArchive(w.ipfs, result.Id, archive); err != nil {
+		return err
 	}
-	defer result.Archive.Close()
+	result.Archive = archive
+	return nil
+}
 
-	// add files
-	if err := result.Archive.AddFile(photocipher, "photo"); err != nil {
-		return nil, err
+// hashFile returns the hex encoded sha256 of a file's contents
+func hashFile(file *os.File) (string, error) {
+	hasher := sha256.New()
+	if _, err := io.Copy(hasher, file); err != nil {
+		return "", err
 	}
-	if err := result.Archive.AddFile(thumbcipher, "thumb"); err != nil {
-		return nil, err
+	return hex.EncodeToString(hasher.Sum(nil)), nil
+}
+
+// Renditions returns the rendition sizes generated for added photos
+func (w *Wallet) Renditions() []model.Rendition {
+	return w.renditions
+}
+
+// reservedPhotoFiles are the photo directory entries a rendition can't replace
+var reservedPhotoFiles = []string{"photo", "thumb", "meta", "pk"}
+
+// ParseRenditions parses a comma separated list of renditions, each as name:width or name:width:square
+func ParseRenditions(spec string) ([]model.Rendition, error) {
+	var renditions []model.Rendition
+	for _, item := range strings.Split(spec, ",") {
+		parts := strings.Split(strings.TrimSpace(item), ":")
+		if len(parts) < 2 || len(parts) > 3 || (len(parts) == 3 && parts[2] != "square") {
+			return nil, errors.New(fmt.Sprintf("invalid rendition: %s", item))
+		}
+		width, err := strconv.Atoi(parts[1])
+		if err != nil {
+			return nil, errors.New(fmt.Sprintf("invalid rendition width: %s", item))
+		}
+		renditions = append(renditions, model.Rendition{Name: parts[0], Width: width, Square: len(parts) == 3})
 	}
-	if err := result.Archive.AddFile(metacipher, "meta"); err != nil {
+	return renditions, validateRenditions(renditions)
+}
+
+// validateRenditions checks rendition names are usable as unique, non-reserved file names
+func validateRenditions(renditions []model.Rendition) error {
+	names := make(map[string]struct{})
+	for _, r := range renditions {
+		if r.Name == "" || r.Name == "." || r.Name == ".." || strings.ContainsAny(r.Name, "/\\") {
+			return errors.New(fmt.Sprintf("invalid rendition name: %s", r.Name))
+		}
+		for _, reserved := range reservedPhotoFiles {
+			if r.Name == reserved {
+				return errors.New(fmt.Sprintf("reserved rendition name: %s", r.Name))
+			}
+		}
+		if _, ok := names[r.Name]; ok {
+			return errors.New(fmt.Sprintf("duplicate rendition name: %s", r.Name))
+		}
+		if r.Width <= 0 {
+			return errors.New(fmt.Sprintf("invalid rendition width: %d", r.Width))
+		}
+		names[r.Name] = struct{}{}
+	}
+	return nil
+}
+
+// GetPhotoReader returns a reader which decrypts a photo variant (photo, thumb, or a rendition name) as it is read.
+// Renditions are checked against the photo's own metadata, since the peer that added it may use other sizes.
+// Configured renditions the photo lacks, e.g. those larger than the original, fall back to the original.
+func (w *Wallet) GetPhotoReader(id string, variant string) (io.ReadCloser, error) {
+	block, err := w.GetBlockByDataId(id)
+	if err != nil {
 		return nil, err
 	}
-	if err := result.Archive.AddFile(mpkcipher, "pk"); err != nil {
+	thrd, err := w.getThreadByBlock(block)
+	if err != nil {
 		return nil, err
 	}
+	if variant != "photo" && variant != "thumb" {
+		meta, err := thrd.GetPhotoMetaData(id, block)
+		if err != nil {
+			return nil, err
+		}
+		if !hasRendition(meta.Renditions, variant) {
+			if !hasRendition(w.renditions, variant) {
+				return nil, errors.New(fmt.Sprintf("invalid photo variant: %s", variant))
+			}
+			log.Debugf("no %s rendition for %s, using original", variant, id)
+			variant = "photo"
+		}
+	}
+	return thrd.GetBlockReader(fmt.Sprintf("%s/%s", id, variant), block)
+}
 
-	// all done
-	return result, nil
+// hasRendition returns whether or not a rendition named name is in the list
+func hasRendition(renditions []model.Rendition, name string) bool {
+	for _, r := range renditions {
+		if r.Name == name {
+			return true
+		}
+	}
+	return false
 }
 
 // PhotoThreads lists threads which contain a photo (known to the local peer)
@@ -202,3 +391,9 @@ func (w *Wallet) GetPhotoKey(id string) (string, error) {
 	}
 	return string(key), nil
 }
+
+// photoFile is a named file in a photo directory
+type photoFile struct {
+	name string
+	data []byte
+}
@@ -3,16 +3,21 @@ package wallet
 import (
 	"bytes"
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	cmodels "github.com/textileio/textile-go/cafe/models"
 	"github.com/textileio/textile-go/core/cafe"
+	"github.com/textileio/textile-go/crypto"
 	"github.com/textileio/textile-go/repo"
 	"github.com/textileio/textile-go/util"
 	"github.com/textileio/textile-go/wallet/model"
+	"gx/ipfs/QmZoWKhxUmZ2seW4BzX6fJkNR8hh9PsGModr7q171yq2SS/go-libp2p-peer"
+	libp2pc "gx/ipfs/QmaPbCnUMBohSGo3KnxEa2bHqyJVVeEEcwtqJAYxerieBo/go-libp2p-crypto"
 	"gx/ipfs/Qmb8jW1F6ZVyYPW1epc2GFRipmd3S8tJ48pZKBVPzVqj9T/go-ipfs/namesys/opts"
 	"gx/ipfs/Qmb8jW1F6ZVyYPW1epc2GFRipmd3S8tJ48pZKBVPzVqj9T/go-ipfs/path"
 	uio "gx/ipfs/Qmb8jW1F6ZVyYPW1epc2GFRipmd3S8tJ48pZKBVPzVqj9T/go-ipfs/unixfs/io"
+	"regexp"
 	"strings"
 	"time"
 )
@@ -20,6 +25,16 @@ import (
 var profileTTL = time.Hour * 24 * 7 * 4
 var profileCacheTTL = time.Hour * 24 * 7
 
+// profileRefreshTTL is how long a cached peer profile is used before resolving it again
+var profileRefreshTTL = time.Hour
+
+// profileRefreshFrequency is how often desktop nodes refresh contact profiles
+var profileRefreshFrequency = time.Minute * 30
+
+const maxProfileFieldLen = 1024
+
+var profileFieldKey = regexp.MustCompile("^[a-z][a-z0-9_]{0,31}$")
+
 // CreateReferral requests a referral from a cafe via key
 func (w *Wallet) CreateReferral(req *cmodels.ReferralRequest) (*cmodels.ReferralResponse, error) {
 	if w.cafeAddr == "" {
@@ -260,43 +275,164 @@ func (w *Wallet) SetAvatarId(id string) error {
 	return nil
 }
 
-// GetProfile return a model representation of a peer profile
+// GetProfile return a model representation of a peer profile.
+// Remote profiles are served from the local cache until they go stale.
 func (w *Wallet) GetProfile(peerId string) (*model.Profile, error) {
+	return w.getProfile(peerId, false)
+}
+
+// getProfile optionally skips a fresh cached profile
+func (w *Wallet) getProfile(peerId string, force bool) (*model.Profile, error) {
 	// if peer id is local, return profile from db
 	pid, err := w.GetId()
 	if err != nil {
 		return nil, err
 	}
 	if pid == peerId {
-		username, _ := w.GetUsername()
-		avatarId, _ := w.GetAvatarId()
-		if !strings.HasPrefix(avatarId, "http") {
-			avatarId = ""
+		return w.localProfile(pid)
+	}
+
+	// check cache
+	cached := w.datastore.ProfileCache().Get(peerId)
+	var old *model.Profile
+	if cached != nil {
+		old = new(model.Profile)
+		if err := json.Unmarshal(cached.Profile, old); err != nil {
+			log.Errorf("error reading cached profile %s: %s", peerId, err)
+			old = nil
+		} else if !force && time.Since(cached.Cached) < profileRefreshTTL {
+			return old, nil
 		}
-		return &model.Profile{Id: pid, Username: username, AvatarId: avatarId}, nil
 	}
 
-	// resolve profile at peer id
-	entry, err := w.ResolveProfile(peerId)
+	// resolve, falling back to a stale cached profile
+	prof, err := w.fetchProfile(peerId)
 	if err != nil {
+		if old != nil {
+			log.Debugf("using stale profile for %s: %s", peerId, err)
+			return old, nil
+		}
 		return nil, err
 	}
-	root := entry.String()
 
-	// get components from entry
-	var usernameb, avatarIdb []byte
-	usernameb, _ = util.GetDataAtPath(w.ipfs, fmt.Sprintf("%s/%s", root, "username"))
-	avatarIdb, _ = util.GetDataAtPath(w.ipfs, fmt.Sprintf("%s/%s", root, "avatar_id"))
-	avatarId := string(avatarIdb)
+	// update cache
+	profb, err := json.Marshal(prof)
+	if err != nil {
+		return nil, err
+	}
+	if err := w.datastore.ProfileCache().Put(&repo.CachedProfile{
+		Id:      peerId,
+		Profile: profb,
+		Cached:  time.Now(),
+	}); err != nil {
+		log.Errorf("error caching profile: %s", err)
+	}
+
+	// keep contacts current
+	if w.datastore.Contacts().Get(peerId) != nil {
+		if err := w.datastore.Contacts().UpdateProfile(peerId, prof.Username, prof.AvatarId); err != nil {
+			log.Errorf("error updating contact profile: %s", err)
+		}
+	}
+
+	// notify listeners
+	if old != nil && profileChanged(old, prof) {
+		w.sendUpdate(Update{Id: peerId, Name: prof.Username, Type: ProfileUpdated})
+	}
+
+	return prof, nil
+}
+
+// SetProfileField sets or clears (with an empty value) a signed public profile field
+func (w *Wallet) SetProfileField(key string, value string) error {
+	if err := w.touchDatastore(); err != nil {
+		return err
+	}
+	if !profileFieldKey.MatchString(key) {
+		return errors.New(fmt.Sprintf("invalid profile field: %s", key))
+	}
+	if len(value) > maxProfileFieldLen {
+		return errors.New(fmt.Sprintf("profile field %s is too long", key))
+	}
+	switch key {
+	case model.ProfileCafeAddr:
+		return errors.New("cafe address is set automatically")
+	case model.ProfileThreads:
+		// only allow our own threads
+		var ids []string
+		for _, id := range strings.Split(value, ",") {
+			id = strings.TrimSpace(id)
+			if id == "" {
+				continue
+			}
+			if _, thrd := w.GetThread(id); thrd == nil {
+				return errors.New(fmt.Sprintf("could not find thread: %s", id))
+			}
+			ids = append(ids, id)
+		}
+		value = strings.Join(ids, ",")
+	}
+	if err := w.datastore.Profile().SetField(key, value); err != nil {
+		return err
+	}
+
+	go func() {
+		<-w.Online()
+		if _, err := w.PublishProfile(nil); err != nil {
+			log.Errorf("error publishing profile (set field): %s", err)
+		}
+	}()
+	return nil
+}
+
+// localProfile builds our own profile from the db
+func (w *Wallet) localProfile(pid string) (*model.Profile, error) {
+	username, _ := w.GetUsername()
+	avatarId, _ := w.GetAvatarId()
 	if !strings.HasPrefix(avatarId, "http") {
 		avatarId = ""
 	}
+	fields, err := w.datastore.Profile().GetFields()
+	if err != nil {
+		return nil, err
+	}
+	if w.cafeAddr != "" {
+		fields[model.ProfileCafeAddr] = w.cafeAddr
+	}
+	avatar, _ := w.datastore.Profile().GetAvatar()
+	return &model.Profile{Id: pid, Username: username, AvatarId: avatarId, Avatar: avatar, Fields: fields}, nil
+}
 
-	return &model.Profile{
-		Id:       peerId,
-		Username: string(usernameb),
-		AvatarId: avatarId,
-	}, nil
+// fetchProfile resolves a peer's profile, verifying the signed profile if present
+func (w *Wallet) fetchProfile(peerId string) (*model.Profile, error) {
+	entry, err := w.ResolveProfile(peerId)
+	if err != nil {
+		return nil, err
+	}
+	root := entry.String()
+
+	var prof *model.Profile
+	signedb, err := util.GetDataAtPath(w.ipfs, fmt.Sprintf("%s/%s", root, "profile"))
+	if err == nil {
+		prof, err = verifyProfile(peerId, signedb)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		// older peers only publish the plain files
+		var usernameb, avatarIdb []byte
+		usernameb, _ = util.GetDataAtPath(w.ipfs, fmt.Sprintf("%s/%s", root, "username"))
+		avatarIdb, _ = util.GetDataAtPath(w.ipfs, fmt.Sprintf("%s/%s", root, "avatar_id"))
+		prof = &model.Profile{
+			Id:       peerId,
+			Username: string(usernameb),
+			AvatarId: string(avatarIdb),
+		}
+	}
+	if !strings.HasPrefix(prof.AvatarId, "http") {
+		prof.AvatarId = ""
+	}
+	return prof, nil
 }
 
 // ResolveProfile looks up a peer's profile on ipns
@@ -352,6 +488,36 @@ func (w *Wallet) PublishProfile(prof *model.Profile) (*util.IpnsEntry, error) {
 		return nil, err
 	}
 
+	// link the public avatar directory
+	if prof.Avatar != "" {
+		if err := util.AddLinkToDirectory(w.ipfs, dirb, prof.Avatar, "avatar"); err != nil {
+			return nil, err
+		}
+	}
+
+	// sign the full profile
+	signed, err := w.signProfile(prof)
+	if err != nil {
+		return nil, err
+	}
+	if err := util.AddFileToDirectory(w.ipfs, dirb, bytes.NewReader(signed), "profile"); err != nil {
+		return nil, err
+	}
+
+	// include the account backup, only readable with our own key
+	// legacy identities can't be recovered from their mnemonic, so they don't publish one
+	backup, err := w.Backup()
+	switch err {
+	case nil:
+		if err := util.AddFileToDirectory(w.ipfs, dirb, bytes.NewReader(backup), "backup"); err != nil {
+			return nil, err
+		}
+	case ErrLegacyIdentity:
+		log.Warning("account identity is not derived from its mnemonic, skipping backup")
+	default:
+		return nil, err
+	}
+
 	// pin the directory locally
 	dir, err := dirb.GetNode()
 	if err != nil {
@@ -397,3 +563,98 @@ func (w *Wallet) PublishProfile(prof *model.Profile) (*util.IpnsEntry, error) {
 
 	return entry, nil
 }
+
+// signProfile returns a signed copy of the profile, stamped with the current time
+func (w *Wallet) signProfile(prof *model.Profile) ([]byte, error) {
+	sk, err := w.GetPrivKey()
+	if err != nil {
+		return nil, err
+	}
+	pkb, err := sk.GetPublic().Bytes()
+	if err != nil {
+		return nil, err
+	}
+	stamped := *prof
+	stamped.Updated = time.Now()
+	profb, err := json.Marshal(&stamped)
+	if err != nil {
+		return nil, err
+	}
+	sig, err := sk.Sign(profb)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(&model.SignedProfile{Profile: profb, PubKey: pkb, Sig: sig})
+}
+
+// verifyProfile checks that a signed profile was signed by peerId's key and is about peerId
+func verifyProfile(peerId string, signedb []byte) (*model.Profile, error) {
+	signed := new(model.SignedProfile)
+	if err := json.Unmarshal(signedb, signed); err != nil {
+		return nil, err
+	}
+	pk, err := libp2pc.UnmarshalPublicKey(signed.PubKey)
+	if err != nil {
+		return nil, err
+	}
+	pid, err := peer.IDFromPublicKey(pk)
+	if err != nil {
+		return nil, err
+	}
+	if pid.Pretty() != peerId {
+		return nil, errors.New("profile key does not match peer")
+	}
+	if err := crypto.Verify(pk, signed.Profile, signed.Sig); err != nil {
+		return nil, err
+	}
+	prof := new(model.Profile)
+	if err := json.Unmarshal(signed.Profile, prof); err != nil {
+		return nil, err
+	}
+	if prof.Id != peerId {
+		return nil, errors.New("profile is not for this peer")
+	}
+	return prof, nil
+}
+
+// profileChanged ignores the publish time, which changes with every republish
+func profileChanged(a *model.Profile, b *model.Profile) bool {
+	if a.Username != b.Username || a.AvatarId != b.AvatarId || a.Avatar != b.Avatar || len(a.Fields) != len(b.Fields) {
+		return true
+	}
+	for k, v := range a.Fields {
+		if bv, ok := b.Fields[k]; !ok || bv != v {
+			return true
+		}
+	}
+	return false
+}
+
+// runProfileRefresh periodically refreshes stale contact profiles and drops old
+// cached profiles that don't belong to a contact
+func (w *Wallet) runProfileRefresh() {
+	tick := time.NewTicker(profileRefreshFrequency)
+	defer tick.Stop()
+	done := w.Done()
+	for {
+		select {
+		case <-tick.C:
+			for _, contact := range w.datastore.Contacts().List("") {
+				if _, err := w.GetProfile(contact.Id); err != nil {
+					log.Debugf("error refreshing profile %s: %s", contact.Id, err)
+				}
+			}
+			expired := fmt.Sprintf("cached<%d", time.Now().Add(-profileCacheTTL).Unix())
+			for _, cached := range w.datastore.ProfileCache().List(expired) {
+				if w.datastore.Contacts().Get(cached.Id) != nil {
+					continue
+				}
+				if err := w.datastore.ProfileCache().Delete(cached.Id); err != nil {
+					log.Errorf("error deleting cached profile: %s", err)
+				}
+			}
+		case <-done:
+			return
+		}
+	}
+}
@@ -0,0 +1,14 @@
+package wallet
+
+import (
+	trepo "github.com/textileio/textile-go/repo"
+)
+
+// Search finds photos in our threads by caption and file name, narrowed by filters.
+// An empty query lists by filters alone, newest first.
+func (w *Wallet) Search(query string, filters *trepo.SearchFilters) ([]trepo.SearchEntry, error) {
+	if err := w.touchDatastore(); err != nil {
+		return nil, err
+	}
+	return w.datastore.Search().Search(query, filters), nil
+}
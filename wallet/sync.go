@@ -0,0 +1,236 @@
+package wallet
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+)
+
+// ErrSyncInProgress is returned when a sync pass is started before the last one has ended
+var ErrSyncInProgress = errors.New("sync already in progress")
+
+// SyncStep names a unit of work performed during a sync pass
+type SyncStep string
+
+const (
+	SyncMessages SyncStep = "messages"
+	SyncPins     SyncStep = "pins"
+	SyncPointers SyncStep = "pointers"
+	SyncThreads  SyncStep = "threads"
+	SyncBackup   SyncStep = "backup"
+)
+
+// SyncSteps lists every step of a sync pass, in the order they run
+var SyncSteps = []SyncStep{SyncMessages, SyncPins, SyncPointers, SyncThreads, SyncBackup}
+
+// SyncProgress is reported as each sync step starts and finishes
+type SyncProgress struct {
+	Step     SyncStep `json:"step"`
+	Done     bool     `json:"done"`
+	Count    int      `json:"count"`
+	Error    string   `json:"error,omitempty"`
+	Finished []string `json:"finished"`
+}
+
+// SyncResult summarizes a single sync pass
+type SyncResult struct {
+	Messages int       `json:"messages"`
+	Pins     int       `json:"pins"`
+	Pointers int       `json:"pointers"`
+	Threads  int       `json:"threads"`
+	Backups  int       `json:"backups"`
+	Finished []string  `json:"finished"`
+	TimedOut bool      `json:"timed_out"`
+	Started  time.Time `json:"started"`
+	Ended    time.Time `json:"ended"`
+}
+
+// Sync runs one bounded pass of the jobs that are not scheduled on mobile:
+// message retrieval, pin flushing, pointer republishing, thread catch-up and
+// account backup publishing.
+// A step that does not finish before the timeout is cancelled, and a new pass
+// can't start until it has returned.
+func (w *Wallet) Sync(timeout time.Duration, progress func(*SyncProgress)) (*SyncResult, error) {
+	if !w.started {
+		return nil, ErrStopped
+	}
+	if !w.beginSync() {
+		return nil, ErrSyncInProgress
+	}
+	type outcome struct {
+		count int
+		err   error
+	}
+
+	// the guard is held until the running step returns, which may be after we do
+	var running chan outcome
+	defer func() {
+		if running == nil {
+			w.endSync()
+			return
+		}
+		go func(done chan outcome) {
+			<-done
+			w.endSync()
+		}(running)
+	}()
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	result := &SyncResult{Started: time.Now()}
+	defer func() {
+		result.Ended = time.Now()
+	}()
+	report := func(prog *SyncProgress) {
+		prog.Finished = result.Finished
+		if progress != nil {
+			progress(prog)
+		}
+	}
+
+	// a background task may wake us before the node is online
+	select {
+	case <-w.Online():
+	case <-ctx.Done():
+		result.TimedOut = true
+		return result, ErrOffline
+	}
+	if !w.IsOnline() {
+		return result, ErrOffline
+	}
+
+	runners := map[SyncStep]struct {
+		run func(context.Context) (int, error)
+		out *int
+	}{
+		SyncMessages: {w.syncMessages, &result.Messages},
+		SyncPins:     {w.syncPins, &result.Pins},
+		SyncPointers: {w.syncPointers, &result.Pointers},
+		SyncThreads:  {w.syncThreads, &result.Threads},
+		SyncBackup:   {w.syncBackup, &result.Backups},
+	}
+	for _, step := range SyncSteps {
+		s := runners[step]
+		report(&SyncProgress{Step: step})
+
+		done := make(chan outcome, 1)
+		go func(run func(context.Context) (int, error)) {
+			count, err := run(ctx)
+			done <- outcome{count: count, err: err}
+		}(s.run)
+
+		select {
+		case out := <-done:
+			*s.out = out.count
+			result.Finished = append(result.Finished, string(step))
+			prog := &SyncProgress{Step: step, Done: true, Count: out.count}
+			if out.err != nil {
+				log.Errorf("sync step %s failed: %s", step, out.err)
+				prog.Error = out.err.Error()
+			}
+			report(prog)
+		case <-ctx.Done():
+			log.Debugf("sync deadline reached during %s", step)
+			result.TimedOut = true
+			running = done
+			return result, nil
+		}
+	}
+	return result, nil
+}
+
+// beginSync marks a sync pass as running, returning false if one already is
+func (w *Wallet) beginSync() bool {
+	w.synclk.Lock()
+	defer w.synclk.Unlock()
+	if w.syncing {
+		return false
+	}
+	w.syncing = true
+	return true
+}
+
+// endSync marks the running sync pass as ended
+func (w *Wallet) endSync() {
+	w.synclk.Lock()
+	defer w.synclk.Unlock()
+	w.syncing = false
+}
+
+// syncMessages fetches and handles new offline messages
+func (w *Wallet) syncMessages(context.Context) (int, error) {
+	w.messageRetriever.Add(1)
+	return w.messageRetriever.FetchPointers(), nil
+}
+
+// syncPins flushes pending pin requests to the cafe
+func (w *Wallet) syncPins(context.Context) (int, error) {
+	if w.pinner == nil {
+		return 0, nil
+	}
+	return w.pinner.Pin(), nil
+}
+
+// syncPointers republishes offline message pointers
+func (w *Wallet) syncPointers(context.Context) (int, error) {
+	return w.pointerRepublisher.Republish(), nil
+}
+
+// syncThreads fills in any missing blocks behind each thread head, stopping between threads once ctx is done
+func (w *Wallet) syncThreads(ctx context.Context) (int, error) {
+	var lastErr error
+	count := 0
+	for _, thrd := range w.Threads() {
+		if err := ctx.Err(); err != nil {
+			return count, err
+		}
+		head, err := thrd.GetHead()
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		if head == "" {
+			continue
+		}
+		var parents []string
+		for _, id := range strings.Split(head, ",") {
+			block := w.datastore.Blocks().Get(id)
+			if block == nil {
+				parents = append(parents, id)
+				continue
+			}
+			parents = append(parents, block.Parents...)
+		}
+		if err := thrd.FollowParents(parents); err != nil {
+			log.Errorf("error catching up thread %s: %s", thrd.Id, err)
+			lastErr = err
+			continue
+		}
+
+		// try blocks earlier walks could not reach
+		if _, err := thrd.RetryMissing(); err != nil {
+			log.Errorf("error retrying missing blocks in thread %s: %s", thrd.Id, err)
+		}
+
+		// ask peers for anything added while we were away
+		if _, err := w.pullThread(ctx, thrd, nil); err != nil {
+			log.Debugf("error pulling thread %s: %s", thrd.Id, err)
+		}
+		count++
+	}
+	return count, lastErr
+}
+
+// syncBackup republishes the profile and account backup when a cafe is configured
+func (w *Wallet) syncBackup(ctx context.Context) (int, error) {
+	if w.cafeAddr == "" {
+		return 0, nil
+	}
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+	if _, err := w.PublishProfile(nil); err != nil {
+		return 0, err
+	}
+	return 1, nil
+}
@@ -0,0 +1,183 @@
+package thread
+
+import (
+	"errors"
+	"fmt"
+	"github.com/golang/protobuf/proto"
+	"github.com/segmentio/ksuid"
+	"github.com/textileio/textile-go/pb"
+	"github.com/textileio/textile-go/repo"
+	"gx/ipfs/QmZoWKhxUmZ2seW4BzX6fJkNR8hh9PsGModr7q171yq2SS/go-libp2p-peer"
+	mh "gx/ipfs/QmZyZDi491cCNTLfAhwcaDii2Kg4pwKRkhqQzURGDvY6ua/go-multihash"
+	libp2pc "gx/ipfs/QmaPbCnUMBohSGo3KnxEa2bHqyJVVeEEcwtqJAYxerieBo/go-libp2p-crypto"
+	"time"
+)
+
+// AddToAlbum adds an outgoing membership block, which puts a thread photo in an album
+func (t *Thread) AddToAlbum(albumId string, dataId string) (mh.Multihash, error) {
+	if t.photo(dataId) == nil {
+		return nil, errors.New("album photos must be in this thread")
+	}
+	return t.addAlbumMember(albumId, dataId, pb.ThreadAlbumMember_ADD)
+}
+
+// RemoveFromAlbum adds an outgoing membership block, which takes a photo out of an album
+func (t *Thread) RemoveFromAlbum(albumId string, dataId string) (mh.Multihash, error) {
+	if t.photo(dataId) == nil {
+		return nil, errors.New("album photos must be in this thread")
+	}
+	return t.addAlbumMember(albumId, dataId, pb.ThreadAlbumMember_REMOVE)
+}
+
+// addAlbumMember adds an outgoing membership block
+func (t *Thread) addAlbumMember(albumId string, dataId string, action pb.ThreadAlbumMember_Action) (mh.Multihash, error) {
+	if t.album(albumId) == nil {
+		return nil, errors.New(fmt.Sprintf("could not find album: %s", albumId))
+	}
+
+	t.mux.Lock()
+	defer t.mux.Unlock()
+
+	// build block
+	header, err := t.newBlockHeader(time.Now())
+	if err != nil {
+		return nil, err
+	}
+	content := &pb.ThreadAlbumMember{
+		Header:  header,
+		AlbumId: albumId,
+		DataId:  dataId,
+		Action:  action,
+	}
+
+	// commit to ipfs
+	message, addr, err := t.commitBlock(content, pb.Message_THREAD_ALBUM_MEMBER)
+	if err != nil {
+		return nil, err
+	}
+	id := addr.B58String()
+
+	// index it locally
+	if err := t.indexAlbumMember(id, content); err != nil {
+		return nil, err
+	}
+
+	// update head
+	if err := t.advanceHead(id, header.Parents); err != nil {
+		return nil, err
+	}
+
+	// post it
+	t.post(message, id, t.Peers())
+
+	log.Debugf("album member added to %s: %s", t.Id, id)
+
+	// all done
+	return addr, nil
+}
+
+// HandleAlbumMemberBlock handles an incoming membership block
+func (t *Thread) HandleAlbumMemberBlock(message *pb.Envelope, signed *pb.SignedThreadBlock, content *pb.ThreadAlbumMember, following bool) (mh.Multihash, error) {
+	// unmarshal if needed
+	if content == nil {
+		content = new(pb.ThreadAlbumMember)
+		if err := proto.Unmarshal(signed.Block, content); err != nil {
+			return nil, err
+		}
+	}
+
+	// ids end up in the index, so only accept well formed ones
+	if _, err := mh.FromB58String(content.AlbumId); err != nil {
+		return nil, errors.New("invalid album id")
+	}
+	if _, err := mh.FromB58String(content.DataId); err != nil {
+		return nil, errors.New("invalid photo id")
+	}
+
+	// add to ipfs
+	addr, err := t.addBlock(message)
+	if err != nil {
+		return nil, err
+	}
+	id := addr.B58String()
+
+	// check if we aleady have this block indexed
+	// (should only happen if a misbehaving peer keeps sending the same block)
+	index := t.blocks().Get(id)
+	if index != nil {
+		return nil, nil
+	}
+
+	// get the author id
+	authorPk, err := libp2pc.UnmarshalPublicKey(content.Header.AuthorPk)
+	if err != nil {
+		return nil, err
+	}
+	authorId, err := peer.IDFromPublicKey(authorPk)
+	if err != nil {
+		return nil, err
+	}
+
+	// add author as a new local peer, just in case we haven't found this peer yet.
+	// double-check not self in case we're re-discovering the thread
+	if authorId.Pretty() != t.ipfs().Identity.Pretty() {
+		newPeer := &repo.Peer{
+			Row:      ksuid.New().String(),
+			Id:       authorId.Pretty(),
+			ThreadId: libp2pc.ConfigEncodeKey(content.Header.ThreadPk),
+			PubKey:   content.Header.AuthorPk,
+		}
+		if err := t.peers().Add(newPeer); err != nil {
+			// TODO: #202 (Properly handle database/sql errors)
+			log.Warningf("peer with id %s already exists in thread %s", newPeer.Id, t.Id)
+		}
+	}
+
+	// index it locally
+	if err := t.indexAlbumMember(id, content); err != nil {
+		return nil, err
+	}
+
+	// the follower walking back to us takes care of our parents
+	if following {
+		return addr, nil
+	}
+
+	// back prop
+	if err := t.FollowParents(content.Header.Parents); err != nil {
+		return nil, err
+	}
+
+	// handle HEAD
+	if err := t.handleHead(id, content.Header.Parents); err != nil {
+		return nil, err
+	}
+
+	return addr, nil
+}
+
+// indexAlbumMember indexes a membership block under its album and photo,
+// then updates the album's current photos with whichever membership block is latest
+func (t *Thread) indexAlbumMember(id string, content *pb.ThreadAlbumMember) error {
+	blockType := repo.AlbumAddBlock
+	if content.Action == pb.ThreadAlbumMember_REMOVE {
+		blockType = repo.AlbumRemoveBlock
+	}
+	dataId := fmt.Sprintf("album-%s-%s", content.AlbumId, content.DataId)
+	dconf := &repo.DataBlockConfig{
+		DataId: dataId,
+	}
+	if err := t.indexBlock(id, content.Header, blockType, dconf); err != nil {
+		return err
+	}
+
+	latest := t.latestBlock(t.blocks().ListByDataId(t.Id, dataId))
+	if latest == nil || latest.Type != repo.AlbumAddBlock {
+		return t.albumPhotos().Delete(content.AlbumId, content.DataId)
+	}
+	return t.albumPhotos().Add(&repo.AlbumPhoto{
+		AlbumId:  content.AlbumId,
+		DataId:   content.DataId,
+		ThreadId: t.Id,
+	})
+}
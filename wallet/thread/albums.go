@@ -0,0 +1,199 @@
+package thread
+
+import (
+	"errors"
+	"fmt"
+	"github.com/golang/protobuf/proto"
+	"github.com/segmentio/ksuid"
+	"github.com/textileio/textile-go/pb"
+	"github.com/textileio/textile-go/repo"
+	"gx/ipfs/QmZoWKhxUmZ2seW4BzX6fJkNR8hh9PsGModr7q171yq2SS/go-libp2p-peer"
+	mh "gx/ipfs/QmZyZDi491cCNTLfAhwcaDii2Kg4pwKRkhqQzURGDvY6ua/go-multihash"
+	libp2pc "gx/ipfs/QmaPbCnUMBohSGo3KnxEa2bHqyJVVeEEcwtqJAYxerieBo/go-libp2p-crypto"
+	"time"
+)
+
+// maxAlbumNameLength bounds album names
+const maxAlbumNameLength = 128
+
+// AddAlbum adds an outgoing album block, which starts a named collection of thread photos
+func (t *Thread) AddAlbum(name string) (mh.Multihash, error) {
+	if name == "" {
+		return nil, errors.New("album name cannot be empty")
+	}
+	if len(name) > maxAlbumNameLength {
+		return nil, errors.New("album name is too long")
+	}
+
+	t.mux.Lock()
+	defer t.mux.Unlock()
+
+	// encrypt name with thread pk
+	nameCipher, err := t.Encrypt([]byte(name))
+	if err != nil {
+		return nil, err
+	}
+
+	// build block
+	header, err := t.newBlockHeader(time.Now())
+	if err != nil {
+		return nil, err
+	}
+	content := &pb.ThreadAlbum{
+		Header:     header,
+		NameCipher: nameCipher,
+	}
+
+	// commit to ipfs
+	message, addr, err := t.commitBlock(content, pb.Message_THREAD_ALBUM)
+	if err != nil {
+		return nil, err
+	}
+	id := addr.B58String()
+
+	// index it locally, the name is kept with the caption so albums list like photos
+	dconf := &repo.DataBlockConfig{
+		DataCaptionCipher: nameCipher,
+	}
+	if err := t.indexBlock(id, header, repo.AlbumBlock, dconf); err != nil {
+		return nil, err
+	}
+
+	// update head
+	if err := t.advanceHead(id, header.Parents); err != nil {
+		return nil, err
+	}
+
+	// post it
+	t.post(message, id, t.Peers())
+
+	log.Debugf("album added to %s: %s", t.Id, id)
+
+	// all done
+	return addr, nil
+}
+
+// HandleAlbumBlock handles an incoming album block
+func (t *Thread) HandleAlbumBlock(message *pb.Envelope, signed *pb.SignedThreadBlock, content *pb.ThreadAlbum, following bool) (mh.Multihash, error) {
+	// unmarshal if needed
+	if content == nil {
+		content = new(pb.ThreadAlbum)
+		if err := proto.Unmarshal(signed.Block, content); err != nil {
+			return nil, err
+		}
+	}
+
+	// albums are listed by name, so one without a readable name is no use
+	name, err := t.Decrypt(content.NameCipher)
+	if err != nil {
+		return nil, err
+	}
+	if len(name) == 0 || len(name) > maxAlbumNameLength {
+		return nil, errors.New("invalid album name")
+	}
+
+	// add to ipfs
+	addr, err := t.addBlock(message)
+	if err != nil {
+		return nil, err
+	}
+	id := addr.B58String()
+
+	// check if we aleady have this block indexed
+	// (should only happen if a misbehaving peer keeps sending the same block)
+	index := t.blocks().Get(id)
+	if index != nil {
+		return nil, nil
+	}
+
+	// get the author id
+	authorPk, err := libp2pc.UnmarshalPublicKey(content.Header.AuthorPk)
+	if err != nil {
+		return nil, err
+	}
+	authorId, err := peer.IDFromPublicKey(authorPk)
+	if err != nil {
+		return nil, err
+	}
+
+	// add author as a new local peer, just in case we haven't found this peer yet.
+	// double-check not self in case we're re-discovering the thread
+	if authorId.Pretty() != t.ipfs().Identity.Pretty() {
+		newPeer := &repo.Peer{
+			Row:      ksuid.New().String(),
+			Id:       authorId.Pretty(),
+			ThreadId: libp2pc.ConfigEncodeKey(content.Header.ThreadPk),
+			PubKey:   content.Header.AuthorPk,
+		}
+		if err := t.peers().Add(newPeer); err != nil {
+			// TODO: #202 (Properly handle database/sql errors)
+			log.Warningf("peer with id %s already exists in thread %s", newPeer.Id, t.Id)
+		}
+	}
+
+	// index it locally
+	dconf := &repo.DataBlockConfig{
+		DataCaptionCipher: content.NameCipher,
+	}
+	if err := t.indexBlock(id, content.Header, repo.AlbumBlock, dconf); err != nil {
+		return nil, err
+	}
+
+	// the follower walking back to us takes care of our parents
+	if following {
+		return addr, nil
+	}
+
+	// back prop
+	if err := t.FollowParents(content.Header.Parents); err != nil {
+		return nil, err
+	}
+
+	// handle HEAD
+	if err := t.handleHead(id, content.Header.Parents); err != nil {
+		return nil, err
+	}
+
+	return addr, nil
+}
+
+// Albums paginates album blocks, the name cipher is in DataCaptionCipher
+func (t *Thread) Albums(offsetId string, limit int) []repo.Block {
+	return t.Blocks(offsetId, limit, repo.AlbumBlock)
+}
+
+// AlbumPhotos paginates the photo blocks currently in an album, newest first
+func (t *Thread) AlbumPhotos(albumId string, offsetId string, limit int) ([]repo.Block, error) {
+	if t.album(albumId) == nil {
+		return nil, errors.New(fmt.Sprintf("could not find album: %s", albumId))
+	}
+	var photos []repo.Block
+	for _, photo := range t.albumPhotos().ListPhotos(t.Id, albumId, offsetId, limit) {
+		if t.ignored(photo.Id) {
+			continue
+		}
+		photos = append(photos, t.resolve(photo))
+	}
+	return photos, nil
+}
+
+// album returns an album block in this thread
+func (t *Thread) album(albumId string) *repo.Block {
+	album := t.blocks().Get(albumId)
+	if album == nil || album.Type != repo.AlbumBlock || album.ThreadId != t.Id {
+		return nil
+	}
+	return album
+}
+
+// photo returns the first photo block in this thread with the given data id
+func (t *Thread) photo(dataId string) *repo.Block {
+	var first *repo.Block
+	for _, block := range t.blocks().ListByDataId(t.Id, dataId) {
+		if block.Type == repo.PhotoBlock {
+			b := block
+			first = &b
+		}
+	}
+	return first
+}
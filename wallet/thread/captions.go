@@ -0,0 +1,180 @@
+package thread
+
+import (
+	"errors"
+	"fmt"
+	"github.com/golang/protobuf/proto"
+	"github.com/segmentio/ksuid"
+	"github.com/textileio/textile-go/pb"
+	"github.com/textileio/textile-go/repo"
+	"gx/ipfs/QmZoWKhxUmZ2seW4BzX6fJkNR8hh9PsGModr7q171yq2SS/go-libp2p-peer"
+	mh "gx/ipfs/QmZyZDi491cCNTLfAhwcaDii2Kg4pwKRkhqQzURGDvY6ua/go-multihash"
+	libp2pc "gx/ipfs/QmaPbCnUMBohSGo3KnxEa2bHqyJVVeEEcwtqJAYxerieBo/go-libp2p-crypto"
+	"time"
+)
+
+// EditCaption adds an outgoing caption block, which replaces the caption of one of our photos
+func (t *Thread) EditCaption(blockId string, caption string) (mh.Multihash, error) {
+	target := t.blocks().Get(blockId)
+	if target == nil || target.Type != repo.PhotoBlock || target.ThreadId != t.Id {
+		return nil, errors.New("caption target must be a photo in this thread")
+	}
+
+	t.mux.Lock()
+	defer t.mux.Unlock()
+
+	// encrypt caption with thread pk
+	var captionCipher []byte
+	var err error
+	if caption != "" {
+		captionCipher, err = t.Encrypt([]byte(caption))
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// build block
+	header, err := t.newBlockHeader(time.Now())
+	if err != nil {
+		return nil, err
+	}
+	if libp2pc.ConfigEncodeKey(header.AuthorPk) != target.AuthorPk {
+		return nil, errors.New("only the photo author can edit its caption")
+	}
+	dataId := fmt.Sprintf("caption-%s", blockId)
+	content := &pb.ThreadCaption{
+		Header:        header,
+		DataId:        dataId,
+		CaptionCipher: captionCipher,
+	}
+
+	// commit to ipfs
+	message, addr, err := t.commitBlock(content, pb.Message_THREAD_CAPTION)
+	if err != nil {
+		return nil, err
+	}
+	id := addr.B58String()
+
+	// index it locally
+	dconf := &repo.DataBlockConfig{
+		DataId:            dataId,
+		DataCaptionCipher: captionCipher,
+	}
+	if err := t.indexBlock(id, header, repo.CaptionBlock, dconf); err != nil {
+		return nil, err
+	}
+
+	// update head
+	if err := t.advanceHead(id, header.Parents); err != nil {
+		return nil, err
+	}
+
+	// post it
+	t.post(message, id, t.Peers())
+
+	log.Debugf("caption added to %s: %s", t.Id, id)
+
+	// all done
+	return addr, nil
+}
+
+// HandleCaptionBlock handles an incoming caption block
+func (t *Thread) HandleCaptionBlock(message *pb.Envelope, signed *pb.SignedThreadBlock, content *pb.ThreadCaption, following bool) (mh.Multihash, error) {
+	// unmarshal if needed
+	if content == nil {
+		content = new(pb.ThreadCaption)
+		if err := proto.Unmarshal(signed.Block, content); err != nil {
+			return nil, err
+		}
+	}
+
+	// captions must target a photo in this thread
+	target, err := t.blockTarget(content.DataId, "caption")
+	if err != nil {
+		return nil, err
+	}
+	if target.Type != repo.PhotoBlock {
+		return nil, errors.New("caption target must be a photo in this thread")
+	}
+
+	// add to ipfs
+	addr, err := t.addBlock(message)
+	if err != nil {
+		return nil, err
+	}
+	id := addr.B58String()
+
+	// check if we aleady have this block indexed
+	// (should only happen if a misbehaving peer keeps sending the same block)
+	index := t.blocks().Get(id)
+	if index != nil {
+		return nil, nil
+	}
+
+	// get the author id
+	authorPk, err := libp2pc.UnmarshalPublicKey(content.Header.AuthorPk)
+	if err != nil {
+		return nil, err
+	}
+	authorId, err := peer.IDFromPublicKey(authorPk)
+	if err != nil {
+		return nil, err
+	}
+
+	// add author as a new local peer, just in case we haven't found this peer yet.
+	// double-check not self in case we're re-discovering the thread
+	if authorId.Pretty() != t.ipfs().Identity.Pretty() {
+		newPeer := &repo.Peer{
+			Row:      ksuid.New().String(),
+			Id:       authorId.Pretty(),
+			ThreadId: libp2pc.ConfigEncodeKey(content.Header.ThreadPk),
+			PubKey:   content.Header.AuthorPk,
+		}
+		if err := t.peers().Add(newPeer); err != nil {
+			// TODO: #202 (Properly handle database/sql errors)
+			log.Warningf("peer with id %s already exists in thread %s", newPeer.Id, t.Id)
+		}
+	}
+
+	// index it locally, captions from anyone but the photo author are ignored when listing
+	dconf := &repo.DataBlockConfig{
+		DataId:            content.DataId,
+		DataCaptionCipher: content.CaptionCipher,
+	}
+	if err := t.indexBlock(id, content.Header, repo.CaptionBlock, dconf); err != nil {
+		return nil, err
+	}
+
+	// the follower walking back to us takes care of our parents
+	if following {
+		return addr, nil
+	}
+
+	// back prop
+	if err := t.FollowParents(content.Header.Parents); err != nil {
+		return nil, err
+	}
+
+	// handle HEAD
+	if err := t.handleHead(id, content.Header.Parents); err != nil {
+		return nil, err
+	}
+
+	return addr, nil
+}
+
+// latestCaption returns the newest caption block the photo's author added for it
+func (t *Thread) latestCaption(photo *repo.Block) *repo.Block {
+	return t.authorCaption(photo, t.blocks().ListByDataIds(t.Id, []string{"caption-" + photo.Id}))
+}
+
+// authorCaption returns the newest of a photo's caption blocks added by the photo's author
+func (t *Thread) authorCaption(photo *repo.Block, captions []repo.Block) *repo.Block {
+	var authored []repo.Block
+	for _, caption := range captions {
+		if caption.AuthorPk == photo.AuthorPk {
+			authored = append(authored, caption)
+		}
+	}
+	return t.latestBlock(authored)
+}
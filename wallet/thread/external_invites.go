@@ -1,16 +1,26 @@
 package thread
 
 import (
+	"errors"
+	"fmt"
 	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes"
 	"github.com/textileio/textile-go/crypto"
 	"github.com/textileio/textile-go/pb"
 	"github.com/textileio/textile-go/repo"
+	"github.com/textileio/textile-go/util"
 	mh "gx/ipfs/QmZyZDi491cCNTLfAhwcaDii2Kg4pwKRkhqQzURGDvY6ua/go-multihash"
 	"time"
 )
 
-// AddExternalInvite creates an outgoing external invite
-func (t *Thread) AddExternalInvite() (mh.Multihash, []byte, error) {
+var ErrInviteNotFound = errors.New("invite not found")
+var ErrInviteRevoked = errors.New("invite has been revoked")
+var ErrInviteExpired = errors.New("invite has expired")
+var ErrInviteUsedUp = errors.New("invite has reached its max uses")
+
+// AddExternalInvite creates an outgoing external invite.
+// A zero expires or maxUses means the invite never expires or has unlimited uses.
+func (t *Thread) AddExternalInvite(expires time.Time, maxUses int) (mh.Multihash, []byte, error) {
 	t.mux.Lock()
 	defer t.mux.Unlock()
 
@@ -25,7 +35,7 @@ func (t *Thread) AddExternalInvite() (mh.Multihash, []byte, error) {
 	if err != nil {
 		return nil, nil, err
 	}
-	threadSkCipher, err := crypto.EncryptAES(threadSk, key)
+	threadSkCipher, err := crypto.EncryptEnvelope(threadSk, key, "")
 	if err != nil {
 		return nil, nil, err
 	}
@@ -39,6 +49,13 @@ func (t *Thread) AddExternalInvite() (mh.Multihash, []byte, error) {
 		Header:        header,
 		SkCipher:      threadSkCipher,
 		SuggestedName: t.Name,
+		MaxUses:       int32(maxUses),
+	}
+	if !expires.IsZero() {
+		content.Expires, err = ptypes.TimestampProto(expires)
+		if err != nil {
+			return nil, nil, err
+		}
 	}
 
 	// commit to ipfs
@@ -54,7 +71,7 @@ func (t *Thread) AddExternalInvite() (mh.Multihash, []byte, error) {
 	}
 
 	// update head
-	if err := t.updateHead(id); err != nil {
+	if err := t.advanceHead(id, header.Parents); err != nil {
 		return nil, nil, err
 	}
 
@@ -96,18 +113,79 @@ func (t *Thread) HandleExternalInviteBlock(message *pb.Envelope, signed *pb.Sign
 		return nil, err
 	}
 
+	// the follower walking back to us takes care of our parents
+	if following {
+		return addr, nil
+	}
+
 	// back prop
 	if err := t.FollowParents(content.Header.Parents); err != nil {
 		return nil, err
 	}
 
 	// handle HEAD
-	if following {
-		return addr, nil
-	}
-	if _, err := t.handleHead(id, content.Header.Parents, false); err != nil {
+	if err := t.handleHead(id, content.Header.Parents); err != nil {
 		return nil, err
 	}
 
 	return addr, nil
 }
+
+// ExternalInviteStatus returns an error if an external invite has been revoked,
+// has expired, or has been used as many times as allowed
+func (t *Thread) ExternalInviteStatus(blockId string) error {
+	index := t.blocks().Get(blockId)
+	if index == nil || index.Type != repo.ExternalInviteBlock {
+		return ErrInviteNotFound
+	}
+	if t.blocks().GetByDataId(fmt.Sprintf("revoke-%s", blockId)) != nil {
+		return ErrInviteRevoked
+	}
+	invite, err := t.loadExternalInvite(blockId)
+	if err != nil {
+		return err
+	}
+	if ExternalInviteExpired(invite) {
+		return ErrInviteExpired
+	}
+	if invite.MaxUses > 0 {
+		query := fmt.Sprintf("threadId='%s' and type=%d and dataId='join-%s'", t.Id, repo.JoinBlock, blockId)
+		if len(t.blocks().List("", -1, query)) >= int(invite.MaxUses) {
+			return ErrInviteUsedUp
+		}
+	}
+	return nil
+}
+
+// ExternalInviteExpired returns whether or not an invite is past its expiry date
+func ExternalInviteExpired(invite *pb.ThreadExternalInvite) bool {
+	if invite.Expires == nil {
+		return false
+	}
+	expires, err := ptypes.Timestamp(invite.Expires)
+	if err != nil {
+		return true
+	}
+	return time.Now().After(expires)
+}
+
+// loadExternalInvite reads an external invite block from ipfs
+func (t *Thread) loadExternalInvite(blockId string) (*pb.ThreadExternalInvite, error) {
+	envb, err := util.GetDataAtPath(t.ipfs(), blockId)
+	if err != nil {
+		return nil, err
+	}
+	env := new(pb.Envelope)
+	if err := proto.Unmarshal(envb, env); err != nil {
+		return nil, err
+	}
+	signed := new(pb.SignedThreadBlock)
+	if err := ptypes.UnmarshalAny(env.Message.Payload, signed); err != nil {
+		return nil, err
+	}
+	invite := new(pb.ThreadExternalInvite)
+	if err := proto.Unmarshal(signed.Block, invite); err != nil {
+		return nil, err
+	}
+	return invite, nil
+}
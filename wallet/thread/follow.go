@@ -0,0 +1,225 @@
+package thread
+
+import (
+	"errors"
+	"github.com/golang/protobuf/proto"
+	"github.com/textileio/textile-go/pb"
+	"github.com/textileio/textile-go/repo"
+	"github.com/textileio/textile-go/util"
+	"sync"
+	"time"
+)
+
+// followConcurrency is how many blocks are fetched at once while following parents
+const followConcurrency = 8
+
+// followTimeout bounds fetching a single block
+const followTimeout = time.Second * 20
+
+// followLimit caps how many blocks one walk fetches, the rest are queued for later
+const followLimit = 500
+
+// followRetryDelay is how long a missing block waits before it's tried again
+const followRetryDelay = time.Minute * 5
+
+// followMaxAttempts is how many times a missing block is tried before it's dropped
+const followMaxAttempts = 20
+
+// followed is a verified block fetched while following parents
+type followed struct {
+	env     *pb.Envelope
+	signed  *pb.SignedThreadBlock
+	parents []string
+	err     error
+}
+
+// FollowParents walks back from parents, fetching missing blocks a few at a time and
+// indexing them oldest first. Blocks that can't be fetched (or are past the walk limit)
+// are queued in the datastore, so a walk indexes what it can and the rest is picked up later.
+func (t *Thread) FollowParents(parents []string) error {
+	fetched := make(map[string]*followed)
+	var missing []string
+	seen := make(map[string]bool)
+	frontier := t.unknownBlocks(parents, seen)
+	for len(frontier) > 0 {
+		if len(fetched) >= followLimit {
+			missing = append(missing, frontier...)
+			break
+		}
+		var next []string
+		for id, res := range t.fetchBlocks(frontier) {
+			if res.err != nil {
+				log.Debugf("error following block %s: %s", id, res.err)
+				missing = append(missing, id)
+				continue
+			}
+			fetched[id] = res
+			next = append(next, t.unknownBlocks(res.parents, seen)...)
+		}
+		frontier = next
+	}
+
+	count := t.indexFollowed(fetched)
+	if count > 0 {
+		log.Debugf("followed %d blocks in thread %s", count, t.Id)
+	}
+	if len(missing) > 0 {
+		log.Warningf("%d blocks missing in thread %s, queued for retry", len(missing), t.Id)
+	}
+	return t.queueMissing(missing)
+}
+
+// RetryMissing follows blocks that were queued by an earlier walk,
+// returning the number that are now indexed
+func (t *Thread) RetryMissing() (int, error) {
+	due := t.missingBlocks().List(t.Id, time.Now().Add(-followRetryDelay), followLimit)
+	if len(due) == 0 {
+		return 0, nil
+	}
+	var ids []string
+	for _, mb := range due {
+		if mb.Attempts >= followMaxAttempts {
+			log.Warningf("giving up on block %s in thread %s", mb.Id, t.Id)
+			if err := t.missingBlocks().Delete(mb.Id); err != nil {
+				return 0, err
+			}
+			continue
+		}
+		ids = append(ids, mb.Id)
+	}
+	if err := t.FollowParents(ids); err != nil {
+		return 0, err
+	}
+	var count int
+	for _, id := range ids {
+		if t.blocks().Get(id) == nil {
+			continue
+		}
+		if err := t.missingBlocks().Delete(id); err != nil {
+			return count, err
+		}
+		count++
+	}
+	return count, nil
+}
+
+// unknownBlocks filters ids down to those not yet seen or indexed
+func (t *Thread) unknownBlocks(ids []string, seen map[string]bool) []string {
+	var unknown []string
+	for _, id := range ids {
+		if id == "" || seen[id] {
+			continue
+		}
+		seen[id] = true
+		if t.blocks().Get(id) != nil {
+			continue
+		}
+		unknown = append(unknown, id)
+	}
+	return unknown
+}
+
+// fetchBlocks downloads and verifies blocks, at most followConcurrency at a time
+func (t *Thread) fetchBlocks(ids []string) map[string]*followed {
+	results := make(map[string]*followed)
+	var lock sync.Mutex
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, followConcurrency)
+	for _, id := range ids {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(id string) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			res := t.fetchBlock(id)
+			lock.Lock()
+			results[id] = res
+			lock.Unlock()
+		}(id)
+	}
+	wg.Wait()
+	return results
+}
+
+// fetchBlock downloads a block and reads its parents
+func (t *Thread) fetchBlock(id string) *followed {
+	serialized, err := util.GetDataAtPathWithTimeout(t.ipfs(), id, followTimeout)
+	if err != nil {
+		return &followed{err: err}
+	}
+	env := new(pb.Envelope)
+	if err := proto.Unmarshal(serialized, env); err != nil {
+		return &followed{err: err}
+	}
+	signed, err := t.verifyEnvelope(env)
+	if err != nil {
+		return &followed{err: err}
+	}
+
+	// every block type leads with its header, which is all a merge block has
+	header := new(pb.ThreadMerge)
+	if err := proto.Unmarshal(signed.Block, header); err != nil {
+		return &followed{err: err}
+	}
+	if header.Header == nil {
+		return &followed{err: errors.New("block is missing a header")}
+	}
+	return &followed{env: env, signed: signed, parents: header.Header.Parents}
+}
+
+// indexFollowed handles fetched blocks parents first, so each block lands on an indexed parent
+func (t *Thread) indexFollowed(fetched map[string]*followed) int {
+	// count the fetched parents each block is waiting on
+	waiting := make(map[string]int)
+	children := make(map[string][]string)
+	var ready []string
+	for id, res := range fetched {
+		for _, parent := range res.parents {
+			if _, ok := fetched[parent]; ok {
+				waiting[id]++
+				children[parent] = append(children[parent], id)
+			}
+		}
+		if waiting[id] == 0 {
+			ready = append(ready, id)
+		}
+	}
+
+	var count int
+	for len(ready) > 0 {
+		id := ready[0]
+		ready = ready[1:]
+		res := fetched[id]
+		if err := t.handleFollowed(res.env, res.signed); err != nil {
+			log.Errorf("error handling followed block %s: %s", id, err)
+		} else {
+			count++
+		}
+		for _, child := range children[id] {
+			waiting[child]--
+			if waiting[child] == 0 {
+				ready = append(ready, child)
+			}
+		}
+	}
+	return count
+}
+
+// queueMissing adds blocks that could not be followed to the retry queue
+func (t *Thread) queueMissing(ids []string) error {
+	now := time.Now()
+	for _, id := range ids {
+		if err := t.missingBlocks().Put(&repo.MissingBlock{
+			Id:       id,
+			ThreadId: t.Id,
+			Attempts: 1,
+			Added:    now,
+			Tried:    now,
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
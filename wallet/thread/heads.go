@@ -0,0 +1,47 @@
+package thread
+
+import (
+	"sort"
+	"strings"
+)
+
+// splitHeads parses a stored head into a sorted list of unique block ids
+func splitHeads(head string) []string {
+	var heads []string
+	seen := make(map[string]bool)
+	for _, id := range strings.Split(head, ",") {
+		id = strings.TrimSpace(id)
+		if id == "" || seen[id] {
+			continue
+		}
+		seen[id] = true
+		heads = append(heads, id)
+	}
+	sort.Strings(heads)
+	return heads
+}
+
+// joinHeads serializes heads for storage
+func joinHeads(heads []string) string {
+	return strings.Join(splitHeads(strings.Join(heads, ",")), ",")
+}
+
+// removeHeads returns heads without any of the ids in drop
+func removeHeads(heads []string, drop []string) []string {
+	dropped := make(map[string]bool)
+	for _, id := range drop {
+		dropped[id] = true
+	}
+	var kept []string
+	for _, head := range heads {
+		if !dropped[head] {
+			kept = append(kept, head)
+		}
+	}
+	return kept
+}
+
+// sameHeads returns whether or not two lists of parents name the same blocks
+func sameHeads(a []string, b []string) bool {
+	return joinHeads(a) == joinHeads(b)
+}
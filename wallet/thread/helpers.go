@@ -9,6 +9,8 @@ import (
 	"github.com/textileio/textile-go/util"
 	"github.com/textileio/textile-go/wallet/model"
 	libp2pc "gx/ipfs/QmaPbCnUMBohSGo3KnxEa2bHqyJVVeEEcwtqJAYxerieBo/go-libp2p-crypto"
+	"io"
+	gopath "path"
 )
 
 // GetBlockDataKey returns the decrypted AES key for a block
@@ -24,7 +26,8 @@ func (t *Thread) GetBlockDataKey(block *repo.Block) ([]byte, error) {
 	return key, nil
 }
 
-// GetBlockData cats file data from ipfs and tries to decrypt it with the provided block
+// GetBlockData cats file data from ipfs and tries to decrypt it with the provided block.
+// The last path segment names the file's subkey.
 func (t *Thread) GetBlockData(path string, block *repo.Block) ([]byte, error) {
 	key, err := t.GetBlockDataKey(block)
 	if err != nil {
@@ -35,7 +38,27 @@ func (t *Thread) GetBlockData(path string, block *repo.Block) ([]byte, error) {
 		log.Errorf("error getting file data: %s", err)
 		return nil, err
 	}
-	return crypto.DecryptAES(cipher, key)
+	return crypto.DecryptEnvelope(cipher, key, gopath.Base(path))
+}
+
+// GetBlockReader returns a reader which decrypts file data under an ipfs path as it is read.
+// The caller must close the reader.
+func (t *Thread) GetBlockReader(path string, block *repo.Block) (io.ReadCloser, error) {
+	key, err := t.GetBlockDataKey(block)
+	if err != nil {
+		return nil, err
+	}
+	cipher, err := util.GetReaderAtPath(t.ipfs(), path)
+	if err != nil {
+		log.Errorf("error getting file reader: %s", err)
+		return nil, err
+	}
+	plain, err := crypto.NewDecryptReader(cipher, key, gopath.Base(path))
+	if err != nil {
+		cipher.Close()
+		return nil, err
+	}
+	return &blockReader{Reader: plain, cipher: cipher}, nil
 }
 
 // GetFileDataBase64 returns file data encoded as base64 under an ipfs path
@@ -62,3 +85,13 @@ func (t *Thread) GetPhotoMetaData(id string, block *repo.Block) (*model.PhotoMet
 	}
 	return data, nil
 }
+
+// blockReader closes the underlying cipher reader
+type blockReader struct {
+	io.Reader
+	cipher io.Closer
+}
+
+func (r *blockReader) Close() error {
+	return r.cipher.Close()
+}
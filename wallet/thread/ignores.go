@@ -47,7 +47,7 @@ func (t *Thread) Ignore(dataId string) (mh.Multihash, error) {
 	}
 
 	// update head
-	if err := t.updateHead(id); err != nil {
+	if err := t.advanceHead(id, header.Parents); err != nil {
 		return nil, err
 	}
 
@@ -70,6 +70,11 @@ func (t *Thread) HandleIgnoreBlock(message *pb.Envelope, signed *pb.SignedThread
 		}
 	}
 
+	// the target may not be indexed yet, but it must be a well formed id
+	if _, err := targetId(content.DataId, "ignore"); err != nil {
+		return nil, err
+	}
+
 	// add to ipfs
 	addr, err := t.addBlock(message)
 	if err != nil {
@@ -117,16 +122,18 @@ func (t *Thread) HandleIgnoreBlock(message *pb.Envelope, signed *pb.SignedThread
 		return nil, err
 	}
 
+	// the follower walking back to us takes care of our parents
+	if following {
+		return addr, nil
+	}
+
 	// back prop
 	if err := t.FollowParents(content.Header.Parents); err != nil {
 		return nil, err
 	}
 
 	// handle HEAD
-	if following {
-		return addr, nil
-	}
-	if _, err := t.handleHead(id, content.Header.Parents, false); err != nil {
+	if err := t.handleHead(id, content.Header.Parents); err != nil {
 		return nil, err
 	}
 
@@ -0,0 +1,114 @@
+package thread
+
+import (
+	"fmt"
+	"github.com/golang/protobuf/proto"
+	"github.com/textileio/textile-go/pb"
+	"github.com/textileio/textile-go/repo"
+	mh "gx/ipfs/QmZyZDi491cCNTLfAhwcaDii2Kg4pwKRkhqQzURGDvY6ua/go-multihash"
+	"time"
+)
+
+// RevokeExternalInvite adds an outgoing invite revoke block, blockId is the external invite to revoke.
+// Anyone already holding the invite key can still decrypt the thread key, but members will
+// refuse their join and the inviter will tell them the invite is no longer valid.
+func (t *Thread) RevokeExternalInvite(blockId string) (mh.Multihash, error) {
+	t.mux.Lock()
+	defer t.mux.Unlock()
+
+	index := t.blocks().Get(blockId)
+	if index == nil || index.Type != repo.ExternalInviteBlock || index.ThreadId != t.Id {
+		return nil, ErrInviteNotFound
+	}
+	if t.blocks().GetByDataId(fmt.Sprintf("revoke-%s", blockId)) != nil {
+		return nil, ErrInviteRevoked
+	}
+
+	// build block
+	header, err := t.newBlockHeader(time.Now())
+	if err != nil {
+		return nil, err
+	}
+	content := &pb.ThreadInviteRevoke{
+		Header:  header,
+		BlockId: blockId,
+	}
+
+	// commit to ipfs
+	message, addr, err := t.commitBlock(content, pb.Message_THREAD_INVITE_REVOKE)
+	if err != nil {
+		return nil, err
+	}
+	id := addr.B58String()
+
+	// index it locally
+	dconf := &repo.DataBlockConfig{
+		DataId: fmt.Sprintf("revoke-%s", blockId),
+	}
+	if err := t.indexBlock(id, header, repo.InviteRevokeBlock, dconf); err != nil {
+		return nil, err
+	}
+
+	// update head
+	if err := t.advanceHead(id, header.Parents); err != nil {
+		return nil, err
+	}
+
+	// post it
+	t.post(message, id, t.Peers())
+
+	log.Debugf("revoked invite %s in %s: %s", blockId, t.Id, id)
+
+	// all done
+	return addr, nil
+}
+
+// HandleInviteRevokeBlock handles an incoming invite revoke block
+func (t *Thread) HandleInviteRevokeBlock(message *pb.Envelope, signed *pb.SignedThreadBlock, content *pb.ThreadInviteRevoke, following bool) (mh.Multihash, error) {
+	// unmarshal if needed
+	if content == nil {
+		content = new(pb.ThreadInviteRevoke)
+		if err := proto.Unmarshal(signed.Block, content); err != nil {
+			return nil, err
+		}
+	}
+
+	// add to ipfs
+	addr, err := t.addBlock(message)
+	if err != nil {
+		return nil, err
+	}
+	id := addr.B58String()
+
+	// check if we aleady have this block indexed
+	// (should only happen if a misbehaving peer keeps sending the same block)
+	index := t.blocks().Get(id)
+	if index != nil {
+		return nil, nil
+	}
+
+	// index it locally
+	dconf := &repo.DataBlockConfig{
+		DataId: fmt.Sprintf("revoke-%s", content.BlockId),
+	}
+	if err := t.indexBlock(id, content.Header, repo.InviteRevokeBlock, dconf); err != nil {
+		return nil, err
+	}
+
+	// the follower walking back to us takes care of our parents
+	if following {
+		return addr, nil
+	}
+
+	// back prop
+	if err := t.FollowParents(content.Header.Parents); err != nil {
+		return nil, err
+	}
+
+	// handle HEAD
+	if err := t.handleHead(id, content.Header.Parents); err != nil {
+		return nil, err
+	}
+
+	return addr, nil
+}
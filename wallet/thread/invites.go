@@ -57,7 +57,7 @@ func (t *Thread) AddInvite(inviteePk libp2pc.PubKey) (mh.Multihash, error) {
 	}
 
 	// update head
-	if err := t.updateHead(id); err != nil {
+	if err := t.advanceHead(id, header.Parents); err != nil {
 		return nil, err
 	}
 
@@ -114,16 +114,18 @@ func (t *Thread) HandleInviteBlock(message *pb.Envelope, signed *pb.SignedThread
 		return nil, err
 	}
 
+	// the follower walking back to us takes care of our parents
+	if following {
+		return addr, nil
+	}
+
 	// back prop
 	if err := t.FollowParents(content.Header.Parents); err != nil {
 		return nil, err
 	}
 
 	// handle HEAD
-	if following {
-		return addr, nil
-	}
-	if _, err := t.handleHead(id, content.Header.Parents, false); err != nil {
+	if err := t.handleHead(id, content.Header.Parents); err != nil {
 		return nil, err
 	}
 
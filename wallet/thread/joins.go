@@ -1,6 +1,7 @@
 package thread
 
 import (
+	"fmt"
 	"github.com/golang/protobuf/proto"
 	"github.com/segmentio/ksuid"
 	"github.com/textileio/textile-go/pb"
@@ -39,13 +40,16 @@ func (t *Thread) Join(inviterPk libp2pc.PubKey, blockId string) (mh.Multihash, e
 	}
 	id := addr.B58String()
 
-	// index it locally
-	if err := t.indexBlock(id, header, repo.JoinBlock, nil); err != nil {
+	// index it locally, the invite is tracked so external invite uses can be counted
+	dconf := &repo.DataBlockConfig{
+		DataId: fmt.Sprintf("join-%s", blockId),
+	}
+	if err := t.indexBlock(id, header, repo.JoinBlock, dconf); err != nil {
 		return nil, err
 	}
 
 	// update head
-	if err := t.updateHead(id); err != nil {
+	if err := t.advanceHead(id, header.Parents); err != nil {
 		return nil, err
 	}
 
@@ -108,9 +112,21 @@ func (t *Thread) HandleJoinBlock(message *pb.Envelope, signed *pb.SignedThreadBl
 		return nil, err
 	}
 
+	// refuse new members joining with an external invite that is no longer valid.
+	// skipped while following, since newer revokes and joins are already indexed.
+	refused := false
+	if !following {
+		if invite := t.blocks().Get(content.BlockId); invite != nil && invite.Type == repo.ExternalInviteBlock {
+			if err := t.ExternalInviteStatus(content.BlockId); err != nil {
+				log.Warningf("refusing join from %s via invite %s: %s", inviteeId.Pretty(), content.BlockId, err)
+				refused = true
+			}
+		}
+	}
+
 	// add invitee as a new local peer.
 	// double-check not self in case we're re-discovering the thread
-	if inviteeId.Pretty() != t.ipfs().Identity.Pretty() {
+	if !refused && inviteeId.Pretty() != t.ipfs().Identity.Pretty() {
 		newPeer := &repo.Peer{
 			Row:      ksuid.New().String(),
 			Id:       inviteeId.Pretty(),
@@ -123,34 +139,43 @@ func (t *Thread) HandleJoinBlock(message *pb.Envelope, signed *pb.SignedThreadBl
 		}
 	}
 
-	// index it locally
-	if err := t.indexBlock(id, content.Header, repo.JoinBlock, nil); err != nil {
+	// index it locally, refused joins don't count as uses of the invite
+	dataId := fmt.Sprintf("join-%s", content.BlockId)
+	if refused {
+		dataId = fmt.Sprintf("refused-%s", content.BlockId)
+	}
+	dconf := &repo.DataBlockConfig{
+		DataId: dataId,
+	}
+	if err := t.indexBlock(id, content.Header, repo.JoinBlock, dconf); err != nil {
 		return nil, err
 	}
 
+	// the follower walking back to us takes care of our parents
+	if following {
+		return addr, nil
+	}
+
 	// back prop
 	if err := t.FollowParents(content.Header.Parents); err != nil {
 		return nil, err
 	}
 
 	// handle HEAD
-	if following {
-		return addr, nil
+	if err := t.handleHead(id, content.Header.Parents); err != nil {
+		return nil, err
 	}
 
-	// echo merge (if needed) if we are the original inviter
-	var post bool
+	// echo a merge (if needed) if we are the original inviter,
+	// so the new peer catches up on blocks added since the invite
 	pk, err := t.ipfs().PrivateKey.GetPublic().Bytes()
 	if err != nil {
 		return nil, err
 	}
-	pks := libp2pc.ConfigEncodeKey(pk)
-	inviterPks := libp2pc.ConfigEncodeKey(content.InviterPk)
-	if pks == inviterPks {
-		post = true
-	}
-	if _, err := t.handleHead(id, content.Header.Parents, post); err != nil {
-		return nil, err
+	if libp2pc.ConfigEncodeKey(pk) == libp2pc.ConfigEncodeKey(content.InviterPk) {
+		if _, err := t.Merge(); err != nil {
+			return nil, err
+		}
 	}
 
 	return addr, nil
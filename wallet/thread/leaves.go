@@ -37,7 +37,7 @@ func (t *Thread) Leave() (mh.Multihash, error) {
 	}
 
 	// update head
-	if err := t.updateHead(id); err != nil {
+	if err := t.advanceHead(id, header.Parents); err != nil {
 		return nil, err
 	}
 
@@ -52,6 +52,14 @@ func (t *Thread) Leave() (mh.Multihash, error) {
 	if err := t.peers().DeleteByThreadId(t.Id); err != nil {
 		return nil, err
 	}
+	// delete search entries
+	if err := t.search().DeleteByThreadId(t.Id); err != nil {
+		return nil, err
+	}
+	// delete queued missing blocks
+	if err := t.missingBlocks().DeleteByThreadId(t.Id); err != nil {
+		return nil, err
+	}
 
 	log.Debugf("left %s", t.Id)
 
@@ -101,16 +109,18 @@ func (t *Thread) HandleLeaveBlock(message *pb.Envelope, signed *pb.SignedThreadB
 		return nil, err
 	}
 
+	// the follower walking back to us takes care of our parents
+	if following {
+		return addr, nil
+	}
+
 	// back prop
 	if err := t.FollowParents(content.Header.Parents); err != nil {
 		return nil, err
 	}
 
 	// handle HEAD
-	if following {
-		return addr, nil
-	}
-	if _, err := t.handleHead(id, content.Header.Parents, false); err != nil {
+	if err := t.handleHead(id, content.Header.Parents); err != nil {
 		return nil, err
 	}
 
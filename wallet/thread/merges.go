@@ -8,17 +8,25 @@ import (
 	"time"
 )
 
-// Merge adds an outgoing merge block, will only post to network if post is true
-func (t *Thread) Merge(head string, post bool) (mh.Multihash, error) {
+// Merge adds an outgoing merge block when the thread has more than one head.
+// Merges usually ride along with the next local block, this forces one now.
+func (t *Thread) Merge() (mh.Multihash, error) {
 	t.mux.Lock()
 	defer t.mux.Unlock()
 
+	heads, err := t.Heads()
+	if err != nil {
+		return nil, err
+	}
+	if len(heads) < 2 {
+		return nil, nil
+	}
+
 	// build block
 	header, err := t.newBlockHeader(time.Now())
 	if err != nil {
 		return nil, err
 	}
-	header.Parents = append(header.Parents, head)
 	content := &pb.ThreadMerge{
 		Header: header,
 	}
@@ -36,14 +44,12 @@ func (t *Thread) Merge(head string, post bool) (mh.Multihash, error) {
 	}
 
 	// update head
-	if err := t.updateHead(id); err != nil {
+	if err := t.advanceHead(id, header.Parents); err != nil {
 		return nil, err
 	}
 
 	// post it
-	if post {
-		t.post(message, id, t.Peers())
-	}
+	t.post(message, id, t.Peers())
 
 	log.Debugf("adding merge to %s: %s", t.Id, id)
 
@@ -80,16 +86,18 @@ func (t *Thread) HandleMergeBlock(message *pb.Envelope, signed *pb.SignedThreadB
 		return nil, err
 	}
 
+	// the follower walking back to us takes care of our parents
+	if following {
+		return addr, nil
+	}
+
 	// back prop
 	if err := t.FollowParents(content.Header.Parents); err != nil {
 		return nil, err
 	}
 
 	// handle HEAD
-	if following {
-		return addr, nil
-	}
-	if _, err := t.handleHead(id, content.Header.Parents, false); err != nil {
+	if err := t.handleHead(id, content.Header.Parents); err != nil {
 		return nil, err
 	}
 
@@ -0,0 +1,246 @@
+package thread
+
+import (
+	"errors"
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/textileio/textile-go/pb"
+	"github.com/textileio/textile-go/repo"
+	mh "gx/ipfs/QmZyZDi491cCNTLfAhwcaDii2Kg4pwKRkhqQzURGDvY6ua/go-multihash"
+	"time"
+)
+
+// maxNameLength and maxDescriptionLength bound thread metadata
+const (
+	maxNameLength        = 128
+	maxDescriptionLength = 1024
+)
+
+// Rename adds an outgoing metadata block with a new thread name
+func (t *Thread) Rename(name string) (mh.Multihash, error) {
+	if name == "" {
+		return nil, errors.New("thread name cannot be empty")
+	}
+	if len(name) > maxNameLength {
+		return nil, errors.New("thread name is too long")
+	}
+	return t.addMetadata(func(meta *metadata) {
+		meta.name = name
+	})
+}
+
+// Describe adds an outgoing metadata block with a new thread description
+func (t *Thread) Describe(description string) (mh.Multihash, error) {
+	if len(description) > maxDescriptionLength {
+		return nil, errors.New("thread description is too long")
+	}
+	return t.addMetadata(func(meta *metadata) {
+		meta.description = description
+	})
+}
+
+// SetCover adds an outgoing metadata block that uses a photo block as the thread cover
+func (t *Thread) SetCover(blockId string) (mh.Multihash, error) {
+	if !t.isPhoto(blockId) {
+		return nil, errors.New("cover must be a photo in this thread")
+	}
+	return t.addMetadata(func(meta *metadata) {
+		meta.coverId = blockId
+	})
+}
+
+// Metadata returns the current thread name, description and cover
+func (t *Thread) Metadata() (string, string, string) {
+	t.metalk.Lock()
+	defer t.metalk.Unlock()
+	return t.Name, t.Description, t.CoverId
+}
+
+// metadata is the replaceable part of a thread
+type metadata struct {
+	name        string
+	description string
+	coverId     string
+}
+
+// addMetadata adds an outgoing metadata block, which replaces all thread metadata.
+// update is applied to the current metadata once no other local block is being written.
+func (t *Thread) addMetadata(update func(meta *metadata)) (mh.Multihash, error) {
+	t.mux.Lock()
+	defer t.mux.Unlock()
+
+	meta := new(metadata)
+	meta.name, meta.description, meta.coverId = t.Metadata()
+	update(meta)
+	name, description, coverId := meta.name, meta.description, meta.coverId
+
+	// encrypt name and description with thread pk
+	nameCipher, err := t.Encrypt([]byte(name))
+	if err != nil {
+		return nil, err
+	}
+	descriptionCipher, err := t.Encrypt([]byte(description))
+	if err != nil {
+		return nil, err
+	}
+
+	// build block
+	header, err := t.newBlockHeader(time.Now())
+	if err != nil {
+		return nil, err
+	}
+	content := &pb.ThreadMetadata{
+		Header:            header,
+		NameCipher:        nameCipher,
+		DescriptionCipher: descriptionCipher,
+		CoverId:           coverId,
+	}
+
+	// commit to ipfs
+	message, addr, err := t.commitBlock(content, pb.Message_THREAD_METADATA)
+	if err != nil {
+		return nil, err
+	}
+	id := addr.B58String()
+
+	// apply it before indexing, so listeners see the new metadata
+	date, err := ptypes.Timestamp(header.Date)
+	if err != nil {
+		return nil, err
+	}
+	if err := t.applyMetadata(id, date, header.Parents, name, description, coverId); err != nil {
+		return nil, err
+	}
+
+	// index it locally
+	if err := t.indexBlock(id, header, repo.MetadataBlock, nil); err != nil {
+		return nil, err
+	}
+
+	// update head
+	if err := t.advanceHead(id, header.Parents); err != nil {
+		return nil, err
+	}
+
+	// post it
+	t.post(message, id, t.Peers())
+
+	log.Debugf("metadata added to %s: %s", t.Id, id)
+
+	// all done
+	return addr, nil
+}
+
+// HandleMetadataBlock handles an incoming metadata block
+func (t *Thread) HandleMetadataBlock(message *pb.Envelope, signed *pb.SignedThreadBlock, content *pb.ThreadMetadata, following bool) (mh.Multihash, error) {
+	// unmarshal if needed
+	if content == nil {
+		content = new(pb.ThreadMetadata)
+		if err := proto.Unmarshal(signed.Block, content); err != nil {
+			return nil, err
+		}
+	}
+
+	// decrypt name and description, rejecting oversized values before storing anything
+	name, err := t.Decrypt(content.NameCipher)
+	if err != nil {
+		return nil, err
+	}
+	description, err := t.Decrypt(content.DescriptionCipher)
+	if err != nil {
+		return nil, err
+	}
+	if len(name) > maxNameLength {
+		return nil, errors.New("thread name is too long")
+	}
+	if len(description) > maxDescriptionLength {
+		return nil, errors.New("thread description is too long")
+	}
+
+	// add to ipfs
+	addr, err := t.addBlock(message)
+	if err != nil {
+		return nil, err
+	}
+	id := addr.B58String()
+
+	// check if we aleady have this block indexed
+	// (should only happen if a misbehaving peer keeps sending the same block)
+	index := t.blocks().Get(id)
+	if index != nil {
+		return nil, nil
+	}
+
+	// drop covers that aren't photos in this thread. a cover from history
+	// may not be indexed yet, so only known blocks can be ruled out.
+	coverId := content.CoverId
+	if coverId != "" {
+		if _, err := mh.FromB58String(coverId); err != nil || (t.blocks().Get(coverId) != nil && !t.isPhoto(coverId)) {
+			log.Warningf("ignoring invalid cover %s in metadata block %s", coverId, id)
+			coverId = ""
+		}
+	}
+
+	// apply it, even from history, since it may still be the latest
+	date, err := ptypes.Timestamp(content.Header.Date)
+	if err != nil {
+		return nil, err
+	}
+	if err := t.applyMetadata(id, date, content.Header.Parents, string(name), string(description), coverId); err != nil {
+		return nil, err
+	}
+
+	// index it locally
+	if err := t.indexBlock(id, content.Header, repo.MetadataBlock, nil); err != nil {
+		return nil, err
+	}
+
+	// the follower walking back to us takes care of our parents
+	if following {
+		return addr, nil
+	}
+
+	// back prop
+	if err := t.FollowParents(content.Header.Parents); err != nil {
+		return nil, err
+	}
+
+	// handle HEAD
+	if err := t.handleHead(id, content.Header.Parents); err != nil {
+		return nil, err
+	}
+
+	return addr, nil
+}
+
+// applyMetadata stores metadata if it's newer than what we have,
+// blocks are ordered like newerBlock so every member settles on the same one
+func (t *Thread) applyMetadata(id string, date time.Time, parents []string, name string, description string, coverId string) error {
+	t.metalk.Lock()
+	defer t.metalk.Unlock()
+
+	// the block index only keeps seconds
+	next := &repo.Block{Id: id, Date: time.Unix(date.Unix(), 0), Parents: parents}
+	if t.metaId != "" {
+		if current := t.blocks().Get(t.metaId); current != nil && !t.newerBlock(next, current) {
+			return nil
+		}
+	}
+	if name == "" {
+		name = t.Name
+	}
+	if err := t.updateMeta(name, description, coverId, id); err != nil {
+		return err
+	}
+	t.Name = name
+	t.Description = description
+	t.CoverId = coverId
+	t.metaId = id
+	return nil
+}
+
+// isPhoto returns whether a block is a photo in this thread
+func (t *Thread) isPhoto(blockId string) bool {
+	index := t.blocks().Get(blockId)
+	return index != nil && index.Type == repo.PhotoBlock && index.ThreadId == t.Id
+}
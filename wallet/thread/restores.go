@@ -0,0 +1,157 @@
+package thread
+
+import (
+	"errors"
+	"fmt"
+	"github.com/golang/protobuf/proto"
+	"github.com/segmentio/ksuid"
+	"github.com/textileio/textile-go/pb"
+	"github.com/textileio/textile-go/repo"
+	"gx/ipfs/QmZoWKhxUmZ2seW4BzX6fJkNR8hh9PsGModr7q171yq2SS/go-libp2p-peer"
+	mh "gx/ipfs/QmZyZDi491cCNTLfAhwcaDii2Kg4pwKRkhqQzURGDvY6ua/go-multihash"
+	libp2pc "gx/ipfs/QmaPbCnUMBohSGo3KnxEa2bHqyJVVeEEcwtqJAYxerieBo/go-libp2p-crypto"
+	"time"
+)
+
+// Restore adds an outgoing restore block, which undoes an ignore of the target block
+func (t *Thread) Restore(blockId string) (mh.Multihash, error) {
+	if !t.ignored(blockId) {
+		return nil, errors.New("block is not ignored")
+	}
+
+	t.mux.Lock()
+	defer t.mux.Unlock()
+
+	// blockId is a fellow block id,
+	// prefixed like ignores so both can be found by target
+	dataId := fmt.Sprintf("restore-%s", blockId)
+
+	// build block
+	header, err := t.newBlockHeader(time.Now())
+	if err != nil {
+		return nil, err
+	}
+	content := &pb.ThreadRestore{
+		Header: header,
+		DataId: dataId,
+	}
+
+	// commit to ipfs
+	message, addr, err := t.commitBlock(content, pb.Message_THREAD_RESTORE)
+	if err != nil {
+		return nil, err
+	}
+	id := addr.B58String()
+
+	// index it locally
+	dconf := &repo.DataBlockConfig{
+		DataId: dataId,
+	}
+	if err := t.indexBlock(id, header, repo.RestoreBlock, dconf); err != nil {
+		return nil, err
+	}
+
+	// update head
+	if err := t.advanceHead(id, header.Parents); err != nil {
+		return nil, err
+	}
+
+	// post it
+	t.post(message, id, t.Peers())
+
+	log.Debugf("restore added to %s: %s", t.Id, id)
+
+	// all done
+	return addr, nil
+}
+
+// HandleRestoreBlock handles an incoming restore block
+func (t *Thread) HandleRestoreBlock(message *pb.Envelope, signed *pb.SignedThreadBlock, content *pb.ThreadRestore, following bool) (mh.Multihash, error) {
+	// unmarshal if needed
+	if content == nil {
+		content = new(pb.ThreadRestore)
+		if err := proto.Unmarshal(signed.Block, content); err != nil {
+			return nil, err
+		}
+	}
+
+	// restores must target a block in this thread
+	if _, err := t.blockTarget(content.DataId, "restore"); err != nil {
+		return nil, err
+	}
+
+	// add to ipfs
+	addr, err := t.addBlock(message)
+	if err != nil {
+		return nil, err
+	}
+	id := addr.B58String()
+
+	// check if we aleady have this block indexed
+	// (should only happen if a misbehaving peer keeps sending the same block)
+	index := t.blocks().Get(id)
+	if index != nil {
+		return nil, nil
+	}
+
+	// get the author id
+	authorPk, err := libp2pc.UnmarshalPublicKey(content.Header.AuthorPk)
+	if err != nil {
+		return nil, err
+	}
+	authorId, err := peer.IDFromPublicKey(authorPk)
+	if err != nil {
+		return nil, err
+	}
+
+	// add author as a new local peer, just in case we haven't found this peer yet.
+	// double-check not self in case we're re-discovering the thread
+	if authorId.Pretty() != t.ipfs().Identity.Pretty() {
+		newPeer := &repo.Peer{
+			Row:      ksuid.New().String(),
+			Id:       authorId.Pretty(),
+			ThreadId: libp2pc.ConfigEncodeKey(content.Header.ThreadPk),
+			PubKey:   content.Header.AuthorPk,
+		}
+		if err := t.peers().Add(newPeer); err != nil {
+			// TODO: #202 (Properly handle database/sql errors)
+			log.Warningf("peer with id %s already exists in thread %s", newPeer.Id, t.Id)
+		}
+	}
+
+	// index it locally
+	dconf := &repo.DataBlockConfig{
+		DataId: content.DataId,
+	}
+	if err := t.indexBlock(id, content.Header, repo.RestoreBlock, dconf); err != nil {
+		return nil, err
+	}
+
+	// the follower walking back to us takes care of our parents
+	if following {
+		return addr, nil
+	}
+
+	// back prop
+	if err := t.FollowParents(content.Header.Parents); err != nil {
+		return nil, err
+	}
+
+	// handle HEAD
+	if err := t.handleHead(id, content.Header.Parents); err != nil {
+		return nil, err
+	}
+
+	return addr, nil
+}
+
+// ignored returns whether the latest ignore or restore targeting a block is an ignore
+func (t *Thread) ignored(blockId string) bool {
+	return t.ignoredBy(t.blocks().ListByDataIds(t.Id, []string{"ignore-" + blockId, "restore-" + blockId}))
+}
+
+// ignoredBy returns whether the latest of a block's ignores and restores is an ignore
+func (t *Thread) ignoredBy(marks []repo.Block) bool {
+	latest := t.latestBlock(marks)
+	return latest != nil && latest.Type == repo.IgnoreBlock
+}
@@ -0,0 +1,133 @@
+package thread
+
+import (
+	"github.com/golang/protobuf/proto"
+	"github.com/textileio/textile-go/pb"
+	"github.com/textileio/textile-go/util"
+)
+
+// MaxSyncBatch caps the number of blocks served in one sync response
+const MaxSyncBatch = 100
+
+// BlocksSince walks back from want, returning up to limit blocks a peer holding have is missing.
+// Blocks are returned newest first, along with their parents so the peer can order them.
+func (t *Thread) BlocksSince(want []string, have []string, limit int) []*pb.SyncBlock {
+	if limit <= 0 || limit > MaxSyncBatch {
+		limit = MaxSyncBatch
+	}
+	seen := make(map[string]bool)
+	for _, id := range have {
+		seen[id] = true
+	}
+	var blocks []*pb.SyncBlock
+	queue := append([]string{}, want...)
+	for len(queue) > 0 && len(blocks) < limit {
+		id := queue[0]
+		queue = queue[1:]
+		if id == "" || seen[id] {
+			continue
+		}
+		seen[id] = true
+
+		// only serve blocks indexed in this thread
+		index := t.blocks().Get(id)
+		if index == nil || index.ThreadId != t.Id {
+			continue
+		}
+		envb, err := util.GetDataAtPath(t.ipfs(), id)
+		if err != nil {
+			log.Warningf("error loading block %s for sync: %s", id, err)
+			continue
+		}
+		blocks = append(blocks, &pb.SyncBlock{
+			Id:       id,
+			Parents:  index.Parents,
+			Envelope: envb,
+		})
+		queue = append(queue, index.Parents...)
+	}
+	return blocks
+}
+
+// ApplyBlocks processes a batch of blocks fetched from a peer, parents first.
+// It returns the number of new blocks and the parents that are still missing.
+func (t *Thread) ApplyBlocks(blocks []*pb.SyncBlock) (int, []string, error) {
+	byId := make(map[string]*pb.SyncBlock)
+	for _, block := range blocks {
+		byId[block.Id] = block
+	}
+
+	var count int
+	done := make(map[string]bool)
+	var apply func(block *pb.SyncBlock) error
+	apply = func(block *pb.SyncBlock) error {
+		if done[block.Id] {
+			return nil
+		}
+		done[block.Id] = true
+
+		// parents in the batch go first so following stops at indexed blocks
+		for _, parent := range block.Parents {
+			if next, ok := byId[parent]; ok {
+				if err := apply(next); err != nil {
+					return err
+				}
+			}
+		}
+		if t.blocks().Get(block.Id) != nil {
+			return nil
+		}
+		env := new(pb.Envelope)
+		if err := proto.Unmarshal(block.Envelope, env); err != nil {
+			return err
+		}
+		if err := t.handleEnvelope(env); err != nil {
+			return err
+		}
+		count++
+		return nil
+	}
+	for _, block := range blocks {
+		if err := apply(block); err != nil {
+			return count, nil, err
+		}
+	}
+
+	// collect the edge of the batch so the next request can pick up there
+	var missing []string
+	for _, block := range blocks {
+		index := t.blocks().Get(block.Id)
+		if index == nil {
+			continue
+		}
+		for _, parent := range index.Parents {
+			if parent != "" && !done[parent] && t.blocks().Get(parent) == nil {
+				done[parent] = true
+				missing = append(missing, parent)
+			}
+		}
+	}
+	return count, missing, nil
+}
+
+// AdoptHeads adds heads from a peer to our own once their blocks are indexed,
+// skipping any we have already built on
+func (t *Thread) AdoptHeads(heads []string) error {
+	for _, head := range heads {
+		index := t.blocks().Get(head)
+		if index == nil {
+			continue
+		}
+		current, err := t.Heads()
+		if err != nil {
+			return err
+		}
+		if len(t.ancestorHeads(current, []string{head})) > 0 {
+			continue
+		}
+		if err := t.handleHead(head, index.Parents); err != nil {
+			return err
+		}
+	}
+	return nil
+}
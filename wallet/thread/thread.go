@@ -11,6 +11,7 @@ import (
 	"github.com/textileio/textile-go/pb"
 	"github.com/textileio/textile-go/repo"
 	"github.com/textileio/textile-go/util"
+	"gx/ipfs/QmZoWKhxUmZ2seW4BzX6fJkNR8hh9PsGModr7q171yq2SS/go-libp2p-peer"
 	mh "gx/ipfs/QmZyZDi491cCNTLfAhwcaDii2Kg4pwKRkhqQzURGDvY6ua/go-multihash"
 	libp2pc "gx/ipfs/QmaPbCnUMBohSGo3KnxEa2bHqyJVVeEEcwtqJAYxerieBo/go-libp2p-crypto"
 	"gx/ipfs/Qmb8jW1F6ZVyYPW1epc2GFRipmd3S8tJ48pZKBVPzVqj9T/go-ipfs/core"
@@ -21,14 +22,22 @@ import (
 
 var log = logging.MustGetLogger("thread")
 
+// headSearchLimit bounds how many blocks are walked when looking for heads an incoming block descends from
+const headSearchLimit = 500
+
 // Config is used to construct a Thread
 type Config struct {
 	RepoPath      string
 	Ipfs          func() *core.IpfsNode
 	Blocks        func() repo.BlockStore
 	Peers         func() repo.PeerStore
+	Contacts      func() repo.ContactStore
+	Search        func() repo.SearchStore
+	MissingBlocks func() repo.MissingBlockStore
+	AlbumPhotos   func() repo.AlbumPhotoStore
 	GetHead       func() (string, error)
 	UpdateHead    func(head string) error
+	UpdateMeta    func(name string, description string, coverId string, metaId string) error
 	Publish       func(payload []byte) error
 	Send          func(message *pb.Envelope, peerId string, hash *string) error
 	NewEnvelope   func(message *pb.Message) (*pb.Envelope, error)
@@ -46,19 +55,32 @@ type Update struct {
 type Thread struct {
 	Id            string
 	Name          string
+	Description   string
+	CoverId       string
 	PrivKey       libp2pc.PrivKey
+	metaId        string
 	updates       chan Update
 	repoPath      string
 	ipfs          func() *core.IpfsNode
 	blocks        func() repo.BlockStore
 	peers         func() repo.PeerStore
+	contacts      func() repo.ContactStore
+	search        func() repo.SearchStore
+	missingBlocks func() repo.MissingBlockStore
+	albumPhotos   func() repo.AlbumPhotoStore
 	GetHead       func() (string, error)
 	updateHead    func(head string) error
+	updateMeta    func(name string, description string, coverId string, metaId string) error
 	publish       func(payload []byte) error
 	send          func(message *pb.Envelope, peerId string, hash *string) error
 	newEnvelope   func(message *pb.Message) (*pb.Envelope, error)
 	putPinRequest func(id string) error
+	searchQueue   map[string]struct{}
+	searchWorking bool
 	mux           sync.Mutex
+	headlk        sync.Mutex
+	metalk        sync.Mutex
+	searchlk      sync.Mutex
 }
 
 // NewThread create a new Thread from a repo model and config
@@ -70,14 +92,22 @@ func NewThread(model *repo.Thread, config *Config) (*Thread, error) {
 	return &Thread{
 		Id:            model.Id,
 		Name:          model.Name,
+		Description:   model.Description,
+		CoverId:       model.CoverId,
 		PrivKey:       sk,
+		metaId:        model.MetaId,
 		updates:       make(chan Update),
 		repoPath:      config.RepoPath,
 		ipfs:          config.Ipfs,
 		blocks:        config.Blocks,
 		peers:         config.Peers,
+		contacts:      config.Contacts,
+		search:        config.Search,
+		missingBlocks: config.MissingBlocks,
+		albumPhotos:   config.AlbumPhotos,
 		GetHead:       config.GetHead,
 		updateHead:    config.UpdateHead,
+		updateMeta:    config.UpdateMeta,
 		publish:       config.Publish,
 		send:          config.Send,
 		newEnvelope:   config.NewEnvelope,
@@ -95,19 +125,107 @@ func (t *Thread) Close() {
 	close(t.updates)
 }
 
-// Blocks paginates blocks from the datastore
+// Blocks paginates blocks from the datastore, dropping ignored blocks and applying caption edits
 func (t *Thread) Blocks(offsetId string, limit int, bType repo.BlockType) []repo.Block {
 	query := fmt.Sprintf("threadId='%s' and type=%d", t.Id, bType)
+	blocks := t.blocks().List(offsetId, limit, query)
+	if len(blocks) == 0 {
+		return nil
+	}
+
+	// load ignores, restores and captions for the whole page at once
+	var dataIds []string
+	for _, block := range blocks {
+		dataIds = append(dataIds, "ignore-"+block.Id, "restore-"+block.Id)
+		if block.Type == repo.PhotoBlock {
+			dataIds = append(dataIds, "caption-"+block.Id)
+		}
+	}
+	related := make(map[string][]repo.Block)
+	for _, block := range t.blocks().ListByDataIds(t.Id, dataIds) {
+		related[block.DataId] = append(related[block.DataId], block)
+	}
+
 	var filtered []repo.Block
-	for _, block := range t.blocks().List(offsetId, limit, query) {
-		ignored := t.blocks().GetByDataId(fmt.Sprintf("ignore-%s", block.Id))
-		if ignored == nil {
-			filtered = append(filtered, block)
+	for _, block := range blocks {
+		var marks []repo.Block
+		marks = append(marks, related["ignore-"+block.Id]...)
+		marks = append(marks, related["restore-"+block.Id]...)
+		if t.ignoredBy(marks) {
+			continue
 		}
+		if block.Type == repo.PhotoBlock {
+			if caption := t.authorCaption(&block, related["caption-"+block.Id]); caption != nil {
+				block.DataCaptionCipher = caption.DataCaptionCipher
+			}
+		}
+		filtered = append(filtered, block)
 	}
 	return filtered
 }
 
+// resolve applies the latest caption edit to a photo block
+func (t *Thread) resolve(block repo.Block) repo.Block {
+	if block.Type != repo.PhotoBlock {
+		return block
+	}
+	if caption := t.latestCaption(&block); caption != nil {
+		block.DataCaptionCipher = caption.DataCaptionCipher
+	}
+	return block
+}
+
+// targetId returns the block id named by a prefixed data id, e.g. ignore-<id>
+func targetId(dataId string, prefix string) (string, error) {
+	id := strings.TrimPrefix(dataId, prefix+"-")
+	if id == dataId {
+		return "", errors.New(fmt.Sprintf("invalid %s data id", prefix))
+	}
+	if _, err := mh.FromB58String(id); err != nil {
+		return "", errors.New(fmt.Sprintf("invalid %s target", prefix))
+	}
+	return id, nil
+}
+
+// blockTarget returns the block named by a prefixed data id, if it's indexed in this thread
+func (t *Thread) blockTarget(dataId string, prefix string) (*repo.Block, error) {
+	id, err := targetId(dataId, prefix)
+	if err != nil {
+		return nil, err
+	}
+	target := t.blocks().Get(id)
+	if target == nil || target.ThreadId != t.Id {
+		return nil, errors.New(fmt.Sprintf("%s target is not a block in this thread", prefix))
+	}
+	return target, nil
+}
+
+// latestBlock picks the newest block, see newerBlock
+func (t *Thread) latestBlock(blocks []repo.Block) *repo.Block {
+	var latest *repo.Block
+	for i := range blocks {
+		if latest == nil || t.newerBlock(&blocks[i], latest) {
+			latest = &blocks[i]
+		}
+	}
+	return latest
+}
+
+// newerBlock orders blocks by date. The index only keeps seconds, so ties go to
+// the block built on top of the other, then to the higher id, so every peer agrees.
+func (t *Thread) newerBlock(a *repo.Block, b *repo.Block) bool {
+	if !a.Date.Equal(b.Date) {
+		return a.Date.After(b.Date)
+	}
+	if len(t.ancestorHeads(a.Parents, []string{b.Id})) > 0 {
+		return true
+	}
+	if len(t.ancestorHeads(b.Parents, []string{a.Id})) > 0 {
+		return false
+	}
+	return a.Id > b.Id
+}
+
 // Peers returns locally known peers in this thread
 func (t *Thread) Peers() []repo.Peer {
 	query := fmt.Sprintf("threadId='%s'", t.Id)
@@ -129,64 +247,44 @@ func (t *Thread) Verify(signed *pb.SignedThreadBlock) error {
 	return crypto.Verify(t.PrivKey.GetPublic(), signed.Block, signed.ThreadSig)
 }
 
-// FollowParents tries to follow a list of chains of block ids, processing along the way
-func (t *Thread) FollowParents(parents []string) error {
-	for _, parent := range parents {
-		if err := t.followParent(parent); err != nil {
-			return err
-		}
-	}
-	return nil
-}
-
-// followParent tries to follow a chain of block ids, processing along the way
-func (t *Thread) followParent(parent string) error {
-	// first update?
-	if parent == "" {
-		log.Debugf("found genesis block, aborting")
-		return nil
-	}
-
-	// check if we aleady have this block indexed
-	index := t.blocks().Get(parent)
-	if index != nil {
-		log.Debugf("block %s exists, aborting", parent)
-		return nil
-	}
-
-	// download it
-	serialized, err := util.GetDataAtPath(t.ipfs(), parent)
+// handleEnvelope verifies a block from another peer and processes it as part of its chain
+func (t *Thread) handleEnvelope(env *pb.Envelope) error {
+	signed, err := t.verifyEnvelope(env)
 	if err != nil {
 		return err
 	}
-	env := new(pb.Envelope)
-	if err := proto.Unmarshal(serialized, env); err != nil {
-		return err
-	}
+	return t.handleFollowed(env, signed)
+}
 
+// verifyEnvelope checks the author and thread signatures of a block
+func (t *Thread) verifyEnvelope(env *pb.Envelope) (*pb.SignedThreadBlock, error) {
 	// verify author sig
 	messageb, err := proto.Marshal(env.Message)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	authorPk, err := libp2pc.UnmarshalPublicKey(env.Pk)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	if err := crypto.Verify(authorPk, messageb, env.Sig); err != nil {
-		return err
+		return nil, err
 	}
 
 	// verify thread sig
 	signed := new(pb.SignedThreadBlock)
 	if err := ptypes.UnmarshalAny(env.Message.Payload, signed); err != nil {
-		return err
+		return nil, err
 	}
 	if err := t.Verify(signed); err != nil {
-		return err
+		return nil, err
 	}
+	return signed, nil
+}
 
-	// handle each type
+// handleFollowed processes a verified block found while following a chain
+func (t *Thread) handleFollowed(env *pb.Envelope, signed *pb.SignedThreadBlock) error {
+	var err error
 	switch env.Message.Type {
 	case pb.Message_THREAD_INVITE:
 		if _, err = t.HandleInviteBlock(env, signed, nil, true); err != nil {
@@ -212,10 +310,34 @@ func (t *Thread) followParent(parent string) error {
 		if _, err = t.HandleIgnoreBlock(env, signed, nil, true); err != nil {
 			return err
 		}
+	case pb.Message_THREAD_INVITE_REVOKE:
+		if _, err = t.HandleInviteRevokeBlock(env, signed, nil, true); err != nil {
+			return err
+		}
+	case pb.Message_THREAD_METADATA:
+		if _, err = t.HandleMetadataBlock(env, signed, nil, true); err != nil {
+			return err
+		}
+	case pb.Message_THREAD_ALBUM:
+		if _, err = t.HandleAlbumBlock(env, signed, nil, true); err != nil {
+			return err
+		}
+	case pb.Message_THREAD_ALBUM_MEMBER:
+		if _, err = t.HandleAlbumMemberBlock(env, signed, nil, true); err != nil {
+			return err
+		}
 	case pb.Message_THREAD_MERGE:
 		if _, err = t.HandleMergeBlock(env, signed, nil, true); err != nil {
 			return err
 		}
+	case pb.Message_THREAD_RESTORE:
+		if _, err = t.HandleRestoreBlock(env, signed, nil, true); err != nil {
+			return err
+		}
+	case pb.Message_THREAD_CAPTION:
+		if _, err = t.HandleCaptionBlock(env, signed, nil, true); err != nil {
+			return err
+		}
 	default:
 		return errors.New(fmt.Sprintf("invalid message type: %s", env.Message.Type))
 	}
@@ -224,11 +346,14 @@ func (t *Thread) followParent(parent string) error {
 
 // newBlockHeader creates a new header
 func (t *Thread) newBlockHeader(date time.Time) (*pb.ThreadBlockHeader, error) {
-	// get current HEAD
-	head, err := t.GetHead()
+	// get current heads, a new block merges all of them
+	heads, err := t.Heads()
 	if err != nil {
 		return nil, err
 	}
+	if len(heads) == 0 {
+		heads = []string{""}
+	}
 
 	// get our own public key
 	threadPk, err := t.PrivKey.GetPublic().Bytes()
@@ -250,7 +375,7 @@ func (t *Thread) newBlockHeader(date time.Time) (*pb.ThreadBlockHeader, error) {
 
 	return &pb.ThreadBlockHeader{
 		Date:     pdate,
-		Parents:  strings.Split(string(head), ","),
+		Parents:  heads,
 		ThreadPk: threadPk,
 		AuthorPk: authorPk,
 	}, nil
@@ -341,40 +466,286 @@ func (t *Thread) indexBlock(id string, header *pb.ThreadBlockHeader, blockType r
 		return err
 	}
 
+	// remember the author across threads
+	if err := t.indexContact(header.AuthorPk, date); err != nil {
+		log.Warningf("error indexing contact for block %s: %s", id, err)
+	}
+
+	// keep the local search index current
+	switch blockType {
+	case repo.PhotoBlock:
+		if err := t.indexSearch(index); err != nil {
+			log.Warningf("error indexing block %s for search: %s", id, err)
+		}
+	case repo.IgnoreBlock, repo.RestoreBlock, repo.CaptionBlock:
+		target := index.DataId[strings.Index(index.DataId, "-")+1:]
+		if err := t.reindexSearch(target); err != nil {
+			log.Warningf("error updating block %s in search: %s", target, err)
+		}
+	}
+
 	// notify listeners
 	t.pushUpdate(*index)
 
 	return nil
 }
 
-// handleHead determines whether or not a thread can be fast-forwarded or if a merge block is needed.
-// parents are the parents of the incoming chain.
-// If a merge is needed and post is true, it will be broadcasted to the network (joins only)
-func (t *Thread) handleHead(inboundId string, parents []string, post bool) (mh.Multihash, error) {
-	// get current HEAD
+// indexSearch adds a photo block's decrypted caption to the search index, and queues
+// its name, date and location to be added once the metadata has been fetched
+func (t *Thread) indexSearch(block *repo.Block) error {
+	entry, err := t.searchEntry(block)
+	if err != nil {
+		return err
+	}
+	if err := t.search().Index(entry); err != nil {
+		return err
+	}
+	t.queueSearchMeta(block.Id)
+	return nil
+}
+
+// searchEntry returns the search entry for a photo block, without metadata
+func (t *Thread) searchEntry(block *repo.Block) (*repo.SearchEntry, error) {
+	pkb, err := libp2pc.ConfigDecodeKey(block.AuthorPk)
+	if err != nil {
+		return nil, err
+	}
+	pk, err := libp2pc.UnmarshalPublicKey(pkb)
+	if err != nil {
+		return nil, err
+	}
+	authorId, err := peer.IDFromPublicKey(pk)
+	if err != nil {
+		return nil, err
+	}
+	entry := &repo.SearchEntry{
+		BlockId:  block.Id,
+		ThreadId: block.ThreadId,
+		DataId:   block.DataId,
+		AuthorId: authorId.Pretty(),
+		Date:     block.Date,
+	}
+	if len(block.DataCaptionCipher) > 0 {
+		caption, err := t.Decrypt(block.DataCaptionCipher)
+		if err != nil {
+			return nil, err
+		}
+		entry.Caption = string(caption)
+	}
+	return entry, nil
+}
+
+// queueSearchMeta queues a photo block for the metadata worker, starting it if needed.
+// metadata may need to come from the network, so one worker per thread fetches it.
+func (t *Thread) queueSearchMeta(blockId string) {
+	t.searchlk.Lock()
+	defer t.searchlk.Unlock()
+	if t.searchQueue == nil {
+		t.searchQueue = make(map[string]struct{})
+	}
+	t.searchQueue[blockId] = struct{}{}
+	if !t.searchWorking {
+		t.searchWorking = true
+		go t.searchMetaWorker()
+	}
+}
+
+// searchMetaWorker indexes metadata for queued photo blocks until the queue is empty
+func (t *Thread) searchMetaWorker() {
+	for {
+		t.searchlk.Lock()
+		var next string
+		for id := range t.searchQueue {
+			next = id
+			break
+		}
+		if next == "" {
+			t.searchWorking = false
+			t.searchlk.Unlock()
+			return
+		}
+		delete(t.searchQueue, next)
+		t.searchlk.Unlock()
+
+		if err := t.indexSearchMeta(next); err != nil {
+			log.Warningf("error indexing metadata for block %s: %s", next, err)
+		}
+	}
+}
+
+// indexSearchMeta adds a photo's metadata to its search entry. the block and its caption
+// are re-read after the fetch, so a caption changed in the meantime isn't overwritten.
+func (t *Thread) indexSearchMeta(blockId string) error {
+	block := t.blocks().Get(blockId)
+	if block == nil || block.Type != repo.PhotoBlock {
+		return nil
+	}
+	meta, err := t.GetPhotoMetaData(block.DataId, block)
+	if err != nil {
+		return err
+	}
+	block = t.blocks().Get(blockId)
+	if block == nil || t.ignored(blockId) {
+		return nil
+	}
+	resolved := t.resolve(*block)
+	entry, err := t.searchEntry(&resolved)
+	if err != nil {
+		return err
+	}
+	entry.Name = meta.Name
+	entry.Taken = meta.Created
+	entry.Latitude = meta.Latitude
+	entry.Longitude = meta.Longitude
+	return t.search().Index(entry)
+}
+
+// BackfillSearch indexes photos added before the search index existed
+func (t *Thread) BackfillSearch() error {
+	query := fmt.Sprintf("threadId='%s' and type=%d", t.Id, repo.PhotoBlock)
+	for _, block := range t.blocks().List("", -1, query) {
+		if t.search().Has(block.Id) || t.ignored(block.Id) {
+			continue
+		}
+		resolved := t.resolve(block)
+		if err := t.indexSearch(&resolved); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// reindexSearch updates a photo in the search index after it's ignored, restored or re-captioned
+func (t *Thread) reindexSearch(blockId string) error {
+	if t.ignored(blockId) {
+		return t.search().Delete(blockId)
+	}
+	block := t.blocks().Get(blockId)
+	if block == nil || block.Type != repo.PhotoBlock {
+		return nil
+	}
+	resolved := t.resolve(*block)
+	return t.indexSearch(&resolved)
+}
+
+// indexContact adds a block author as a contact, or updates when they were last seen
+func (t *Thread) indexContact(authorPk []byte, date time.Time) error {
+	pk, err := libp2pc.UnmarshalPublicKey(authorPk)
+	if err != nil {
+		return err
+	}
+	authorId, err := peer.IDFromPublicKey(pk)
+	if err != nil {
+		return err
+	}
+	id := authorId.Pretty()
+	if id == t.ipfs().Identity.Pretty() {
+		return nil
+	}
+	if t.contacts().Get(id) != nil {
+		return t.contacts().UpdateLastSeen(id, date)
+	}
+	return t.contacts().Add(&repo.Contact{
+		Id:       id,
+		PubKey:   authorPk,
+		Added:    time.Now(),
+		LastSeen: date,
+	})
+}
+
+// Heads returns the current thread heads, sorted so every peer
+// lists the same set of heads in the same order
+func (t *Thread) Heads() ([]string, error) {
 	head, err := t.GetHead()
 	if err != nil {
 		return nil, err
 	}
+	return splitHeads(head), nil
+}
 
-	// fast-forward is possible if current HEAD is equal to one of the incoming parents
-	var fastForwardable bool
-	for _, parent := range parents {
-		if head == parent {
-			fastForwardable = true
+// advanceHead replaces the parents of a new local block with the block itself
+func (t *Thread) advanceHead(id string, parents []string) error {
+	t.headlk.Lock()
+	defer t.headlk.Unlock()
+	heads, err := t.Heads()
+	if err != nil {
+		return err
+	}
+	return t.updateHead(joinHeads(append(removeHeads(heads, parents), id)))
+}
+
+// handleHead adds an incoming block to the set of heads, dropping any heads it descends from.
+// Diverging heads are not merged here, the next local block lists all of them as
+// parents, which keeps concurrent writers from trading merge blocks.
+func (t *Thread) handleHead(inboundId string, parents []string) error {
+	t.headlk.Lock()
+	defer t.headlk.Unlock()
+	heads, err := t.Heads()
+	if err != nil {
+		return err
+	}
+
+	// fast-forward past any heads the incoming chain already includes
+	superseded := t.ancestorHeads(parents, heads)
+	next := removeHeads(heads, superseded)
+
+	// peers merging the same heads at once produce equivalent merges,
+	// keep the lowest id so everyone settles on the same one
+	if inbound := t.blocks().Get(inboundId); inbound != nil && inbound.Type == repo.MergeBlock {
+		for _, head := range next {
+			if head == inboundId {
+				continue
+			}
+			index := t.blocks().Get(head)
+			if index == nil || index.Type != repo.MergeBlock || !sameHeads(index.Parents, inbound.Parents) {
+				continue
+			}
+			if head < inboundId {
+				log.Debugf("merge %s is equivalent to head %s, skipping", inboundId, head)
+				return nil
+			}
+			next = removeHeads(next, []string{head})
 		}
 	}
-	if fastForwardable {
-		// no need for a merge
+	next = append(next, inboundId)
+
+	if len(superseded) == len(heads) {
 		log.Debugf("fast-forwarded to %s", inboundId)
-		if err := t.updateHead(inboundId); err != nil {
-			return nil, err
-		}
-		return nil, nil
+	} else {
+		log.Debugf("added head %s, thread %s has %d heads", inboundId, t.Id, len(next))
 	}
+	return t.updateHead(joinHeads(next))
+}
 
-	// needs merge
-	return t.Merge(inboundId, post)
+// ancestorHeads walks back from parents and returns the heads found along the way.
+// The walk is bounded, a head deeper than that is left for the next local block to merge.
+func (t *Thread) ancestorHeads(parents []string, heads []string) []string {
+	if len(heads) == 0 {
+		return nil
+	}
+	isHead := make(map[string]bool)
+	for _, head := range heads {
+		isHead[head] = true
+	}
+	var found []string
+	seen := make(map[string]bool)
+	queue := append([]string{}, parents...)
+	for len(queue) > 0 && len(seen) < headSearchLimit && len(found) < len(heads) {
+		id := queue[0]
+		queue = queue[1:]
+		if id == "" || seen[id] {
+			continue
+		}
+		seen[id] = true
+		if isHead[id] {
+			found = append(found, id)
+			continue
+		}
+		if index := t.blocks().Get(id); index != nil {
+			queue = append(queue, index.Parents...)
+		}
+	}
+	return found
 }
 
 // post publishes a message with content id to peers
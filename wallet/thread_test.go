@@ -1,16 +1,31 @@
 package wallet_test
 
 import (
+	"bytes"
+	"crypto/rand"
+	"fmt"
+	rmodel "github.com/textileio/textile-go/repo"
+	"github.com/textileio/textile-go/repo/db"
+	"github.com/textileio/textile-go/util"
 	. "github.com/textileio/textile-go/wallet"
 	"github.com/textileio/textile-go/wallet/thread"
 	mh "gx/ipfs/QmZyZDi491cCNTLfAhwcaDii2Kg4pwKRkhqQzURGDvY6ua/go-multihash"
+	libp2pc "gx/ipfs/QmaPbCnUMBohSGo3KnxEa2bHqyJVVeEEcwtqJAYxerieBo/go-libp2p-crypto"
 	"os"
+	"sync"
 	"testing"
+	"time"
 )
 
 var trepo = "testdata/.textile1"
+var hrepo = "testdata/.textile1h"
 
 var twallet *Wallet
+var hwallet *Wallet
+
+// hthrd and hpeer are copies of the same thread on two wallets, used to test heads
+var hthrd *thread.Thread
+var hpeer *thread.Thread
 
 var thrd *thread.Thread
 var wadded *AddDataResult
@@ -71,6 +86,403 @@ func TestThread_AddPhoto(t *testing.T) {
 	}
 }
 
+func TestThread_Heads(t *testing.T) {
+	heads, err := thrd.Heads()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if len(heads) != 1 || heads[0] != tadded.B58String() {
+		t.Errorf("bad heads after local block: %v", heads)
+	}
+	merged, err := thrd.Merge()
+	if err != nil {
+		t.Errorf("merge failed: %s", err)
+	}
+	if merged != nil {
+		t.Error("merged a thread with a single head")
+	}
+}
+
+func TestThread_BlocksSince(t *testing.T) {
+	heads, err := thrd.Heads()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	blocks := thrd.BlocksSince(heads, nil, 10)
+	if len(blocks) == 0 || blocks[0].Id != tadded.B58String() {
+		t.Error("blocks since heads should start at the head")
+		return
+	}
+	if len(thrd.BlocksSince(heads, heads, 10)) != 0 {
+		t.Error("peer with our heads should not be missing blocks")
+	}
+	count, _, err := thrd.ApplyBlocks(blocks)
+	if err != nil {
+		t.Errorf("apply blocks failed: %s", err)
+	}
+	if count != 0 {
+		t.Error("applied blocks we already have")
+	}
+}
+
+func TestThread_Metadata(t *testing.T) {
+	if _, err := thrd.Rename("renamed"); err != nil {
+		t.Errorf("rename failed: %s", err)
+	}
+	if _, err := thrd.Describe("about this thread"); err != nil {
+		t.Errorf("describe failed: %s", err)
+	}
+	if _, err := thrd.SetCover(tadded.B58String()); err != nil {
+		t.Errorf("set cover failed: %s", err)
+	}
+	if thrd.Name != "renamed" || thrd.Description != "about this thread" || thrd.CoverId != tadded.B58String() {
+		t.Error("thread metadata not updated")
+	}
+	if _, err := thrd.SetCover("nope"); err == nil {
+		t.Error("set cover with a non-photo block should fail")
+	}
+}
+
+func TestThread_IgnoreRestore(t *testing.T) {
+	if _, err := thrd.Ignore(tadded.B58String()); err != nil {
+		t.Errorf("ignore failed: %s", err)
+		return
+	}
+	if len(thrd.Blocks("", -1, rmodel.PhotoBlock)) != 0 {
+		t.Error("ignored photo should not be listed")
+	}
+	if _, err := thrd.Restore(tadded.B58String()); err != nil {
+		t.Errorf("restore failed: %s", err)
+		return
+	}
+	if len(thrd.Blocks("", -1, rmodel.PhotoBlock)) != 1 {
+		t.Error("restored photo should be listed")
+	}
+	if _, err := thrd.Restore(tadded.B58String()); err == nil {
+		t.Error("restore of a listed photo should fail")
+	}
+}
+
+func TestThread_EditCaption(t *testing.T) {
+	if _, err := thrd.EditCaption(tadded.B58String(), "edited"); err != nil {
+		t.Errorf("edit caption failed: %s", err)
+		return
+	}
+	photos := thrd.Blocks("", -1, rmodel.PhotoBlock)
+	if len(photos) != 1 {
+		t.Error("photo missing after caption edit")
+		return
+	}
+	caption, err := thrd.Decrypt(photos[0].DataCaptionCipher)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if string(caption) != "edited" {
+		t.Errorf("bad caption after edit: %s", string(caption))
+	}
+}
+
+func TestThread_Albums(t *testing.T) {
+	album, err := thrd.AddAlbum("trip")
+	if err != nil {
+		t.Errorf("add album failed: %s", err)
+		return
+	}
+	albumId := album.B58String()
+	if len(thrd.Albums("", -1)) != 1 {
+		t.Error("album not listed")
+	}
+	if _, err := thrd.AddToAlbum(albumId, wadded.Id); err != nil {
+		t.Errorf("add to album failed: %s", err)
+		return
+	}
+	photos, err := thrd.AlbumPhotos(albumId, "", -1)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if len(photos) != 1 || photos[0].DataId != wadded.Id {
+		t.Error("album photo not listed")
+	}
+	if _, err := thrd.RemoveFromAlbum(albumId, wadded.Id); err != nil {
+		t.Errorf("remove from album failed: %s", err)
+		return
+	}
+	photos, err = thrd.AlbumPhotos(albumId, "", -1)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if len(photos) != 0 {
+		t.Error("removed photo still in album")
+	}
+	if _, err := thrd.AddToAlbum(albumId, "nope"); err == nil {
+		t.Error("add to album with a photo not in the thread should fail")
+	}
+	if _, err := thrd.RemoveFromAlbum(albumId, "nope"); err == nil {
+		t.Error("remove from album with a photo not in the thread should fail")
+	}
+}
+
+func TestThread_HeadsSetup(t *testing.T) {
+	os.RemoveAll(hrepo)
+	var err error
+	hwallet, _, err = NewWallet(Config{RepoPath: hrepo})
+	if err != nil {
+		t.Errorf("create wallet failed: %s", err)
+		return
+	}
+	if err := hwallet.Start(); err != nil {
+		t.Errorf("start wallet failed: %s", err)
+		return
+	}
+	sk, _, err := libp2pc.GenerateEd25519Key(rand.Reader)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	hthrd, err = twallet.AddThread("heads", sk)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	hpeer, err = hwallet.AddThread("heads", sk)
+	if err != nil {
+		t.Error(err)
+	}
+}
+
+func TestThread_HandleHeadDivergent(t *testing.T) {
+	a, err := hthrd.Rename("a")
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	b, err := hpeer.Rename("b")
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if err := syncHeads(hpeer, hthrd); err != nil {
+		t.Errorf("sync failed: %s", err)
+		return
+	}
+	if err := syncHeads(hthrd, hpeer); err != nil {
+		t.Errorf("sync failed: %s", err)
+		return
+	}
+	for _, thrd := range []*thread.Thread{hthrd, hpeer} {
+		heads, err := thrd.Heads()
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if len(heads) != 2 || !hasHead(heads, a.B58String()) || !hasHead(heads, b.B58String()) {
+			t.Errorf("concurrent blocks should both be heads: %v", heads)
+		}
+	}
+}
+
+func TestThread_HandleHeadEquivalentMerges(t *testing.T) {
+	ma, err := hthrd.Merge()
+	if err != nil || ma == nil {
+		t.Errorf("merge failed: %s", err)
+		return
+	}
+	mb, err := hpeer.Merge()
+	if err != nil || mb == nil {
+		t.Errorf("merge failed: %s", err)
+		return
+	}
+	if ma.B58String() == mb.B58String() {
+		t.Error("merges should be distinct blocks")
+		return
+	}
+	if err := syncHeads(hpeer, hthrd); err != nil {
+		t.Errorf("sync failed: %s", err)
+		return
+	}
+	if err := syncHeads(hthrd, hpeer); err != nil {
+		t.Errorf("sync failed: %s", err)
+		return
+	}
+
+	// both sides keep the merge with the lowest id
+	lowest := ma.B58String()
+	if mb.B58String() < lowest {
+		lowest = mb.B58String()
+	}
+	for _, thrd := range []*thread.Thread{hthrd, hpeer} {
+		heads, err := thrd.Heads()
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if len(heads) != 1 || heads[0] != lowest {
+			t.Errorf("equivalent merges should settle on %s: %v", lowest, heads)
+		}
+	}
+}
+
+func TestThread_HandleHeadSearchLimit(t *testing.T) {
+	heads, err := hthrd.Heads()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if len(heads) != 1 {
+		t.Errorf("expected a single head: %v", heads)
+		return
+	}
+	base := heads[0]
+
+	// build a chain one block past the head search limit on the peer
+	var tip mh.Multihash
+	for i := 0; i < 501; i++ {
+		tip, err = hpeer.Rename(fmt.Sprintf("chain %d", i))
+		if err != nil {
+			t.Error(err)
+			return
+		}
+	}
+	if err := syncHeads(hpeer, hthrd); err != nil {
+		t.Errorf("sync failed: %s", err)
+		return
+	}
+
+	// the old head is too deep to be found, so it's kept until a local block merges it
+	heads, err = hthrd.Heads()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if len(heads) != 2 || !hasHead(heads, base) || !hasHead(heads, tip.B58String()) {
+		t.Errorf("head past the search limit should be kept: %v", heads)
+		return
+	}
+	if _, err := hthrd.Merge(); err != nil {
+		t.Errorf("merge failed: %s", err)
+		return
+	}
+	heads, err = hthrd.Heads()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if len(heads) != 1 {
+		t.Errorf("local merge should leave a single head: %v", heads)
+	}
+}
+
+func TestThread_FollowMissingParent(t *testing.T) {
+	sk, _, err := libp2pc.GenerateEd25519Key(rand.Reader)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	fthrd, err := twallet.AddThread("follow", sk)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	fpeer, err := hwallet.AddThread("follow", sk)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	// build a chain on the peer, then stop it so the root can't be fetched
+	var chain []string
+	for _, name := range []string{"one", "two", "three"} {
+		id, err := fpeer.Rename(name)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		chain = append(chain, id.B58String())
+	}
+	envs := make(map[string][]byte)
+	for _, block := range fpeer.BlocksSince([]string{chain[2]}, nil, 10) {
+		envs[block.Id] = block.Envelope
+	}
+	hwallet.Stop()
+	for _, id := range chain[1:] {
+		if _, err := util.PinData(twallet.Ipfs(), bytes.NewReader(envs[id])); err != nil {
+			t.Error(err)
+			return
+		}
+	}
+
+	var lock sync.Mutex
+	var indexed []string
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		for {
+			select {
+			case update := <-fthrd.Updates():
+				lock.Lock()
+				indexed = append(indexed, update.Block.Id)
+				lock.Unlock()
+			case <-done:
+				return
+			}
+		}
+	}()
+	if err := fthrd.FollowParents([]string{chain[2]}); err != nil {
+		t.Errorf("follow parents failed: %s", err)
+		return
+	}
+
+	// reachable children are indexed parents first
+	lock.Lock()
+	order := append([]string{}, indexed...)
+	lock.Unlock()
+	if len(order) != 2 || order[0] != chain[1] || order[1] != chain[2] {
+		t.Errorf("reachable blocks should be indexed parents first: %v", order)
+	}
+
+	// the unreachable root is queued
+	store, err := db.Create(twallet.GetRepoPath(), "")
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	defer store.Close()
+	queued := store.MissingBlocks().List(fthrd.Id, time.Now().Add(time.Minute), 10)
+	if len(queued) != 1 || queued[0].Id != chain[0] {
+		t.Errorf("unreachable block should be queued: %v", queued)
+		return
+	}
+
+	// once it's available (and due), a retry indexes it and clears the queue
+	if _, err := util.PinData(twallet.Ipfs(), bytes.NewReader(envs[chain[0]])); err != nil {
+		t.Error(err)
+		return
+	}
+	if err := store.MissingBlocks().Put(&rmodel.MissingBlock{Id: chain[0], Tried: time.Now().Add(-time.Hour)}); err != nil {
+		t.Error(err)
+		return
+	}
+	count, err := fthrd.RetryMissing()
+	if err != nil {
+		t.Errorf("retry missing failed: %s", err)
+		return
+	}
+	if count != 1 {
+		t.Errorf("retry should index the missing block, got %d", count)
+	}
+	if _, err := twallet.GetBlock(chain[0]); err != nil {
+		t.Errorf("retried block not indexed: %s", err)
+	}
+	if len(store.MissingBlocks().List(fthrd.Id, time.Now().Add(time.Minute), 10)) != 0 {
+		t.Error("retried block should be removed from the queue")
+	}
+}
+
 func TestThread_GetBlockData(t *testing.T) {
 	// TODO
 }
@@ -124,5 +536,44 @@ func TestThread_Peers(t *testing.T) {
 }
 
 func Test_TeardownThread(t *testing.T) {
+	if hwallet != nil {
+		hwallet.Stop()
+		os.RemoveAll(hwallet.GetRepoPath())
+	}
 	os.RemoveAll(twallet.GetRepoPath())
 }
+
+// syncHeads copies blocks missing from to and adopts from's heads, like a thread sync
+func syncHeads(from *thread.Thread, to *thread.Thread) error {
+	heads, err := from.Heads()
+	if err != nil {
+		return err
+	}
+	have, err := to.Heads()
+	if err != nil {
+		return err
+	}
+	want := heads
+	for len(want) > 0 {
+		blocks := from.BlocksSince(want, have, thread.MaxSyncBatch)
+		if len(blocks) == 0 {
+			break
+		}
+		_, missing, err := to.ApplyBlocks(blocks)
+		if err != nil {
+			return err
+		}
+		want = missing
+	}
+	return to.AdoptHeads(heads)
+}
+
+// hasHead returns whether or not id is in heads
+func hasHead(heads []string, id string) bool {
+	for _, head := range heads {
+		if head == id {
+			return true
+		}
+	}
+	return false
+}
@@ -10,6 +10,7 @@ import (
 	trepo "github.com/textileio/textile-go/repo"
 	"github.com/textileio/textile-go/util"
 	"github.com/textileio/textile-go/wallet/thread"
+	"gx/ipfs/QmZoWKhxUmZ2seW4BzX6fJkNR8hh9PsGModr7q171yq2SS/go-libp2p-peer"
 	mh "gx/ipfs/QmZyZDi491cCNTLfAhwcaDii2Kg4pwKRkhqQzURGDvY6ua/go-multihash"
 	libp2pc "gx/ipfs/QmaPbCnUMBohSGo3KnxEa2bHqyJVVeEEcwtqJAYxerieBo/go-libp2p-crypto"
 )
@@ -63,14 +64,49 @@ func (w *Wallet) AddThread(name string, secret libp2pc.PrivKey) (*thread.Thread,
 	return thrd, nil
 }
 
-// AddThreadWithMnemonic adds a thread with a given name and mnemonic phrase
+// AddDerivedThread adds a thread keyed with the next unused thread key derived from the account key.
+// The index is published with the account backup, which Recover uses to skip it on other installs.
+func (w *Wallet) AddDerivedThread(name string) (*thread.Thread, error) {
+	for {
+		secret, index, err := w.nextDerivedKey(util.ThreadKey)
+		if err != nil {
+			return nil, err
+		}
+		pkb, err := secret.GetPublic().Bytes()
+		if err != nil {
+			return nil, err
+		}
+
+		// skip keys already in use, e.g., threads restored before the index was
+		if w.datastore.Threads().Get(libp2pc.ConfigEncodeKey(pkb)) != nil {
+			continue
+		}
+		log.Debugf("derived thread key %d for: %s", index, name)
+		thrd, err := w.AddThread(name, secret)
+		if err != nil {
+			return nil, err
+		}
+
+		// republish the backup so the index is reserved account-wide
+		go func() {
+			<-w.Online()
+			if _, err := w.PublishProfile(nil); err != nil {
+				log.Errorf("error publishing profile (derived thread): %s", err)
+			}
+		}()
+		return thrd, nil
+	}
+}
+
+// AddThreadWithMnemonic adds a thread with a given name and mnemonic phrase.
+// Without a mnemonic, the thread key is derived from the account key and no phrase is returned.
 func (w *Wallet) AddThreadWithMnemonic(name string, mnemonic *string) (*thread.Thread, string, error) {
-	if mnemonic != nil {
-		log.Debugf("regenerating keypair from mnemonic for: %s", name)
-	} else {
-		log.Debugf("generating keypair for: %s", name)
+	if mnemonic == nil {
+		thrd, err := w.AddDerivedThread(name)
+		return thrd, "", err
 	}
-	secret, mnem, err := util.PrivKeyFromMnemonic(mnemonic)
+	log.Debugf("regenerating keypair from mnemonic for: %s", name)
+	secret, mnem, err := util.PrivKeyFromMnemonic(mnemonic, "")
 	if err != nil {
 		return nil, "", err
 	}
@@ -102,6 +138,12 @@ func (w *Wallet) RemoveThread(id string) (mh.Multihash, error) {
 	if err := w.datastore.Threads().Delete(id); err != nil {
 		return nil, err
 	}
+	if err := w.datastore.PhotoImports().DeleteByThreadId(id); err != nil {
+		return nil, err
+	}
+	if err := w.datastore.AlbumPhotos().DeleteByThreadId(id); err != nil {
+		return nil, err
+	}
 
 	// clean up
 	thrd.Close()
@@ -117,6 +159,42 @@ func (w *Wallet) RemoveThread(id string) (mh.Multihash, error) {
 	return addr, nil
 }
 
+// RenameThread adds a metadata block with a new thread name
+func (w *Wallet) RenameThread(id string, name string) (mh.Multihash, error) {
+	if !w.IsOnline() {
+		return nil, ErrOffline
+	}
+	_, thrd := w.GetThread(id)
+	if thrd == nil {
+		return nil, errors.New(fmt.Sprintf("could not find thread: %s", id))
+	}
+	return thrd.Rename(name)
+}
+
+// DescribeThread adds a metadata block with a new thread description
+func (w *Wallet) DescribeThread(id string, description string) (mh.Multihash, error) {
+	if !w.IsOnline() {
+		return nil, ErrOffline
+	}
+	_, thrd := w.GetThread(id)
+	if thrd == nil {
+		return nil, errors.New(fmt.Sprintf("could not find thread: %s", id))
+	}
+	return thrd.Describe(description)
+}
+
+// SetThreadCover adds a metadata block that uses a thread photo as the cover
+func (w *Wallet) SetThreadCover(id string, blockId string) (mh.Multihash, error) {
+	if !w.IsOnline() {
+		return nil, ErrOffline
+	}
+	_, thrd := w.GetThread(id)
+	if thrd == nil {
+		return nil, errors.New(fmt.Sprintf("could not find thread: %s", id))
+	}
+	return thrd.SetCover(blockId)
+}
+
 // AcceptThreadInvite attemps to download an encrypted thread key from an internal invite,
 // add the thread, and notify the inviter of the join
 func (w *Wallet) AcceptThreadInvite(blockId string) (mh.Multihash, error) {
@@ -228,7 +306,7 @@ func (w *Wallet) AcceptExternalThreadInvite(blockId string, key []byte) (mh.Mult
 	}
 
 	// decrypt thread key
-	skb, err := crypto.DecryptAES(invite.SkCipher, key)
+	skb, err := crypto.DecryptEnvelope(invite.SkCipher, key, "")
 	if err != nil {
 		return nil, err
 	}
@@ -242,6 +320,15 @@ func (w *Wallet) AcceptExternalThreadInvite(blockId string, key []byte) (mh.Mult
 		return nil, err
 	}
 
+	// refuse expired invites, and check with the inviter for revoked or used up ones
+	if thread.ExternalInviteExpired(invite) {
+		return nil, thread.ErrInviteExpired
+	}
+	threadId := libp2pc.ConfigEncodeKey(invite.Header.ThreadPk)
+	if err := w.checkExternalInvite(authorPk, threadId, blockId); err != nil {
+		return nil, err
+	}
+
 	// add it
 	thrd, err := w.AddThread(invite.SuggestedName, sk)
 	if err != nil {
@@ -255,3 +342,114 @@ func (w *Wallet) AcceptExternalThreadInvite(blockId string, key []byte) (mh.Mult
 
 	return thrd.Join(authorPk, blockId)
 }
+
+// rekeyThread moves a thread's photos, metadata and albums to a new derived key shared
+// with every member except revoked, then leaves the old thread. The revoked peer keeps
+// access to the old thread's existing content but not to anything added after.
+// The old thread is only removed once the new one is complete.
+func (w *Wallet) rekeyThread(thrd *thread.Thread, revoked peer.ID) (*thread.Thread, error) {
+	// collect members before leaving, devices are invited when the new thread is added
+	members := thrd.Peers()
+	name, description, coverId := thrd.Metadata()
+
+	fresh, err := w.AddDerivedThread(name)
+	if err != nil {
+		return nil, err
+	}
+	if err := w.copyThread(thrd, fresh, members, revoked, description, coverId); err != nil {
+		if _, rerr := w.RemoveThread(fresh.Id); rerr != nil {
+			log.Errorf("error rolling back re-key of thread %s: %s", thrd.Id, rerr)
+		}
+		return nil, err
+	}
+
+	if _, err := w.RemoveThread(thrd.Id); err != nil {
+		if _, rerr := w.RemoveThread(fresh.Id); rerr != nil {
+			log.Errorf("error rolling back re-key of thread %s: %s", thrd.Id, rerr)
+		}
+		return nil, err
+	}
+	log.Infof("re-keyed thread %s as %s", thrd.Id, fresh.Id)
+
+	return fresh, nil
+}
+
+// copyThread invites members and re-adds photos, metadata and albums from one thread to another
+func (w *Wallet) copyThread(from *thread.Thread, to *thread.Thread, members []trepo.Peer, revoked peer.ID, description string, coverId string) error {
+	for _, member := range members {
+		if member.Id == revoked.Pretty() || member.Id == w.ipfs.Identity.Pretty() {
+			continue
+		}
+		if w.datastore.Devices().Get(libp2pc.ConfigEncodeKey(member.PubKey)) != nil {
+			continue
+		}
+		pk, err := libp2pc.UnmarshalPublicKey(member.PubKey)
+		if err != nil {
+			return err
+		}
+		if _, err := to.AddInvite(pk); err != nil {
+			return err
+		}
+	}
+
+	// re-add photos oldest first, the data itself is keyed per photo and does not change.
+	// blocks are resolved, so ignored photos are left behind and captions are the latest edit.
+	photos := from.Blocks("", -1, trepo.PhotoBlock)
+	blockIds := make(map[string]string)
+	for i := len(photos) - 1; i >= 0; i-- {
+		block := photos[i]
+		key, err := from.GetBlockDataKey(&block)
+		if err != nil {
+			return err
+		}
+		var caption string
+		if block.DataCaptionCipher != nil {
+			captionb, err := from.Decrypt(block.DataCaptionCipher)
+			if err != nil {
+				return err
+			}
+			caption = string(captionb)
+		}
+		addr, err := to.AddPhoto(block.DataId, caption, key)
+		if err != nil {
+			return err
+		}
+		blockIds[block.Id] = addr.B58String()
+	}
+
+	// metadata, the name was used to add the thread
+	if description != "" {
+		if _, err := to.Describe(description); err != nil {
+			return err
+		}
+	}
+	if cover, ok := blockIds[coverId]; ok {
+		if _, err := to.SetCover(cover); err != nil {
+			return err
+		}
+	}
+
+	// albums oldest first, along with their current photos
+	albums := from.Albums("", -1)
+	for i := len(albums) - 1; i >= 0; i-- {
+		album := albums[i]
+		nameb, err := from.Decrypt(album.DataCaptionCipher)
+		if err != nil {
+			return err
+		}
+		addr, err := to.AddAlbum(string(nameb))
+		if err != nil {
+			return err
+		}
+		inAlbum, err := from.AlbumPhotos(album.Id, "", -1)
+		if err != nil {
+			return err
+		}
+		for j := len(inAlbum) - 1; j >= 0; j-- {
+			if _, err := to.AddToAlbum(addr.B58String(), inAlbum[j].DataId); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,198 @@
+package wallet
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/textileio/textile-go/pb"
+	"github.com/textileio/textile-go/wallet/thread"
+	"gx/ipfs/QmZoWKhxUmZ2seW4BzX6fJkNR8hh9PsGModr7q171yq2SS/go-libp2p-peer"
+	libp2pc "gx/ipfs/QmaPbCnUMBohSGo3KnxEa2bHqyJVVeEEcwtqJAYxerieBo/go-libp2p-crypto"
+	"time"
+)
+
+// threadSyncTimeout bounds each heads or blocks request to a peer
+const threadSyncTimeout = time.Second * 15
+
+// threadSyncBatch is how many blocks are requested from a peer at once
+const threadSyncBatch = 50
+
+// ThreadSyncProgress is reported as blocks are pulled from a thread peer
+type ThreadSyncProgress struct {
+	ThreadId string `json:"thread_id"`
+	PeerId   string `json:"peer_id"`
+	Fetched  int    `json:"fetched"`
+	Done     bool   `json:"done"`
+	Error    string `json:"error,omitempty"`
+}
+
+// SyncThread asks each thread peer for its heads and pulls any blocks we're missing,
+// returning the number of new blocks
+func (w *Wallet) SyncThread(threadId string, progress func(*ThreadSyncProgress)) (int, error) {
+	if !w.IsOnline() {
+		return 0, ErrOffline
+	}
+	_, thrd := w.GetThread(threadId)
+	if thrd == nil {
+		return 0, errors.New(fmt.Sprintf("could not find thread: %s", threadId))
+	}
+	return w.pullThread(context.Background(), thrd, progress)
+}
+
+// pullThreads catches up every thread, used when coming online
+func (w *Wallet) pullThreads() {
+	for _, thrd := range w.Threads() {
+		if _, err := thrd.RetryMissing(); err != nil {
+			log.Debugf("error retrying missing blocks in thread %s: %s", thrd.Id, err)
+		}
+		count, err := w.pullThread(context.Background(), thrd, nil)
+		if err != nil {
+			log.Debugf("error pulling thread %s: %s", thrd.Id, err)
+			continue
+		}
+		if count > 0 {
+			log.Infof("pulled %d blocks in thread %s", count, thrd.Name)
+		}
+	}
+}
+
+// pullThread pulls missing blocks from each thread peer in turn.
+// Unreachable peers are skipped, an error is only returned if none answered.
+// Requests are made with ctx, so cancelling it ends the pull.
+func (w *Wallet) pullThread(ctx context.Context, thrd *thread.Thread, progress func(*ThreadSyncProgress)) (int, error) {
+	report := func(prog *ThreadSyncProgress) {
+		prog.ThreadId = thrd.Id
+		if progress != nil {
+			progress(prog)
+		}
+	}
+
+	var total int
+	var answered bool
+	var lastErr error
+	seen := make(map[string]bool)
+	for _, p := range thrd.Peers() {
+		if ctx.Err() != nil {
+			lastErr = ctx.Err()
+			break
+		}
+		if seen[p.Id] || p.Id == w.ipfs.Identity.Pretty() {
+			continue
+		}
+		seen[p.Id] = true
+		pid, err := peer.IDB58Decode(p.Id)
+		if err != nil {
+			continue
+		}
+		count, err := w.pullThreadFrom(ctx, thrd, pid, func(fetched int) {
+			report(&ThreadSyncProgress{PeerId: p.Id, Fetched: total + fetched})
+		})
+		total += count
+		if err != nil {
+			log.Debugf("error pulling thread %s from %s: %s", thrd.Id, p.Id, err)
+			lastErr = err
+			continue
+		}
+		answered = true
+	}
+
+	prog := &ThreadSyncProgress{Fetched: total, Done: true}
+	if !answered && lastErr != nil {
+		prog.Error = lastErr.Error()
+		report(prog)
+		return total, lastErr
+	}
+	report(prog)
+	return total, nil
+}
+
+// pullThreadFrom asks a peer for its heads, then requests blocks in batches
+// until we reach blocks we already have
+func (w *Wallet) pullThreadFrom(ctx context.Context, thrd *thread.Thread, pid peer.ID, progress func(int)) (int, error) {
+	heads := new(pb.ThreadHeads)
+	req := &pb.ThreadHeadsRequest{ThreadId: thrd.Id}
+	if err := w.threadRequest(ctx, pid, pb.Message_THREAD_HEADS, req, heads); err != nil {
+		return 0, err
+	}
+	var want []string
+	for _, head := range heads.Heads {
+		if w.datastore.Blocks().Get(head) == nil {
+			want = append(want, head)
+		}
+	}
+	have, err := thrd.Heads()
+	if err != nil {
+		return 0, err
+	}
+
+	var total int
+	for len(want) > 0 {
+		blocks := new(pb.ThreadBlocks)
+		req := &pb.ThreadBlocksRequest{
+			ThreadId: thrd.Id,
+			Want:     want,
+			Have:     have,
+			Limit:    threadSyncBatch,
+		}
+		if err := w.threadRequest(ctx, pid, pb.Message_THREAD_BLOCKS, req, blocks); err != nil {
+			return total, err
+		}
+		count, missing, err := thrd.ApplyBlocks(blocks.Blocks)
+		total += count
+		if err != nil {
+			return total, err
+		}
+		if count == 0 {
+			// peer has nothing new for us
+			break
+		}
+		progress(total)
+		want = missing
+	}
+
+	// take on the peer's heads now that we have their blocks
+	if err := thrd.AdoptHeads(heads.Heads); err != nil {
+		return total, err
+	}
+	return total, nil
+}
+
+// threadRequest sends a sync request to a peer and unmarshals its response into res
+func (w *Wallet) threadRequest(ctx context.Context, pid peer.ID, mtype pb.Message_Type, req proto.Message, res proto.Message) error {
+	payload, err := ptypes.MarshalAny(req)
+	if err != nil {
+		return err
+	}
+	env, err := w.NewEnvelope(&pb.Message{Type: mtype, Payload: payload})
+	if err != nil {
+		return err
+	}
+	rctx, cancel := context.WithTimeout(ctx, threadSyncTimeout)
+	defer cancel()
+	renv, err := w.service.SendRequest(rctx, pid, env)
+	if err != nil {
+		return err
+	}
+
+	// verify the response came from the peer we asked
+	if err := w.VerifyEnvelope(renv); err != nil {
+		return err
+	}
+	pk, err := libp2pc.UnmarshalPublicKey(renv.Pk)
+	if err != nil {
+		return err
+	}
+	if !pid.MatchesPublicKey(pk) {
+		return errors.New("sync response from wrong peer")
+	}
+	if renv.Message.Type == pb.Message_ERROR {
+		perr := new(pb.Error)
+		if err := ptypes.UnmarshalAny(renv.Message.Payload, perr); err != nil {
+			return err
+		}
+		return errors.New(fmt.Sprintf("sync rejected: %s", perr.Message))
+	}
+	return ptypes.UnmarshalAny(renv.Message.Payload, res)
+}
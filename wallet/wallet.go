@@ -13,6 +13,7 @@ import (
 	"github.com/textileio/textile-go/repo/db"
 	"github.com/textileio/textile-go/storage"
 	"github.com/textileio/textile-go/util"
+	"github.com/textileio/textile-go/wallet/model"
 	"github.com/textileio/textile-go/wallet/thread"
 	"gx/ipfs/QmVW4cqbibru3hXA1iRmg85Fk7z9qML9k176CYQaMXVCrP/go-libp2p-kad-dht"
 	libp2pc "gx/ipfs/QmaPbCnUMBohSGo3KnxEa2bHqyJVVeEEcwtqJAYxerieBo/go-libp2p-crypto"
@@ -24,6 +25,7 @@ import (
 	"gx/ipfs/QmcZfnkapfECQGcLZaf9B79NRg7cRa9EnZh4LSbkCzwNvY/go-cid"
 	"os"
 	"path/filepath"
+	"sync"
 	"time"
 )
 
@@ -34,12 +36,20 @@ type Config struct {
 	RepoPath string
 	Mnemonic *string
 
+	// Passphrase is an optional bip39 passphrase for the account seed
+	Passphrase string
+
+	// Password optionally encrypts the repo
+	Password string
+
 	SwarmPorts string
 
 	IsMobile bool
 	IsServer bool
 
 	CafeAddr string
+
+	Renditions []model.Rendition
 }
 
 type Update struct {
@@ -55,24 +65,28 @@ const (
 	ThreadRemoved
 	DeviceAdded
 	DeviceRemoved
+	ProfileUpdated
 )
 
 // AddDataResult wraps added data content id and key
 type AddDataResult struct {
-	Id      string          `json:"id"`
-	Key     string          `json:"key"`
-	Archive *client.Archive `json:"archive,omitempty"`
+	Id        string          `json:"id"`
+	Key       string          `json:"key"`
+	Archive   *client.Archive `json:"archive,omitempty"`
+	Duplicate bool            `json:"duplicate,omitempty"`
 }
 
 type Wallet struct {
 	version            string
 	context            oldcmds.Context
 	repoPath           string
+	password           string
 	cancel             context.CancelFunc
 	ipfs               *core.IpfsNode
 	datastore          trepo.Datastore
 	service            *serv.TextileService
 	cafeAddr           string
+	renditions         []model.Rendition
 	isMobile           bool
 	started            bool
 	threads            []*thread.Thread
@@ -83,6 +97,10 @@ type Wallet struct {
 	messageRetriever   *net.MessageRetriever
 	pointerRepublisher *net.PointerRepublisher
 	pinner             *net.Pinner
+	pairing            *pendingPairing
+	pairinglk          sync.Mutex
+	syncing            bool
+	synclk             sync.Mutex
 }
 
 const pingTimeout = time.Second * 10
@@ -92,20 +110,39 @@ var ErrStopped = errors.New("node is stopped")
 var ErrOffline = errors.New("node is offline")
 var ErrThreadLoaded = errors.New("thread is loaded")
 var ErrNoCafeHost = errors.New("cafe host address is not set")
+var ErrNotSignedIn = errors.New("not signed in")
+var ErrBlockNotFound = errors.New("block not found locally")
 
 func NewWallet(config Config) (*Wallet, string, error) {
+	// renditions share the photo directory with reserved entries
+	if err := validateRenditions(config.Renditions); err != nil {
+		return nil, "", err
+	}
+
 	// get database handle
-	sqliteDB, err := db.Create(config.RepoPath, "")
+	sqliteDB, err := db.Create(config.RepoPath, config.Password)
 	if err != nil {
 		return nil, "", err
 	}
 
 	// we may be running in an uninitialized state.
-	mnemonic, err := trepo.DoInit(config.RepoPath, config.Version, config.Mnemonic, sqliteDB.Config().Init, sqliteDB.Config().Configure)
+	mnemonic, err := trepo.DoInit(config.RepoPath, config.Version, config.Mnemonic, config.Passphrase, config.Password, sqliteDB.Config().Init, sqliteDB.Config().Configure)
 	if err != nil && err != trepo.ErrRepoExists {
 		return nil, "", err
 	}
 
+	// a wrong (or missing) password leaves the datastore unreadable
+	if sqliteDB.Config().IsEncrypted() {
+		sqliteDB.Close()
+		return nil, "", trepo.ErrInvalidPassword
+	}
+
+	// bring datastores from older versions up to the current schema
+	if err := sqliteDB.Migrate(); err != nil {
+		sqliteDB.Close()
+		return nil, "", err
+	}
+
 	// acquire the repo lock _before_ constructing a node. we need to make
 	// sure we are permitted to access the resources (datastore, etc.)
 	repo, err := fsrepo.Open(config.RepoPath)
@@ -129,12 +166,20 @@ func NewWallet(config Config) (*Wallet, string, error) {
 		return nil, "", err
 	}
 
+	// use default photo renditions if none are configured
+	renditions := config.Renditions
+	if renditions == nil {
+		renditions = model.DefaultRenditions
+	}
+
 	return &Wallet{
-		version:   config.Version,
-		repoPath:  config.RepoPath,
-		datastore: sqliteDB,
-		isMobile:  config.IsMobile,
-		cafeAddr:  config.CafeAddr,
+		version:    config.Version,
+		repoPath:   config.RepoPath,
+		password:   config.Password,
+		datastore:  sqliteDB,
+		isMobile:   config.IsMobile,
+		cafeAddr:   config.CafeAddr,
+		renditions: renditions,
 	}, mnemonic, nil
 }
 
@@ -193,7 +238,7 @@ func (w *Wallet) Start() error {
 		})
 
 		// service is now configurable
-		w.service = serv.NewService(w.ipfs, w.datastore, w.GetThread, w.AddThread)
+		w.service = serv.NewService(w.ipfs, w.datastore, w.GetThread, w.AddThread, w.handlePairRequest, w.handleDeviceRevoke)
 
 		// build the message retriever
 		mrCfg := net.MRConfig{
@@ -213,6 +258,8 @@ func (w *Wallet) Start() error {
 		if !w.isMobile {
 			go w.messageRetriever.Run()
 			go w.pointerRepublisher.Run()
+			go w.runProfileRefresh()
+			go w.pullThreads()
 		}
 
 		// print swarm addresses
@@ -240,12 +287,17 @@ func (w *Wallet) Start() error {
 			go w.pinner.Run()
 		}
 
-		// re-pub profile
+		// re-pub profile, which carries the account backup
 		go func() {
 			<-w.Online()
 			if _, err := w.PublishProfile(nil); err != nil {
 				log.Errorf("error publishing profile: %s", err)
 			}
+
+			// keep the backup fresh if not mobile
+			if !w.isMobile {
+				w.runBackups()
+			}
 		}()
 	}
 
@@ -260,6 +312,18 @@ func (w *Wallet) Start() error {
 		}
 	}
 
+	// index photos from before search existed, metadata may need the network
+	threads := make([]*thread.Thread, len(w.threads))
+	copy(threads, w.threads)
+	go func() {
+		<-w.Online()
+		for _, thrd := range threads {
+			if err := thrd.BackfillSearch(); err != nil {
+				log.Errorf("error backfilling search for thread %s: %s", thrd.Id, err)
+			}
+		}
+	}()
+
 	return nil
 }
 
@@ -314,6 +378,46 @@ func (w *Wallet) Stop() error {
 	return nil
 }
 
+// ChangePassword re-encrypts the datastore and identity key with next.
+// An empty password stores them unencrypted. The wallet must be stopped.
+func (w *Wallet) ChangePassword(current string, next string) error {
+	if w.started {
+		return ErrStarted
+	}
+	if current != w.password {
+		return trepo.ErrInvalidPassword
+	}
+	w.datastore.Close()
+
+	// re-key the datastore first, it's the only step that can partially fail
+	if err := db.ChangePassword(w.repoPath, current, next); err != nil {
+		return w.reopenDatastore(err)
+	}
+	if err := trepo.ChangeIdentityPassword(w.repoPath, current, next); err != nil {
+		log.Errorf("error re-keying identity, rolling back datastore: %s", err)
+		if rerr := db.ChangePassword(w.repoPath, next, current); rerr != nil {
+			log.Errorf("error rolling back datastore password: %s", rerr)
+		}
+		return w.reopenDatastore(err)
+	}
+	w.password = next
+	return w.reopenDatastore(nil)
+}
+
+// reopenDatastore re-opens the datastore with the current password, passing through err
+func (w *Wallet) reopenDatastore(err error) error {
+	sqliteDB, derr := db.Create(w.repoPath, w.password)
+	if derr != nil {
+		log.Errorf("error re-opening datastore: %s", derr)
+		if err == nil {
+			err = derr
+		}
+		return err
+	}
+	w.datastore = sqliteDB
+	return err
+}
+
 func (w *Wallet) Started() bool {
 	return w.started
 }
@@ -434,7 +538,7 @@ func (w *Wallet) GetThread(id string) (*int, *thread.Thread) {
 func (w *Wallet) GetBlock(id string) (*trepo.Block, error) {
 	block := w.datastore.Blocks().Get(id)
 	if block == nil {
-		return nil, errors.New("block not found locally")
+		return nil, ErrBlockNotFound
 	}
 	return block, nil
 }
@@ -443,7 +547,7 @@ func (w *Wallet) GetBlock(id string) (*trepo.Block, error) {
 func (w *Wallet) GetBlockByDataId(dataId string) (*trepo.Block, error) {
 	block := w.datastore.Blocks().GetByDataId(dataId)
 	if block == nil {
-		return nil, errors.New("block not found locally")
+		return nil, ErrBlockNotFound
 	}
 	return block, nil
 }
@@ -459,7 +563,7 @@ func (w *Wallet) GetDataAtPath(path string) ([]byte, error) {
 // createIPFS creates an IPFS node
 func (w *Wallet) createIPFS(online bool) error {
 	// open repo
-	repo, err := fsrepo.Open(w.repoPath)
+	repo, err := trepo.OpenRepo(w.repoPath, w.password)
 	if err != nil {
 		log.Errorf("error opening repo: %s", err)
 		return err
@@ -541,8 +645,12 @@ func (w *Wallet) loadThread(mod *trepo.Thread) (*thread.Thread, error) {
 		Ipfs: func() *core.IpfsNode {
 			return w.ipfs
 		},
-		Blocks: w.datastore.Blocks,
-		Peers:  w.datastore.Peers,
+		Blocks:        w.datastore.Blocks,
+		Peers:         w.datastore.Peers,
+		Contacts:      w.datastore.Contacts,
+		Search:        w.datastore.Search,
+		MissingBlocks: w.datastore.MissingBlocks,
+		AlbumPhotos:   w.datastore.AlbumPhotos,
 		GetHead: func() (string, error) {
 			m := w.datastore.Threads().Get(id)
 			if m == nil {
@@ -556,6 +664,9 @@ func (w *Wallet) loadThread(mod *trepo.Thread) (*thread.Thread, error) {
 			}
 			return nil
 		},
+		UpdateMeta: func(name string, description string, coverId string, metaId string) error {
+			return w.datastore.Threads().UpdateMeta(id, name, description, coverId, metaId)
+		},
 		Send:          w.SendMessage,
 		NewEnvelope:   w.NewEnvelope,
 		PutPinRequest: w.putPinRequest,
@@ -592,7 +703,7 @@ func (w *Wallet) sendUpdate(update Update) {
 func (w *Wallet) touchDatastore() error {
 	if err := w.datastore.Ping(); err != nil {
 		log.Debug("re-opening datastore...")
-		sqliteDB, err := db.Create(w.repoPath, "")
+		sqliteDB, err := db.Create(w.repoPath, w.password)
 		if err != nil {
 			log.Errorf("error re-opening datastore: %s", err)
 			return err
@@ -2,13 +2,24 @@ package wallet_test
 
 import (
 	"crypto/rand"
+	"encoding/json"
+	"fmt"
 	"github.com/segmentio/ksuid"
 	cmodels "github.com/textileio/textile-go/cafe/models"
+	"github.com/textileio/textile-go/crypto"
+	rmodel "github.com/textileio/textile-go/repo"
+	tutil "github.com/textileio/textile-go/util"
 	util "github.com/textileio/textile-go/util/testing"
 	. "github.com/textileio/textile-go/wallet"
+	"github.com/textileio/textile-go/wallet/model"
+	"github.com/textileio/textile-go/wallet/thread"
 	libp2pc "gx/ipfs/QmaPbCnUMBohSGo3KnxEa2bHqyJVVeEEcwtqJAYxerieBo/go-libp2p-crypto"
+	"io/ioutil"
 	"os"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 )
 
 var repo = "testdata/.textile"
@@ -38,6 +49,31 @@ func TestNewWallet(t *testing.T) {
 	}
 }
 
+func TestNewWallet_ReservedRendition(t *testing.T) {
+	config := Config{
+		RepoPath:   "testdata/.textile-renditions",
+		Renditions: []model.Rendition{{Name: "meta", Width: 100}},
+	}
+	if _, _, err := NewWallet(config); err == nil {
+		t.Error("create wallet with a reserved rendition name should fail")
+	}
+}
+
+func TestParseRenditions(t *testing.T) {
+	renditions, err := ParseRenditions("small:320, square:100:square")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(renditions) != 2 || renditions[0].Width != 320 || !renditions[1].Square {
+		t.Errorf("bad renditions: %+v", renditions)
+	}
+	for _, spec := range []string{"small", "small:big", "thumb:100", "small:100,small:200", "../x:100", "small:0"} {
+		if _, err := ParseRenditions(spec); err == nil {
+			t.Errorf("parse renditions %s should fail", spec)
+		}
+	}
+}
+
 func TestWallet_StartWallet(t *testing.T) {
 	if err := wallet.Start(); err != nil {
 		t.Errorf("start wallet failed: %s", err)
@@ -104,6 +140,53 @@ func TestWallet_GetUsername(t *testing.T) {
 	// TODO
 }
 
+func TestWallet_SetProfileField(t *testing.T) {
+	if err := wallet.SetProfileField("bio", "testing"); err != nil {
+		t.Error(err)
+		return
+	}
+	if err := wallet.SetProfileField("Not A Key", "testing"); err == nil {
+		t.Error("set profile field with bad key should fail")
+	}
+	if err := wallet.SetProfileField("threads", "nope"); err == nil {
+		t.Error("set profile field with unknown thread should fail")
+	}
+	pid, err := wallet.GetId()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	prof, err := wallet.GetProfile(pid)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if prof.Fields["bio"] != "testing" {
+		t.Error("profile field was not set")
+	}
+}
+
+func TestWallet_SetAvatar(t *testing.T) {
+	id, err := wallet.SetAvatar("../util/testdata/image.jpg")
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	for _, size := range model.AvatarSizes {
+		if _, err := wallet.GetDataAtPath(fmt.Sprintf("%s/%d", id, size)); err != nil {
+			t.Errorf("missing avatar size %d: %s", size, err)
+		}
+	}
+	avatar, err := wallet.GetAvatar()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if avatar != id {
+		t.Error("avatar was not saved")
+	}
+}
+
 func TestWallet_GetId(t *testing.T) {
 	// TODO
 }
@@ -171,6 +254,96 @@ func TestWallet_AddThreadWithMnemonic(t *testing.T) {
 	// TODO
 }
 
+func TestWallet_AddDerivedThread(t *testing.T) {
+	first, err := wallet.AddDerivedThread("derived1")
+	if err != nil {
+		t.Errorf("add derived thread failed: %s", err)
+		return
+	}
+	second, err := wallet.AddDerivedThread("derived2")
+	if err != nil {
+		t.Errorf("add derived thread failed: %s", err)
+		return
+	}
+	if first.Id == second.Id {
+		t.Error("derived threads share a key")
+	}
+	sk, err := wallet.DeriveKey(tutil.ThreadKey, 0)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if !sk.Equals(first.PrivKey) {
+		t.Error("first derived thread does not use index 0")
+	}
+}
+
+func TestWallet_CreateExternalInvite(t *testing.T) {
+	thrd, err := wallet.AddDerivedThread("invites")
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	invite, err := wallet.CreateExternalInvite(thrd.Id, time.Hour, 2)
+	if err != nil {
+		t.Errorf("create external invite failed: %s", err)
+		return
+	}
+	if !invite.Expires.After(time.Now()) || invite.MaxUses != 2 {
+		t.Error("external invite has bad expiry or max uses")
+	}
+	id, key, err := ParseExternalInviteLink(invite.Link)
+	if err != nil {
+		t.Errorf("parse invite link failed: %s", err)
+		return
+	}
+	if id != invite.Id || string(key) != invite.Key {
+		t.Error("parsed invite link does not match invite")
+	}
+	if err := thrd.ExternalInviteStatus(invite.Id); err != nil {
+		t.Errorf("new invite is not valid: %s", err)
+	}
+	if _, err := wallet.RevokeExternalInvite(invite.Id); err != nil {
+		t.Errorf("revoke external invite failed: %s", err)
+		return
+	}
+	if err := thrd.ExternalInviteStatus(invite.Id); err != thread.ErrInviteRevoked {
+		t.Errorf("revoked invite has status: %v", err)
+	}
+	if _, _, err := ParseExternalInviteLink("textile://pair?code=abc"); err == nil {
+		t.Error("parsed a bad invite link")
+	}
+}
+
+func TestWallet_Backup(t *testing.T) {
+	ciphertext, err := wallet.Backup()
+	if err != nil {
+		t.Errorf("backup failed: %s", err)
+		return
+	}
+	sk, err := wallet.GetPrivKey()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	plain, err := crypto.Decrypt(sk, ciphertext)
+	if err != nil {
+		t.Errorf("decrypt backup failed: %s", err)
+		return
+	}
+	backup := new(model.AccountBackup)
+	if err := json.Unmarshal(plain, backup); err != nil {
+		t.Error(err)
+		return
+	}
+	if len(backup.Threads) != len(wallet.Threads()) {
+		t.Error("backup is missing threads")
+	}
+	if backup.KeyIndexes[tutil.ThreadKey.String()] == 0 {
+		t.Error("backup is missing derived thread key indexes")
+	}
+}
+
 func TestWallet_RemoveThread(t *testing.T) {
 	// TODO
 }
@@ -187,10 +360,73 @@ func TestWallet_AddDevice(t *testing.T) {
 	// TODO
 }
 
+func TestWallet_NewPairingRequest(t *testing.T) {
+	req, err := wallet.NewPairingRequest()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if req.Code == "" {
+		t.Error("pairing request missing code")
+	}
+	if !strings.HasPrefix(req.Link, "textile://pair?") || !strings.Contains(req.Link, req.Code) {
+		t.Errorf("bad pairing link: %s", req.Link)
+	}
+	if !req.Expires.After(time.Now()) {
+		t.Error("pairing request already expired")
+	}
+}
+
+func TestWallet_PairDevice(t *testing.T) {
+	req, err := wallet.NewPairingRequest()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := wallet.PairDevice(req.Link, "self"); err == nil {
+		t.Error("pairing with self should fail")
+	}
+	if _, err := wallet.PairDevice("textile://pair?code=abc", "nope"); err == nil {
+		t.Error("pairing with bad link should fail")
+	}
+}
+
 func TestWallet_RemoveDevice(t *testing.T) {
 	// TODO
 }
 
+func TestWallet_AddContact(t *testing.T) {
+	_, pk, err := libp2pc.GenerateEd25519Key(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	contact, err := wallet.AddContact(pk)
+	if err != nil {
+		t.Fatal(err)
+	}
+	found, err := wallet.ResolveContact(contact.Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if found.Id != contact.Id {
+		t.Error("resolved wrong contact")
+	}
+	if len(wallet.Contacts()) != 1 {
+		t.Error("incorrect number of contacts")
+	}
+}
+
+func TestWallet_RemoveContact(t *testing.T) {
+	contacts := wallet.Contacts()
+	if len(contacts) == 0 {
+		t.Fatal("no contacts to remove")
+	}
+	if err := wallet.RemoveContact(contacts[0].Id); err != nil {
+		t.Error(err)
+	}
+	if _, err := wallet.ResolveContact(contacts[0].Id); err != ErrContactNotFound {
+		t.Error("contact was not removed")
+	}
+}
+
 func TestWallet_AddPhoto(t *testing.T) {
 	added, err := wallet.AddPhoto("../util/testdata/image.jpg")
 	if err != nil {
@@ -202,6 +438,192 @@ func TestWallet_AddPhoto(t *testing.T) {
 	}
 }
 
+func TestWallet_AddPhotoDuplicate(t *testing.T) {
+	first, err := wallet.AddPhoto("../util/testdata/image.jpg")
+	if err != nil {
+		t.Errorf("add photo failed: %s", err)
+		return
+	}
+	again, err := wallet.AddPhoto("../util/testdata/image.jpg")
+	if err != nil {
+		t.Errorf("add duplicate photo failed: %s", err)
+		return
+	}
+	if !again.Duplicate {
+		t.Error("duplicate photo was not reported")
+	}
+	if again.Id != first.Id || again.Key != first.Key {
+		t.Error("duplicate photo did not reuse the existing data")
+	}
+}
+
+func TestWallet_ImportPhotos(t *testing.T) {
+	sk, _, err := libp2pc.GenerateEd25519Key(rand.Reader)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	thrd, err := wallet.AddThread("import", sk)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	result, err := wallet.ImportPhotos("../util/testdata", thrd.Id, nil)
+	if err != nil {
+		t.Errorf("import photos failed: %s", err)
+		return
+	}
+	if result.Total != 2 || result.Added != 2 || len(result.Errors) != 0 {
+		t.Errorf("bad import result: %+v", result)
+	}
+	resumed, err := wallet.ImportPhotos("../util/testdata", thrd.Id, nil)
+	if err != nil {
+		t.Errorf("resume import failed: %s", err)
+		return
+	}
+	if resumed.Skipped != 2 || resumed.Added != 0 {
+		t.Errorf("resumed import did not skip imported photos: %+v", resumed)
+	}
+}
+
+func TestWallet_ImportPhotosDuplicates(t *testing.T) {
+	sk, _, err := libp2pc.GenerateEd25519Key(rand.Reader)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	thrd, err := wallet.AddThread("import dups", sk)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	data, err := ioutil.ReadFile("../util/testdata/image.jpg")
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	dir := "testdata/import"
+	defer os.RemoveAll(dir)
+	os.MkdirAll(dir, os.ModePerm)
+	for i := 0; i < 4; i++ {
+		if err := ioutil.WriteFile(fmt.Sprintf("%s/copy%d.jpg", dir, i), data, 0644); err != nil {
+			t.Error(err)
+			return
+		}
+	}
+	result, err := wallet.ImportPhotos(dir, thrd.Id, nil)
+	if err != nil {
+		t.Errorf("import photos failed: %s", err)
+		return
+	}
+	if result.Added != 1 || result.Skipped != 3 {
+		t.Errorf("identical files were not deduplicated: %+v", result)
+	}
+	if len(thrd.Blocks("", -1, rmodel.PhotoBlock)) != 1 {
+		t.Error("identical files added as separate photos")
+	}
+}
+
+func TestWallet_ExportPhotos(t *testing.T) {
+	sk, _, err := libp2pc.GenerateEd25519Key(rand.Reader)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	thrd, err := wallet.AddThread("export", sk)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	added, err := wallet.AddPhoto("../util/testdata/image.jpg")
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if _, err := thrd.AddPhoto(added.Id, "exported", []byte(added.Key)); err != nil {
+		t.Error(err)
+		return
+	}
+	dir := "testdata/export"
+	defer os.RemoveAll(dir)
+	result, err := wallet.ExportPhotos(thrd.Id, dir, nil)
+	if err != nil {
+		t.Errorf("export photos failed: %s", err)
+		return
+	}
+	if result.Exported != 1 || len(result.Errors) != 0 {
+		t.Errorf("bad export result: %+v", result)
+		return
+	}
+	if _, err := os.Stat(dir + "/image.jpg"); err != nil {
+		t.Errorf("exported photo missing: %s", err)
+	}
+	sidecar, err := ioutil.ReadFile(dir + "/image.jpg.json")
+	if err != nil {
+		t.Errorf("exported sidecar missing: %s", err)
+		return
+	}
+	var exported ExportedPhoto
+	if err := json.Unmarshal(sidecar, &exported); err != nil {
+		t.Error(err)
+		return
+	}
+	if exported.Id != added.Id || exported.Caption != "exported" {
+		t.Error("exported sidecar fields don't match")
+	}
+	info, err := os.Stat(dir + "/image.jpg")
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	mtime := exported.Date
+	if exported.Taken != nil {
+		mtime = *exported.Taken
+	}
+	if info.ModTime().Unix() != mtime.Unix() {
+		t.Errorf("exported photo mtime not restored: %s", info.ModTime())
+	}
+
+	// exporting again should not replace the first export
+	if _, err := wallet.ExportPhotos(thrd.Id, dir, nil); err != nil {
+		t.Error(err)
+		return
+	}
+	if _, err := os.Stat(dir + "/image-1.jpg"); err != nil {
+		t.Errorf("second export replaced the first: %s", err)
+	}
+}
+
+func TestWallet_Search(t *testing.T) {
+	sk, _, err := libp2pc.GenerateEd25519Key(rand.Reader)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	thrd, err := wallet.AddThread("search", sk)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	added, err := wallet.AddPhoto("../util/testdata/image.jpg")
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if _, err := thrd.AddPhoto(added.Id, "a searchable caption", []byte(added.Key)); err != nil {
+		t.Error(err)
+		return
+	}
+	results, err := wallet.Search("search", &rmodel.SearchFilters{ThreadId: thrd.Id})
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if len(results) != 1 || results[0].DataId != added.Id {
+		t.Error("search did not find photo by caption")
+	}
+}
+
 func TestWallet_GetBlock(t *testing.T) {
 	// TODO
 }
@@ -226,6 +648,33 @@ func TestWallet_Peers(t *testing.T) {
 	// TODO
 }
 
+func TestWallet_SyncOverlap(t *testing.T) {
+	started := make(chan struct{})
+	release := make(chan struct{})
+	ended := make(chan struct{})
+	var once sync.Once
+	go func() {
+		defer close(ended)
+		wallet.Sync(time.Second*10, func(prog *SyncProgress) {
+			once.Do(func() {
+				close(started)
+				<-release
+			})
+		})
+	}()
+	select {
+	case <-started:
+	case <-time.After(time.Second * 10):
+		t.Error("sync did not start")
+		return
+	}
+	if _, err := wallet.Sync(time.Second, nil); err != ErrSyncInProgress {
+		t.Errorf("overlapping sync should fail: %v", err)
+	}
+	close(release)
+	<-ended
+}
+
 func TestWallet_SignOut(t *testing.T) {
 	err := wallet.SignOut()
 	if err != nil {